@@ -26,6 +26,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
 	eventBridgeTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/eventbridge"
+	kinesisTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/kinesis"
 	sfnTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sfn"
 	snsTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sns"
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/spanpointers"
@@ -109,9 +110,13 @@ func (mw *traceMiddleware) startTraceMiddleware(stack *middleware.Stack) error {
 		// Inject trace context
 		switch serviceID {
 		case "SQS":
-			sqsTracer.EnrichOperation(span, in, operation)
+			sqsTracer.EnrichOperation(spanctx, span, &in, operation, mw.cfg.dataStreamsEnabled)
 		case "SNS":
-			snsTracer.EnrichOperation(span, in, operation)
+			snsTracer.EnrichOperation(spanctx, span, in, operation, mw.cfg.dataStreamsEnabled)
+		case "Kinesis":
+			if mw.cfg.kinesisInjection {
+				kinesisTracer.EnrichOperation(spanctx, span, &in, operation, mw.cfg.dataStreamsEnabled)
+			}
 		case "EventBridge":
 			eventBridgeTracer.EnrichOperation(span, in, operation)
 		case "SFN":
@@ -125,6 +130,16 @@ func (mw *traceMiddleware) startTraceMiddleware(stack *middleware.Stack) error {
 		if err != nil && (mw.cfg.errCheck == nil || mw.cfg.errCheck(err)) {
 			span.SetTag(ext.Error, err)
 		}
+
+		// Extract trace context from the received messages
+		if err == nil {
+			switch serviceID {
+			case "SQS":
+				sqsTracer.HandleOutput(spanctx, span, in, out, operation, mw.cfg.dataStreamsEnabled)
+			case "Kinesis":
+				kinesisTracer.HandleOutput(spanctx, span, in, out, operation, mw.cfg.dataStreamsEnabled)
+			}
+		}
 		span.Finish()
 
 		return out, metadata, err
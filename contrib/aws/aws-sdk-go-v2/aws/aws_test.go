@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -321,6 +322,70 @@ func TestAppendMiddlewareSqsSendMessage(t *testing.T) {
 	assert.NotEmpty(t, traceContext["x-datadog-parent-id"])
 }
 
+func TestAppendMiddlewareSqsReceiveMessageLinks(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	producer := tracer.StartSpan("producer")
+	carrier := tracer.TextMapCarrier{}
+	require.NoError(t, tracer.Inject(producer.Context(), carrier))
+	producer.Finish()
+	traceContext, err := json.Marshal(carrier)
+	require.NoError(t, err)
+
+	var attributeNames []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			MessageAttributeNames []string
+		}
+		json.NewDecoder(r.Body).Decode(&in)
+		attributeNames = in.MessageAttributeNames
+		w.Header().Set("X-Amz-RequestId", "test_req")
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(map[string]any{
+			"Messages": []any{map[string]any{
+				"MessageId": "1",
+				"Body":      "test message",
+				"MessageAttributes": map[string]any{
+					"_datadog": map[string]string{"DataType": "String", "StringValue": string(traceContext)},
+				},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	resolver := aws.EndpointResolverFunc(func(_, _ string) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			PartitionID:   "aws",
+			URL:           server.URL,
+			SigningRegion: "eu-west-1",
+		}, nil
+	})
+
+	awsCfg := aws.Config{
+		Region:           "eu-west-1",
+		Credentials:      aws.AnonymousCredentials{},
+		EndpointResolver: resolver,
+	}
+
+	AppendMiddleware(&awsCfg, WithDataStreams())
+
+	sqsClient := sqs.NewFromConfig(awsCfg)
+	out, err := sqsClient.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl: aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	})
+	require.NoError(t, err)
+	require.Len(t, out.Messages, 1)
+	assert.Equal(t, []string{"_datadog"}, attributeNames)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	links := spans[1].Links()
+	require.Len(t, links, 1)
+	assert.Equal(t, producer.Context().TraceIDLower(), links[0].TraceID)
+	assert.Equal(t, producer.Context().SpanID(), links[0].SpanID)
+}
+
 func TestAppendMiddlewareS3ListObjects(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -582,6 +647,67 @@ func TestAppendMiddlewareDynamodbGetItem(t *testing.T) {
 	}
 }
 
+func TestAppendMiddlewareKinesisRecordInjection(t *testing.T) {
+	for _, inject := range []bool{false, true} {
+		t.Run(fmt.Sprint(inject), func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			var data []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var in struct {
+					Data []byte
+				}
+				json.NewDecoder(r.Body).Decode(&in)
+				data = in.Data
+				w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+				w.Write([]byte(`{"SequenceNumber": "1", "ShardId": "shardId-000000000000"}`))
+			}))
+			defer server.Close()
+
+			resolver := aws.EndpointResolverFunc(func(_, _ string) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					PartitionID:   "aws",
+					URL:           server.URL,
+					SigningRegion: "eu-west-1",
+				}, nil
+			})
+			awsCfg := aws.Config{
+				Region:           "eu-west-1",
+				Credentials:      aws.AnonymousCredentials{},
+				EndpointResolver: resolver,
+			}
+			var opts []Option
+			if inject {
+				opts = append(opts, WithKinesisRecordInjection())
+			}
+			AppendMiddleware(&awsCfg, opts...)
+
+			record := []byte(`{"message":"Hello, Kinesis!"}`)
+			kinesisClient := kinesis.NewFromConfig(awsCfg)
+			_, err := kinesisClient.PutRecord(context.Background(), &kinesis.PutRecordInput{
+				StreamName:   aws.String("my-kinesis-stream"),
+				Data:         record,
+				PartitionKey: aws.String("my-partition-key"),
+			})
+			require.NoError(t, err)
+
+			if !inject {
+				// the record data is left untouched by default
+				assert.Equal(t, record, data)
+				return
+			}
+			var got struct {
+				Message string
+				Datadog tracer.TextMapCarrier `json:"_datadog"`
+			}
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, "Hello, Kinesis!", got.Message)
+			assert.NotEmpty(t, got.Datadog["x-datadog-trace-id"])
+		})
+	}
+}
+
 func TestAppendMiddlewareKinesisPutRecord(t *testing.T) {
 	tests := []struct {
 		name               string
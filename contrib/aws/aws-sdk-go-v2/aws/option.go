@@ -10,9 +10,11 @@ import (
 )
 
 type config struct {
-	serviceName   string
-	analyticsRate float64
-	errCheck      func(err error) bool
+	serviceName        string
+	analyticsRate      float64
+	errCheck           func(err error) bool
+	dataStreamsEnabled bool
+	kinesisInjection   bool
 }
 
 // Option describes options for the AWS integration.
@@ -29,6 +31,7 @@ func (fn OptionFn) apply(cfg *config) {
 
 func defaults(cfg *config) {
 	cfg.analyticsRate = instr.AnalyticsRate(false)
+	cfg.dataStreamsEnabled = instr.DataStreamsEnabled()
 }
 
 // WithService sets the given service name for the dialled connection.
@@ -71,3 +74,25 @@ func WithErrorCheck(fn func(err error) bool) OptionFn {
 		cfg.errCheck = fn
 	}
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+// Checkpoints are set for the messages sent to and received from SQS, published
+// to SNS, and read from Kinesis. Checkpoints are set for the records put to
+// Kinesis only when WithKinesisRecordInjection is also used.
+func WithDataStreams() OptionFn {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
+
+// WithKinesisRecordInjection enables the propagation of the trace context in
+// the records put to Kinesis with PutRecord and PutRecords. Kinesis records
+// have no attributes, so the trace context is added to the record data as a
+// "_datadog" field. This rewrites the data of the records which hold a JSON
+// object, and consumers must tolerate the extra field. Other records are left
+// unchanged. It's disabled by default.
+func WithKinesisRecordInjection() OptionFn {
+	return func(cfg *config) {
+		cfg.kinesisInjection = true
+	}
+}
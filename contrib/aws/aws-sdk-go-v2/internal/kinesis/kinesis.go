@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kinesis
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	datadogKey   = "_datadog"
+	maxSizeBytes = 1024 * 1024 // 1 MB
+	// pathwaySizeBytes is the room kept in the record for the Data Streams
+	// Monitoring pathway, which is added to the trace context once the record
+	// is known to fit.
+	pathwaySizeBytes = 128
+	dataStreamsType  = "kinesis"
+)
+
+var instr = internal.Instr
+
+// EnrichOperation injects the trace context of span into the records put with
+// PutRecord and PutRecords, setting a Data Streams Monitoring checkpoint for
+// each record if dataStreams is true. The trace context is only injected into
+// records holding a JSON object, as a "_datadog" field, which rewrites the
+// record data. The parameters of in are replaced with a copy holding the
+// rewritten records, so that the caller's input is left untouched. It's only
+// called when the integration is configured with WithKinesisRecordInjection.
+func EnrichOperation(ctx context.Context, span *tracer.Span, in *middleware.InitializeInput, operation string, dataStreams bool) {
+	switch operation {
+	case "PutRecord":
+		handlePutRecord(ctx, span, in, dataStreams)
+	case "PutRecords":
+		handlePutRecords(ctx, span, in, dataStreams)
+	}
+}
+
+// HandleOutput links span to the spans which put the records received with
+// GetRecords, and sets a Data Streams Monitoring checkpoint for each record if
+// dataStreams is true. The checkpoints are only set when the stream is known,
+// that is when GetRecords is called with the stream ARN: the shard iterator
+// doesn't tell it.
+func HandleOutput(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, out middleware.InitializeOutput, operation string, dataStreams bool) {
+	if operation != "GetRecords" {
+		return
+	}
+	params, ok := in.Parameters.(*kinesis.GetRecordsInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read GetRecords params")
+		return
+	}
+	res, ok := out.Result.(*kinesis.GetRecordsOutput)
+	if !ok {
+		instr.Logger().Debug("Unable to read GetRecords output")
+		return
+	}
+	stream := streamName(nil, params.StreamARN)
+	for _, r := range res.Records {
+		carrier, ok := extractCarrier(r.Data)
+		if !ok {
+			continue
+		}
+		if sctx, err := tracer.Extract(carrier); err == nil {
+			span.AddLink(messaging.SpanLink(sctx))
+		}
+		if dataStreams && stream != "" {
+			messaging.SetConsumeCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: stream, Carrier: carrier, PayloadSize: int64(len(r.Data))})
+		}
+	}
+}
+
+func handlePutRecord(ctx context.Context, span *tracer.Span, in *middleware.InitializeInput, dataStreams bool) {
+	params, ok := in.Parameters.(*kinesis.PutRecordInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read PutRecord params")
+		return
+	}
+	stream := streamName(params.StreamName, params.StreamARN)
+	p := *params
+	p.Data = injectTraceContext(ctx, span, params.Data, stream, dataStreams)
+	in.Parameters = &p
+}
+
+func handlePutRecords(ctx context.Context, span *tracer.Span, in *middleware.InitializeInput, dataStreams bool) {
+	params, ok := in.Parameters.(*kinesis.PutRecordsInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read PutRecords params")
+		return
+	}
+	stream := streamName(params.StreamName, params.StreamARN)
+	p := *params
+	p.Records = slices.Clone(params.Records)
+	for i := range p.Records {
+		p.Records[i].Data = injectTraceContext(ctx, span, p.Records[i].Data, stream, dataStreams)
+	}
+	in.Parameters = &p
+}
+
+// injectTraceContext returns data with the trace context of span added as the
+// "_datadog" field, if data is a JSON object. Otherwise, data is returned
+// unchanged. The Data Streams Monitoring checkpoint is only set once the
+// record is known to have room for the trace context.
+func injectTraceContext(ctx context.Context, span *tracer.Span, data []byte, stream string, dataStreams bool) []byte {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(data, &record); err != nil || record == nil {
+		instr.Logger().Debug("Cannot inject trace context: record data is not a JSON object")
+		return data
+	}
+	carrier := tracer.TextMapCarrier{}
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		instr.Logger().Debug("Unable to inject trace context: %s", err.Error())
+		return data
+	}
+	b, err := marshalRecord(record, carrier)
+	if err != nil {
+		instr.Logger().Debug("Unable to marshal record data: %s", err.Error())
+		return data
+	}
+	if len(b)+pathwaySizeBytes > maxSizeBytes {
+		instr.Logger().Info("Cannot inject trace context: record size would exceed %d bytes", maxSizeBytes)
+		return data
+	}
+	if !dataStreams || stream == "" {
+		return b
+	}
+	messaging.SetProduceCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: stream, Carrier: carrier, PayloadSize: int64(len(data))})
+	if b, err = marshalRecord(record, carrier); err != nil || len(b) > maxSizeBytes {
+		instr.Logger().Info("Cannot inject trace context: record size would exceed %d bytes", maxSizeBytes)
+		return data
+	}
+	return b
+}
+
+// marshalRecord returns the record with carrier set as its "_datadog" field.
+func marshalRecord(record map[string]json.RawMessage, carrier tracer.TextMapCarrier) ([]byte, error) {
+	traceContext, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, err
+	}
+	record[datadogKey] = traceContext
+	return json.Marshal(record)
+}
+
+// extractCarrier returns the trace context carrier injected into the record
+// data, if any.
+func extractCarrier(data []byte) (tracer.TextMapCarrier, bool) {
+	if !strings.Contains(string(data), datadogKey) {
+		return nil, false
+	}
+	var record struct {
+		Datadog tracer.TextMapCarrier `json:"_datadog"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil || len(record.Datadog) == 0 {
+		return nil, false
+	}
+	return record.Datadog, true
+}
+
+// streamName returns the name of the stream, taken from its ARN if the name
+// isn't set, or an empty string if neither is set.
+func streamName(name, arn *string) string {
+	if name != nil {
+		return *name
+	}
+	a := aws.ToString(arn)
+	return a[strings.LastIndex(a, "/")+1:]
+}
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kinesis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func TestEnrichOperation(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "test-span")
+	defer span.Finish()
+
+	t.Run("PutRecord", func(t *testing.T) {
+		params := &kinesis.PutRecordInput{
+			StreamName:   aws.String("test-stream"),
+			Data:         []byte(`{"id":1}`),
+			PartitionKey: aws.String("key"),
+		}
+		in := middleware.InitializeInput{Parameters: params}
+		EnrichOperation(ctx, span, &in, "PutRecord", true)
+
+		// the caller's input is left untouched
+		assert.Equal(t, []byte(`{"id":1}`), params.Data)
+		data := in.Parameters.(*kinesis.PutRecordInput).Data
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(data, &record))
+		assert.Equal(t, float64(1), record["id"])
+		carrier, ok := extractCarrier(data)
+		require.True(t, ok)
+		sctx, err := tracer.Extract(carrier)
+		require.NoError(t, err)
+		assert.Equal(t, span.Context().SpanID(), sctx.SpanID())
+
+		got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+		require.True(t, ok)
+		dsCtx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:test-stream", "type:kinesis")
+		want, _ := datastreams.PathwayFromContext(dsCtx)
+		assert.Equal(t, want.GetHash(), got.GetHash())
+	})
+
+	t.Run("PutRecords", func(t *testing.T) {
+		params := &kinesis.PutRecordsInput{
+			StreamARN: aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/test-stream"),
+			Records: []types.PutRecordsRequestEntry{
+				{Data: []byte(`{"id":1}`), PartitionKey: aws.String("key")},
+				{Data: []byte("not json"), PartitionKey: aws.String("key")},
+				{Data: []byte(`["not", "an", "object"]`), PartitionKey: aws.String("key")},
+				{Data: []byte(`{"big":"` + strings.Repeat("x", maxSizeBytes) + `"}`), PartitionKey: aws.String("key")},
+			},
+		}
+		in := middleware.InitializeInput{Parameters: params}
+		EnrichOperation(ctx, span, &in, "PutRecords", false)
+
+		// the caller's input is left untouched
+		assert.Equal(t, []byte(`{"id":1}`), params.Records[0].Data)
+		records := in.Parameters.(*kinesis.PutRecordsInput).Records
+		_, ok := extractCarrier(records[0].Data)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("not json"), records[1].Data)
+		assert.Equal(t, []byte(`["not", "an", "object"]`), records[2].Data)
+		_, ok = extractCarrier(records[3].Data)
+		assert.False(t, ok, "the record size limit is exceeded")
+	})
+}
+
+func TestHandleOutput(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	producer, pctx := tracer.StartSpanFromContext(context.Background(), "producer")
+	put := middleware.InitializeInput{Parameters: &kinesis.PutRecordInput{
+		StreamName: aws.String("test-stream"),
+		Data:       []byte(`{"id":1}`),
+	}}
+	EnrichOperation(pctx, producer, &put, "PutRecord", true)
+	producer.Finish()
+	data := put.Parameters.(*kinesis.PutRecordInput).Data
+
+	for name, arn := range map[string]*string{
+		"stream-arn": aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/test-stream"),
+		// the checkpoint is skipped, as the stream is unknown
+		"shard-iterator": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			consumer, cctx := tracer.StartSpanFromContext(context.Background(), "consumer")
+			in := middleware.InitializeInput{Parameters: &kinesis.GetRecordsInput{
+				ShardIterator: aws.String("iterator"),
+				StreamARN:     arn,
+			}}
+			out := middleware.InitializeOutput{Result: &kinesis.GetRecordsOutput{
+				Records: []types.Record{{Data: data}, {Data: []byte("untraced")}},
+			}}
+			HandleOutput(cctx, consumer, in, out, "GetRecords", true)
+			consumer.Finish()
+
+			spans := mt.FinishedSpans()
+			links := spans[len(spans)-1].Links()
+			require.Len(t, links, 1)
+			assert.Equal(t, producer.Context().TraceIDLower(), links[0].TraceID)
+			assert.Equal(t, producer.Context().SpanID(), links[0].SpanID)
+		})
+	}
+}
+
+func TestStreamName(t *testing.T) {
+	assert.Equal(t, "name", streamName(aws.String("name"), aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/other")))
+	assert.Equal(t, "test-stream", streamName(nil, aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/test-stream")))
+	assert.Equal(t, "", streamName(nil, nil))
+}
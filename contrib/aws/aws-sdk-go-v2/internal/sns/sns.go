@@ -6,10 +6,14 @@
 package sns
 
 import (
+	"context"
 	"encoding/json"
+	"maps"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
@@ -19,27 +23,46 @@ import (
 const (
 	datadogKey           = "_datadog"
 	maxMessageAttributes = 10
+	dataStreamsType      = "sns"
 )
 
 var instr = internal.Instr
 
-func EnrichOperation(span *tracer.Span, in middleware.InitializeInput, operation string) {
+// EnrichOperation injects the trace context of span into the messages
+// published with Publish and PublishBatch, setting a Data Streams Monitoring
+// checkpoint for each message if dataStreams is true.
+func EnrichOperation(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, operation string, dataStreams bool) {
 	switch operation {
 	case "Publish":
-		handlePublish(span, in)
+		handlePublish(ctx, span, in, dataStreams)
 	case "PublishBatch":
-		handlePublishBatch(span, in)
+		handlePublishBatch(ctx, span, in, dataStreams)
 	}
 }
 
-func handlePublish(span *tracer.Span, in middleware.InitializeInput) {
+func handlePublish(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, dataStreams bool) {
 	params, ok := in.Parameters.(*sns.PublishInput)
 	if !ok {
 		instr.Logger().Debug("Unable to read PublishInput params")
 		return
 	}
+	if !hasRoom(params.MessageAttributes) {
+		return
+	}
 
-	traceContext, err := getTraceContext(span)
+	carrier, err := getCarrier(span)
+	if err != nil {
+		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+		return
+	}
+	if dataStreams {
+		topic := aws.ToString(params.TopicArn)
+		if topic == "" {
+			topic = aws.ToString(params.TargetArn)
+		}
+		messaging.SetProduceCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: arnName(topic), Carrier: carrier, PayloadSize: int64(len(aws.ToString(params.Message)))})
+	}
+	traceContext, err := carrierAttribute(carrier)
 	if err != nil {
 		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
 		return
@@ -52,34 +75,55 @@ func handlePublish(span *tracer.Span, in middleware.InitializeInput) {
 	injectTraceContext(traceContext, params.MessageAttributes)
 }
 
-func handlePublishBatch(span *tracer.Span, in middleware.InitializeInput) {
+func handlePublishBatch(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, dataStreams bool) {
 	params, ok := in.Parameters.(*sns.PublishBatchInput)
 	if !ok {
 		instr.Logger().Debug("Unable to read PublishBatch params")
 		return
 	}
 
-	traceContext, err := getTraceContext(span)
+	carrier, err := getCarrier(span)
+	if err != nil {
+		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+		return
+	}
+	traceContext, err := carrierAttribute(carrier)
 	if err != nil {
 		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
 		return
 	}
 
+	topic := arnName(aws.ToString(params.TopicArn))
 	for i := range params.PublishBatchRequestEntries {
-		if params.PublishBatchRequestEntries[i].MessageAttributes == nil {
-			params.PublishBatchRequestEntries[i].MessageAttributes = make(map[string]types.MessageAttributeValue)
+		entry := &params.PublishBatchRequestEntries[i]
+		if !hasRoom(entry.MessageAttributes) {
+			continue
+		}
+		if entry.MessageAttributes == nil {
+			entry.MessageAttributes = make(map[string]types.MessageAttributeValue)
 		}
-		injectTraceContext(traceContext, params.PublishBatchRequestEntries[i].MessageAttributes)
+		if dataStreams {
+			// each message gets its own checkpoint
+			c := maps.Clone(carrier)
+			messaging.SetProduceCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: topic, Carrier: c, PayloadSize: int64(len(aws.ToString(entry.Message)))})
+			if traceContext, err = carrierAttribute(c); err != nil {
+				instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+				continue
+			}
+		}
+		injectTraceContext(traceContext, entry.MessageAttributes)
 	}
 }
 
-func getTraceContext(span *tracer.Span) (types.MessageAttributeValue, error) {
+func getCarrier(span *tracer.Span) (tracer.TextMapCarrier, error) {
 	carrier := tracer.TextMapCarrier{}
-	err := tracer.Inject(span.Context(), carrier)
-	if err != nil {
-		return types.MessageAttributeValue{}, err
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		return nil, err
 	}
+	return carrier, nil
+}
 
+func carrierAttribute(carrier tracer.TextMapCarrier) (types.MessageAttributeValue, error) {
 	jsonBytes, err := json.Marshal(carrier)
 	if err != nil {
 		return types.MessageAttributeValue{}, err
@@ -95,14 +139,27 @@ func getTraceContext(span *tracer.Span) (types.MessageAttributeValue, error) {
 	return attribute, nil
 }
 
-func injectTraceContext(traceContext types.MessageAttributeValue, messageAttributes map[string]types.MessageAttributeValue) {
-	// SNS only allows a maximum of 10 message attributes.
-	// https://docs.aws.amazon.com/sns/latest/dg/sns-message-attributes.html
-	// Only inject if there's room.
+// hasRoom reports whether the trace context can be added to the message
+// attributes. SNS only allows a maximum of 10 message attributes, so neither
+// the trace context nor the Data Streams Monitoring checkpoint are set on
+// messages which already have that many.
+// https://docs.aws.amazon.com/sns/latest/dg/sns-message-attributes.html
+func hasRoom(messageAttributes map[string]types.MessageAttributeValue) bool {
 	if len(messageAttributes) >= maxMessageAttributes {
 		instr.Logger().Info("Cannot inject trace context: message already has maximum allowed attributes")
-		return
+		return false
 	}
+	return true
+}
 
+func injectTraceContext(traceContext types.MessageAttributeValue, messageAttributes map[string]types.MessageAttributeValue) {
+	if !hasRoom(messageAttributes) {
+		return
+	}
 	messageAttributes[datadogKey] = traceContext
 }
+
+// arnName returns the name of the resource identified by arn.
+func arnName(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
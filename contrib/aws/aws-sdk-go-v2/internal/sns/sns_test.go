@@ -103,7 +103,7 @@ func TestEnrichOperation(t *testing.T) {
 			ctx := context.Background()
 			span := tt.setup(ctx)
 
-			EnrichOperation(span, tt.input, tt.operation)
+			EnrichOperation(ctx, span, tt.input, tt.operation, false)
 
 			if tt.check != nil {
 				tt.check(t, tt.input)
@@ -150,7 +150,9 @@ func TestInjectTraceContext(t *testing.T) {
 				}
 			}
 
-			traceContext, err := getTraceContext(span)
+			carrier, err := getCarrier(span)
+			assert.NoError(t, err)
+			traceContext, err := carrierAttribute(carrier)
 			assert.NoError(t, err)
 			injectTraceContext(traceContext, messageAttributes)
 
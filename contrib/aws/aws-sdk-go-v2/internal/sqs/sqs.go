@@ -6,10 +6,16 @@
 package sqs
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
@@ -19,27 +25,90 @@ import (
 const (
 	datadogKey           = "_datadog"
 	maxMessageAttributes = 10
+	dataStreamsType      = "sqs"
 )
 
 var instr = internal.Instr
 
-func EnrichOperation(span *tracer.Span, in middleware.InitializeInput, operation string) {
+// EnrichOperation injects the trace context of span into the messages sent
+// with SendMessage and SendMessageBatch, setting a Data Streams Monitoring
+// checkpoint for each message if dataStreams is true. It requests the trace
+// context attribute of the messages received with ReceiveMessage, replacing
+// the parameters of in with a copy so that the caller's input is left
+// untouched.
+func EnrichOperation(ctx context.Context, span *tracer.Span, in *middleware.InitializeInput, operation string, dataStreams bool) {
 	switch operation {
 	case "SendMessage":
-		handleSendMessage(span, in)
+		handleSendMessage(ctx, span, *in, dataStreams)
 	case "SendMessageBatch":
-		handleSendMessageBatch(span, in)
+		handleSendMessageBatch(ctx, span, *in, dataStreams)
+	case "ReceiveMessage":
+		handleReceiveMessage(in)
 	}
 }
 
-func handleSendMessage(span *tracer.Span, in middleware.InitializeInput) {
+// HandleOutput links span to the spans which sent the messages received with
+// ReceiveMessage, and sets a Data Streams Monitoring checkpoint for each
+// message if dataStreams is true.
+func HandleOutput(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, out middleware.InitializeOutput, operation string, dataStreams bool) {
+	if operation != "ReceiveMessage" {
+		return
+	}
+	params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read ReceiveMessage params")
+		return
+	}
+	res, ok := out.Result.(*sqs.ReceiveMessageOutput)
+	if !ok {
+		instr.Logger().Debug("Unable to read ReceiveMessage output")
+		return
+	}
+	queue := queueName(params.QueueUrl)
+	for _, msg := range res.Messages {
+		carrier, ok := extractCarrier(msg)
+		if !ok {
+			continue
+		}
+		if sctx, err := tracer.Extract(carrier); err == nil {
+			span.AddLink(messaging.SpanLink(sctx))
+		}
+		if dataStreams {
+			messaging.SetConsumeCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: queue, Carrier: carrier, PayloadSize: messageSize(msg)})
+		}
+	}
+}
+
+// ExtractContext returns the span context injected into msg by the sender,
+// either in its message attributes, or in the attributes of the SNS
+// notification it wraps.
+func ExtractContext(msg types.Message) (*tracer.SpanContext, error) {
+	carrier, ok := extractCarrier(msg)
+	if !ok {
+		return nil, tracer.ErrSpanContextNotFound
+	}
+	return tracer.Extract(carrier)
+}
+
+func handleSendMessage(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, dataStreams bool) {
 	params, ok := in.Parameters.(*sqs.SendMessageInput)
 	if !ok {
 		instr.Logger().Debug("Unable to read SendMessage params")
 		return
 	}
+	if !hasRoom(params.MessageAttributes) {
+		return
+	}
 
-	traceContext, err := getTraceContext(span)
+	carrier, err := getCarrier(span)
+	if err != nil {
+		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+		return
+	}
+	if dataStreams {
+		messaging.SetProduceCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: queueName(params.QueueUrl), Carrier: carrier, PayloadSize: int64(len(aws.ToString(params.MessageBody)))})
+	}
+	traceContext, err := carrierAttribute(carrier)
 	if err != nil {
 		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
 		return
@@ -52,34 +121,74 @@ func handleSendMessage(span *tracer.Span, in middleware.InitializeInput) {
 	injectTraceContext(traceContext, params.MessageAttributes)
 }
 
-func handleSendMessageBatch(span *tracer.Span, in middleware.InitializeInput) {
+func handleSendMessageBatch(ctx context.Context, span *tracer.Span, in middleware.InitializeInput, dataStreams bool) {
 	params, ok := in.Parameters.(*sqs.SendMessageBatchInput)
 	if !ok {
 		instr.Logger().Debug("Unable to read SendMessageBatch params")
 		return
 	}
 
-	traceContext, err := getTraceContext(span)
+	carrier, err := getCarrier(span)
+	if err != nil {
+		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+		return
+	}
+	traceContext, err := carrierAttribute(carrier)
 	if err != nil {
 		instr.Logger().Debug("Unable to get trace context: %s", err.Error())
 		return
 	}
 
+	queue := queueName(params.QueueUrl)
 	for i := range params.Entries {
-		if params.Entries[i].MessageAttributes == nil {
-			params.Entries[i].MessageAttributes = make(map[string]types.MessageAttributeValue)
+		entry := &params.Entries[i]
+		if !hasRoom(entry.MessageAttributes) {
+			continue
+		}
+		if entry.MessageAttributes == nil {
+			entry.MessageAttributes = make(map[string]types.MessageAttributeValue)
 		}
-		injectTraceContext(traceContext, params.Entries[i].MessageAttributes)
+		if dataStreams {
+			// each message gets its own checkpoint
+			c := maps.Clone(carrier)
+			messaging.SetProduceCheckpoint(ctx, dataStreamsType, messaging.Message{Destination: queue, Carrier: c, PayloadSize: int64(len(aws.ToString(entry.MessageBody)))})
+			if traceContext, err = carrierAttribute(c); err != nil {
+				instr.Logger().Debug("Unable to get trace context: %s", err.Error())
+				continue
+			}
+		}
+		injectTraceContext(traceContext, entry.MessageAttributes)
 	}
 }
 
-func getTraceContext(span *tracer.Span) (types.MessageAttributeValue, error) {
+// handleReceiveMessage requests the trace context attribute of the received
+// messages, if it's not already requested. The request uses a copy of the
+// parameters, as they belong to the caller.
+func handleReceiveMessage(in *middleware.InitializeInput) {
+	params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read ReceiveMessage params")
+		return
+	}
+	for _, name := range params.MessageAttributeNames {
+		if name == datadogKey || name == "All" || name == ".*" {
+			return
+		}
+	}
+	p := *params
+	p.MessageAttributeNames = append(slices.Clip(params.MessageAttributeNames), datadogKey)
+	in.Parameters = &p
+}
+
+func getCarrier(span *tracer.Span) (tracer.TextMapCarrier, error) {
 	carrier := tracer.TextMapCarrier{}
-	err := tracer.Inject(span.Context(), carrier)
-	if err != nil {
-		return types.MessageAttributeValue{}, err
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		return nil, err
 	}
+	return carrier, nil
+}
 
+func carrierAttribute(carrier tracer.TextMapCarrier) (types.MessageAttributeValue, error) {
 	jsonBytes, err := json.Marshal(carrier)
 	if err != nil {
 		return types.MessageAttributeValue{}, err
@@ -93,14 +202,90 @@ func getTraceContext(span *tracer.Span) (types.MessageAttributeValue, error) {
 	return attribute, nil
 }
 
-func injectTraceContext(traceContext types.MessageAttributeValue, messageAttributes map[string]types.MessageAttributeValue) {
-	// SQS only allows a maximum of 10 message attributes.
-	// https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-message-metadata.html#sqs-message-attributes
-	// Only inject if there's room.
+// hasRoom reports whether the trace context can be added to the message
+// attributes. SQS only allows a maximum of 10 message attributes, so neither
+// the trace context nor the Data Streams Monitoring checkpoint are set on
+// messages which already have that many.
+// https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-message-metadata.html#sqs-message-attributes
+func hasRoom(messageAttributes map[string]types.MessageAttributeValue) bool {
 	if len(messageAttributes) >= maxMessageAttributes {
 		instr.Logger().Info("Cannot inject trace context: message already has maximum allowed attributes")
-		return
+		return false
 	}
+	return true
+}
 
+func injectTraceContext(traceContext types.MessageAttributeValue, messageAttributes map[string]types.MessageAttributeValue) {
+	if !hasRoom(messageAttributes) {
+		return
+	}
 	messageAttributes[datadogKey] = traceContext
 }
+
+// snsNotification is the body of the SQS messages delivered by an SNS
+// subscription without raw message delivery.
+type snsNotification struct {
+	Type              string `json:"Type"`
+	MessageAttributes map[string]struct {
+		Type  string `json:"Type"`
+		Value string `json:"Value"`
+	} `json:"MessageAttributes"`
+}
+
+// extractCarrier returns the trace context carrier injected into msg, either
+// as a message attribute, or as an attribute of the SNS notification held in
+// its body.
+func extractCarrier(msg types.Message) (tracer.TextMapCarrier, bool) {
+	var data []byte
+	if attr, ok := msg.MessageAttributes[datadogKey]; ok {
+		if attr.StringValue != nil {
+			data = []byte(*attr.StringValue)
+		} else {
+			// SNS raw message delivery keeps the binary attribute
+			data = attr.BinaryValue
+		}
+	} else if body := aws.ToString(msg.Body); strings.Contains(body, datadogKey) {
+		var n snsNotification
+		if err := json.Unmarshal([]byte(body), &n); err != nil || n.Type != "Notification" {
+			return nil, false
+		}
+		attr, ok := n.MessageAttributes[datadogKey]
+		if !ok {
+			return nil, false
+		}
+		switch attr.Type {
+		case "Binary":
+			b, err := base64.StdEncoding.DecodeString(attr.Value)
+			if err != nil {
+				instr.Logger().Debug("Unable to decode trace context: %s", err.Error())
+				return nil, false
+			}
+			data = b
+		default:
+			data = []byte(attr.Value)
+		}
+	}
+	if len(data) == 0 {
+		return nil, false
+	}
+	var carrier tracer.TextMapCarrier
+	if err := json.Unmarshal(data, &carrier); err != nil {
+		instr.Logger().Debug("Unable to unmarshal trace context: %s", err.Error())
+		return nil, false
+	}
+	return carrier, true
+}
+
+// messageSize returns the size of the body and attributes of msg.
+func messageSize(msg types.Message) int64 {
+	size := len(aws.ToString(msg.Body))
+	for k, v := range msg.MessageAttributes {
+		size += len(k) + len(aws.ToString(v.StringValue)) + len(v.BinaryValue)
+	}
+	return int64(size)
+}
+
+func queueName(queueURL *string) string {
+	u := aws.ToString(queueURL)
+	return u[strings.LastIndex(u, "/")+1:]
+}
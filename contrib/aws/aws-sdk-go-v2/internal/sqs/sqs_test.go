@@ -7,12 +7,14 @@ package sqs
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -108,7 +110,7 @@ func TestEnrichOperation(t *testing.T) {
 			ctx := context.Background()
 			span := tt.setup(ctx)
 
-			EnrichOperation(span, tt.input, tt.operation)
+			EnrichOperation(ctx, span, &tt.input, tt.operation, false)
 
 			if tt.check != nil {
 				tt.check(t, tt.input)
@@ -155,7 +157,9 @@ func TestInjectTraceContext(t *testing.T) {
 				}
 			}
 
-			traceContext, err := getTraceContext(span)
+			carrier, err := getCarrier(span)
+			assert.NoError(t, err)
+			traceContext, err := carrierAttribute(carrier)
 			assert.NoError(t, err)
 			injectTraceContext(traceContext, messageAttributes)
 
@@ -180,3 +184,153 @@ func TestInjectTraceContext(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractContext(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span := tracer.StartSpan("test-span")
+	spanCarrier, err := getCarrier(span)
+	require.NoError(t, err)
+	traceContext, err := carrierAttribute(spanCarrier)
+	require.NoError(t, err)
+	carrier := *traceContext.StringValue
+
+	tests := []struct {
+		name string
+		msg  types.Message
+	}{
+		{
+			name: "message attribute",
+			msg: types.Message{
+				Body:              aws.String("test message"),
+				MessageAttributes: map[string]types.MessageAttributeValue{datadogKey: traceContext},
+			},
+		},
+		{
+			name: "SNS raw message delivery",
+			msg: types.Message{
+				Body: aws.String("test message"),
+				MessageAttributes: map[string]types.MessageAttributeValue{datadogKey: {
+					DataType:    aws.String("Binary"),
+					BinaryValue: []byte(carrier),
+				}},
+			},
+		},
+		{
+			name: "SNS notification with string attribute",
+			msg:  types.Message{Body: aws.String(snsNotificationBody(t, "String", carrier))},
+		},
+		{
+			name: "SNS notification with binary attribute",
+			msg:  types.Message{Body: aws.String(snsNotificationBody(t, "Binary", base64.StdEncoding.EncodeToString([]byte(carrier))))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sctx, err := ExtractContext(tt.msg)
+			require.NoError(t, err)
+			assert.Equal(t, span.Context().TraceID(), sctx.TraceID())
+			assert.Equal(t, span.Context().SpanID(), sctx.SpanID())
+		})
+	}
+
+	t.Run("no context", func(t *testing.T) {
+		_, err := ExtractContext(types.Message{Body: aws.String(`{"_datadog": "not a notification"}`)})
+		assert.Equal(t, tracer.ErrSpanContextNotFound, err)
+	})
+}
+
+func snsNotificationBody(t *testing.T, typ, value string) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"Type":    "Notification",
+		"Message": "test message",
+		"MessageAttributes": map[string]any{
+			datadogKey: map[string]string{"Type": typ, "Value": value},
+		},
+	})
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandleReceiveMessage(t *testing.T) {
+	for _, names := range [][]string{nil, {"custom"}, {"All"}, {datadogKey}} {
+		params := &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/1234567890/test-queue"),
+			MessageAttributeNames: names,
+		}
+		in := middleware.InitializeInput{Parameters: params}
+		EnrichOperation(context.Background(), nil, &in, "ReceiveMessage", false)
+		// the caller's input is never modified
+		assert.Equal(t, names, params.MessageAttributeNames)
+		got := in.Parameters.(*sqs.ReceiveMessageInput)
+		if len(names) > 0 && names[0] != "custom" {
+			assert.Same(t, params, got)
+		} else {
+			assert.Equal(t, append(names, datadogKey), got.MessageAttributeNames)
+			assert.Equal(t, params.QueueUrl, got.QueueUrl)
+		}
+	}
+}
+
+func TestHandleOutput(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	const queueURL = "https://sqs.us-east-1.amazonaws.com/1234567890/test-queue"
+	producer, pctx := tracer.StartSpanFromContext(context.Background(), "producer")
+	send := &sqs.SendMessageInput{
+		MessageBody: aws.String("test message"),
+		QueueUrl:    aws.String(queueURL),
+	}
+	EnrichOperation(pctx, producer, &middleware.InitializeInput{Parameters: send}, "SendMessage", true)
+	producer.Finish()
+
+	// the pathway of the produce checkpoint is propagated
+	carrier, ok := extractCarrier(types.Message{MessageAttributes: send.MessageAttributes})
+	require.True(t, ok)
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+	require.True(t, ok)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:test-queue", "type:sqs")
+	want, _ := datastreams.PathwayFromContext(ctx)
+	assert.Equal(t, want.GetHash(), got.GetHash())
+
+	consumer, cctx := tracer.StartSpanFromContext(context.Background(), "consumer")
+	in := middleware.InitializeInput{Parameters: &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL)}}
+	out := middleware.InitializeOutput{Result: &sqs.ReceiveMessageOutput{
+		Messages: []types.Message{
+			{Body: send.MessageBody, MessageAttributes: send.MessageAttributes},
+			{Body: aws.String("untraced message")},
+		},
+	}}
+	HandleOutput(cctx, consumer, in, out, "ReceiveMessage", true)
+	consumer.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	links := spans[1].Links()
+	require.Len(t, links, 1)
+	assert.Equal(t, producer.Context().TraceIDLower(), links[0].TraceID)
+	assert.Equal(t, producer.Context().SpanID(), links[0].SpanID)
+}
+
+func TestSendMessageMaxAttributes(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	attrs := make(map[string]types.MessageAttributeValue)
+	for i := 0; i < maxMessageAttributes; i++ {
+		attrs[fmt.Sprintf("attr%d", i)] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("value")}
+	}
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "producer")
+	defer span.Finish()
+	send := &sqs.SendMessageInput{
+		MessageBody:       aws.String("test message"),
+		QueueUrl:          aws.String("https://sqs.us-east-1.amazonaws.com/1234567890/test-queue"),
+		MessageAttributes: attrs,
+	}
+	EnrichOperation(ctx, span, &middleware.InitializeInput{Parameters: send}, "SendMessage", true)
+	assert.Len(t, send.MessageAttributes, maxMessageAttributes)
+	assert.NotContains(t, send.MessageAttributes, datadogKey)
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sqs_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	awstrace "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/aws"
+	sqstrace "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/sqs"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// An example of the spans processing the received messages continuing the
+// traces of the messages.
+func ExampleExtractContext() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	cfg, err := awscfg.LoadDefaultConfig(context.Background())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	awstrace.AppendMiddleware(&cfg, awstrace.WithDataStreams())
+	client := sqs.NewFromConfig(cfg)
+
+	out, err := client.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/my-queue"),
+	})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	for _, msg := range out.Messages {
+		var opts []tracer.StartSpanOption
+		if sctx, err := sqstrace.ExtractContext(msg); err == nil {
+			opts = append(opts, tracer.ChildOf(sctx))
+		}
+		span := tracer.StartSpan("process.message", opts...)
+		// process the message
+		span.Finish()
+	}
+}
@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package sqs provides helpers to continue the traces of the SQS messages
+// received with a client traced by the aws package.
+package sqs
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	sqsTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sqs"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// ExtractContext returns the span context injected into msg by the traced
+// client which sent it, to be used as the parent of the spans processing it.
+// The context is read from the "_datadog" message attribute, or from the
+// attributes of the SNS notification wrapped in the message body when it was
+// delivered by an SNS subscription. The "_datadog" attribute is requested by
+// the traced ReceiveMessage calls.
+// It returns tracer.ErrSpanContextNotFound if msg holds no span context.
+func ExtractContext(msg types.Message) (*tracer.SpanContext, error) {
+	return sqsTracer.ExtractContext(msg)
+}
@@ -71,7 +71,7 @@ func WrapConsumer(c *kafka.Consumer, opts ...Option) *Consumer {
 }
 
 // Close calls the underlying Consumer.Close and if polling is enabled, finishes
-// any remaining span.
+// any remaining span, including the span of the current batch.
 func (c *Consumer) Close() error {
 	err := c.Consumer.Close()
 	// we only close the previous span if consuming via the events channel is
@@ -81,6 +81,7 @@ func (c *Consumer) Close() error {
 		c.tracer.PrevSpan.Finish()
 		c.tracer.PrevSpan = nil
 	}
+	c.tracer.FinishConsumeBatch(nil)
 	return err
 }
 
@@ -101,11 +102,18 @@ func (c *Consumer) Poll(timeoutMS int) (event kafka.Event) {
 	if msg, ok := evt.(*kafka.Message); ok {
 		tMsg := wrapMessage(msg)
 		c.tracer.SetConsumeCheckpoint(tMsg)
-		c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+		if c.tracer.BatchConsumeEnabled() {
+			c.tracer.AddToConsumeBatch(tMsg)
+		} else {
+			c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+		}
 	} else if offset, ok := evt.(kafka.OffsetsCommitted); ok {
 		tOffsets := wrapTopicPartitions(offset.Offsets)
 		c.tracer.TrackCommitOffsets(tOffsets, offset.Error)
 		c.tracer.TrackHighWatermarkOffset(tOffsets, c.Consumer)
+	} else if evt == nil {
+		// no message was received before the timeout
+		c.tracer.FinishConsumeBatch(nil)
 	}
 	return evt
 }
@@ -118,17 +126,24 @@ func (c *Consumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
 	}
 	msg, err := c.Consumer.ReadMessage(timeout)
 	if err != nil {
+		c.tracer.FinishConsumeBatch(nil)
 		return nil, err
 	}
 	tMsg := wrapMessage(msg)
 	c.tracer.SetConsumeCheckpoint(tMsg)
-	c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+	if c.tracer.BatchConsumeEnabled() {
+		c.tracer.AddToConsumeBatch(tMsg)
+	} else {
+		c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+	}
 	return msg, nil
 }
 
 // Commit commits current offsets and tracks the commit offsets if data streams is enabled.
+// It finishes the span of the current batch if the batch mode is enabled.
 func (c *Consumer) Commit() ([]kafka.TopicPartition, error) {
 	tps, err := c.Consumer.Commit()
+	c.tracer.FinishConsumeBatch(err)
 	tOffsets := wrapTopicPartitions(tps)
 	c.tracer.TrackCommitOffsets(tOffsets, err)
 	c.tracer.TrackHighWatermarkOffset(tOffsets, c.Consumer)
@@ -136,8 +151,10 @@ func (c *Consumer) Commit() ([]kafka.TopicPartition, error) {
 }
 
 // CommitMessage commits a message and tracks the commit offsets if data streams is enabled.
+// It finishes the span of the current batch if the batch mode is enabled.
 func (c *Consumer) CommitMessage(msg *kafka.Message) ([]kafka.TopicPartition, error) {
 	tps, err := c.Consumer.CommitMessage(msg)
+	c.tracer.FinishConsumeBatch(err)
 	tOffsets := wrapTopicPartitions(tps)
 	c.tracer.TrackCommitOffsets(tOffsets, err)
 	c.tracer.TrackHighWatermarkOffset(tOffsets, c.Consumer)
@@ -145,8 +162,10 @@ func (c *Consumer) CommitMessage(msg *kafka.Message) ([]kafka.TopicPartition, er
 }
 
 // CommitOffsets commits provided offsets and tracks the commit offsets if data streams is enabled.
+// It finishes the span of the current batch if the batch mode is enabled.
 func (c *Consumer) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
 	tps, err := c.Consumer.CommitOffsets(offsets)
+	c.tracer.FinishConsumeBatch(err)
 	tOffsets := wrapTopicPartitions(tps)
 	c.tracer.TrackCommitOffsets(tOffsets, err)
 	c.tracer.TrackHighWatermarkOffset(tOffsets, c.Consumer)
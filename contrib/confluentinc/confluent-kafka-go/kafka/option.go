@@ -50,3 +50,25 @@ func WithConfig(cm *kafka.ConfigMap) kafkatrace.OptionFn {
 
 // WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
 var WithDataStreams = kafkatrace.WithDataStreams
+
+// WithBatchConsume enables the batch mode of the consumer, for the messages
+// polled one at a time but processed as a batch. Instead of a span per message,
+// the messages polled until the next commit are traced by a single span, linked
+// to the spans which produced them. At most maxLinks links are added to the
+// span, 128 if maxLinks is not positive.
+//
+// As the offsets may never be committed explicitly, the batch also ends when a
+// poll returns no message, or when it reaches the limits set by
+// WithBatchConsumeLimits.
+//
+// The messages consumed via the events channel are traced in batches too, a
+// batch ending when the committed offsets are received from the channel, or
+// when it reaches its limits.
+var WithBatchConsume = kafkatrace.WithBatchConsume
+
+// WithBatchConsumeLimits sets the maximum number of messages and the maximum
+// duration of the batches traced with WithBatchConsume. A batch which reaches
+// either of them is finished, its next message starting a new batch. A limit
+// which is not positive disables it. By default, the number of messages is not
+// limited and the duration is limited to 10 seconds.
+var WithBatchConsumeLimits = kafkatrace.WithBatchConsumeLimits
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kafkatrace
+
+import (
+	"math"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+const (
+	// DefaultBatchMaxLinks is the default maximum number of links of the span
+	// of a batch, see WithBatchConsume.
+	DefaultBatchMaxLinks = 128
+	// DefaultBatchMaxDuration is the default maximum duration of the span of
+	// a batch, see WithBatchConsumeLimits.
+	DefaultBatchMaxDuration = 10 * time.Second
+)
+
+// BatchConsumeEnabled reports whether the messages are traced in batches, see
+// WithBatchConsume.
+func (tr *Tracer) BatchConsumeEnabled() bool {
+	return tr.batch != nil
+}
+
+// AddToConsumeBatch traces msg as part of the current batch, the span of the
+// batch being started with its first message. The span is linked to the span
+// which produced msg, unless it already has the maximum number of links.
+func (tr *Tracer) AddToConsumeBatch(msg Message) {
+	tr.batch.Add(tr.ctx, messaging.Message{
+		Destination: msg.GetTopicPartition().GetTopic(),
+		Carrier:     NewMessageCarrier(msg),
+	})
+}
+
+// FinishConsumeBatch finishes the span of the current batch, if any, with err,
+// like the error of the commit which ends it.
+func (tr *Tracer) FinishConsumeBatch(err error) {
+	if tr.batch != nil {
+		tr.batch.Finish(err)
+	}
+}
+
+func (tr *Tracer) newConsumeBatch(instr *instrumentation.Instrumentation) *messaging.ConsumeBatch {
+	cfg := messaging.Config{
+		System:      ext.MessagingSystemKafka,
+		Component:   ComponentName(tr.ckgoVersion),
+		Instr:       instr,
+		ServiceName: tr.consumerServiceName,
+	}
+	var opts []tracer.StartSpanOption
+	if tr.bootstrapServers != "" {
+		opts = append(opts, tracer.Tag(ext.KafkaBootstrapServers, tr.bootstrapServers))
+	}
+	if !math.IsNaN(tr.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, tr.analyticsRate))
+	}
+	return messaging.NewConsumeBatch(cfg, tr.batchLimits, opts...)
+}
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kafkatrace
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+type testTopicPartition struct {
+	topic string
+}
+
+func (tp testTopicPartition) GetTopic() string    { return tp.topic }
+func (tp testTopicPartition) GetPartition() int32 { return 0 }
+func (tp testTopicPartition) GetOffset() int64    { return 0 }
+func (tp testTopicPartition) GetError() error     { return nil }
+
+type testMessage struct {
+	topic   string
+	headers []Header
+}
+
+func (m *testMessage) GetValue() []byte                  { return nil }
+func (m *testMessage) GetKey() []byte                    { return nil }
+func (m *testMessage) GetHeaders() []Header              { return m.headers }
+func (m *testMessage) SetHeaders(headers []Header)       { m.headers = headers }
+func (m *testMessage) GetTopicPartition() TopicPartition { return testTopicPartition{m.topic} }
+func (m *testMessage) Unwrap() any                       { return m }
+
+func TestConsumeBatch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewKafkaTracer(testInstr, CKGoVersion2, 0, WithBatchConsume(2))
+	require.True(t, tr.BatchConsumeEnabled())
+
+	var producers []*tracer.Span
+	for range 3 {
+		msg := &testMessage{topic: "gotest"}
+		span := tracer.StartSpan("kafka.produce")
+		require.NoError(t, tracer.Inject(span.Context(), NewMessageCarrier(msg)))
+		span.Finish()
+		producers = append(producers, span)
+		tr.AddToConsumeBatch(msg)
+	}
+	tr.AddToConsumeBatch(&testMessage{topic: "gotest"})
+	tr.FinishConsumeBatch(errors.New("commit failed"))
+	// no batch in progress
+	tr.FinishConsumeBatch(nil)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	batch := spans[3]
+	assert.Equal(t, "kafka.consume.batch", batch.OperationName())
+	assert.Equal(t, "gotest", batch.Tag(ext.ResourceName))
+	assert.Equal(t, "gotest", batch.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, ext.SpanKindConsumer, batch.Tag(ext.SpanKind))
+	assert.Equal(t, "confluentinc/confluent-kafka-go/kafka.v2", batch.Tag(ext.Component))
+	assert.Equal(t, float64(4), batch.Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, "commit failed", batch.Tag(ext.ErrorMsg))
+	assert.Zero(t, batch.ParentID())
+
+	// the number of links is capped
+	links := batch.Links()
+	require.Len(t, links, 2)
+	for i, link := range links {
+		assert.Equal(t, producers[i].Context().TraceIDLower(), link.TraceID)
+		assert.Equal(t, producers[i].Context().SpanID(), link.SpanID)
+	}
+
+	t.Run("next", func(t *testing.T) {
+		mt.Reset()
+		tr.AddToConsumeBatch(&testMessage{topic: "other"})
+		tr.FinishConsumeBatch(nil)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "other", spans[0].Tag(ext.ResourceName))
+		assert.Equal(t, float64(1), spans[0].Tag(ext.MessagingBatchMessageCount))
+		assert.Empty(t, spans[0].Links())
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		mt.Reset()
+		tr.AddToConsumeBatch(&testMessage{topic: "b"})
+		tr.AddToConsumeBatch(&testMessage{topic: "a"})
+		tr.FinishConsumeBatch(nil)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "a,b", spans[0].Tag(ext.ResourceName))
+		assert.Nil(t, spans[0].Tag(ext.MessagingDestinationName))
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		mt.Reset()
+		tr := NewKafkaTracer(testInstr, CKGoVersion2, 0, WithBatchConsume(0), WithBatchConsumeLimits(2, time.Millisecond))
+		for range 3 {
+			tr.AddToConsumeBatch(&testMessage{topic: "gotest"})
+		}
+		require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, time.Second, time.Millisecond)
+		spans := mt.FinishedSpans()
+		assert.Equal(t, float64(2), spans[0].Tag(ext.MessagingBatchMessageCount))
+		assert.Equal(t, float64(1), spans[1].Tag(ext.MessagingBatchMessageCount))
+		// the batch finished by the timer is not finished again
+		tr.FinishConsumeBatch(nil)
+		assert.Len(t, mt.FinishedSpans(), 2)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.False(t, NewKafkaTracer(testInstr, CKGoVersion2, 0).BatchConsumeEnabled())
+		tr := NewKafkaTracer(testInstr, CKGoVersion2, 0, WithBatchConsume(0))
+		assert.Equal(t, DefaultBatchMaxLinks, tr.batchLimits.MaxLinks)
+	})
+}
+
+type testOffsetsCommitted struct{ err error }
+
+func (o testOffsetsCommitted) GetError() error              { return o.err }
+func (o testOffsetsCommitted) GetOffsets() []TopicPartition { return nil }
+
+// testEvent is either a message or committed offsets.
+type testEvent struct {
+	msg     *testMessage
+	offsets *testOffsetsCommitted
+}
+
+func (e testEvent) KafkaMessage() (Message, bool) { return e.msg, e.msg != nil }
+
+func (e testEvent) KafkaOffsetsCommitted() (OffsetsCommitted, bool) {
+	return e.offsets, e.offsets != nil
+}
+
+func TestConsumeBatchEventsChannel(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewKafkaTracer(testInstr, CKGoVersion2, 0, WithBatchConsume(0))
+	in := make(chan testEvent)
+	out := WrapConsumeEventsChannel(tr, in, nil, func(e testEvent) testEvent { return e })
+	send := func(e testEvent) {
+		in <- e
+		<-out
+	}
+	send(testEvent{msg: &testMessage{topic: "gotest"}})
+	send(testEvent{msg: &testMessage{topic: "gotest"}})
+	send(testEvent{offsets: &testOffsetsCommitted{err: errors.New("commit failed")}})
+	send(testEvent{msg: &testMessage{topic: "gotest"}})
+	close(in)
+	for range out {
+	}
+
+	// the batches end with the commits, and when the channel is closed
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "kafka.consume.batch", spans[0].OperationName())
+	assert.Equal(t, float64(2), spans[0].Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, "commit failed", spans[0].Tag(ext.ErrorMsg))
+	assert.Equal(t, float64(1), spans[1].Tag(ext.MessagingBatchMessageCount))
+}
@@ -27,11 +27,17 @@ func WrapConsumeEventsChannel[E any, TE Event](tr *Tracer, in chan E, consumer C
 
 			// only trace messages
 			if msg, ok := tEvt.KafkaMessage(); ok {
-				next = tr.StartConsumeSpan(msg)
+				if tr.BatchConsumeEnabled() {
+					tr.AddToConsumeBatch(msg)
+				} else {
+					next = tr.StartConsumeSpan(msg)
+				}
 				tr.SetConsumeCheckpoint(msg)
 			} else if offset, ok := tEvt.KafkaOffsetsCommitted(); ok {
 				tr.TrackCommitOffsets(offset.GetOffsets(), offset.GetError())
 				tr.TrackHighWatermarkOffset(offset.GetOffsets(), consumer)
+				// the messages received before the commit make a batch
+				tr.FinishConsumeBatch(offset.GetError())
 			}
 
 			out <- evt
@@ -46,6 +52,7 @@ func WrapConsumeEventsChannel[E any, TE Event](tr *Tracer, in chan E, consumer C
 			tr.PrevSpan.Finish()
 			tr.PrevSpan = nil
 		}
+		tr.FinishConsumeBatch(nil)
 	}()
 	return out
 }
@@ -10,9 +10,11 @@ import (
 	"math"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
 	"github.com/DataDog/dd-trace-go/v2/internal"
 )
 
@@ -30,6 +32,8 @@ type Tracer struct {
 	dsmEnabled          bool
 	ckgoVersion         CKGoVersion
 	librdKafkaVersion   int
+	batchLimits         messaging.BatchLimits
+	batch               *messaging.ConsumeBatch
 }
 
 func (tr *Tracer) DSMEnabled() bool {
@@ -53,6 +57,7 @@ func NewKafkaTracer(instr *instrumentation.Instrumentation, ckgoVersion CKGoVers
 		analyticsRate:     instr.AnalyticsRate(false),
 		ckgoVersion:       ckgoVersion,
 		librdKafkaVersion: librdKafkaVersion,
+		batchLimits:       messaging.BatchLimits{MaxDuration: DefaultBatchMaxDuration},
 	}
 	if internal.BoolEnv("DD_TRACE_KAFKA_ANALYTICS_ENABLED", false) {
 		tr.analyticsRate = 1.0
@@ -71,6 +76,9 @@ func NewKafkaTracer(instr *instrumentation.Instrumentation, ckgoVersion CKGoVers
 		}
 		opt.apply(tr)
 	}
+	if tr.batchLimits.MaxLinks > 0 {
+		tr.batch = tr.newConsumeBatch(instr)
+	}
 	return tr
 }
 
@@ -149,3 +157,37 @@ func WithDataStreams() OptionFn {
 		tr.dsmEnabled = true
 	}
 }
+
+// WithBatchConsume enables the batch mode of the consumers, for the messages
+// polled one at a time but processed as a batch. Instead of a span per message,
+// the messages polled until the next commit are traced by a single span, linked
+// to the spans which produced them. At most maxLinks links are added to the
+// span, DefaultBatchMaxLinks if maxLinks is not positive.
+//
+// As the offsets may never be committed explicitly, the batch also ends when a
+// poll returns no message, or when it reaches the limits set by
+// WithBatchConsumeLimits.
+//
+// The messages consumed via the events channel are traced in batches too, a
+// batch ending when the committed offsets are received from the channel, or
+// when it reaches its limits.
+func WithBatchConsume(maxLinks int) OptionFn {
+	return func(tr *Tracer) {
+		if maxLinks <= 0 {
+			maxLinks = DefaultBatchMaxLinks
+		}
+		tr.batchLimits.MaxLinks = maxLinks
+	}
+}
+
+// WithBatchConsumeLimits sets the maximum number of messages and the maximum
+// duration of the batches traced with WithBatchConsume. A batch which reaches
+// either of them is finished, its next message starting a new batch. A limit
+// which is not positive disables it. By default, the number of messages is
+// not limited and the duration is limited to DefaultBatchMaxDuration.
+func WithBatchConsumeLimits(maxMessages int, maxDuration time.Duration) OptionFn {
+	return func(tr *Tracer) {
+		tr.batchLimits.MaxMessages = maxMessages
+		tr.batchLimits.MaxDuration = maxDuration
+	}
+}
@@ -42,3 +42,26 @@ func Example() {
 		}},
 	})
 }
+
+func ExampleNewDBMMonitor() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	ctx := context.Background()
+	m := mongotrace.NewDBMMonitor(mongotrace.WithDBMPropagation(tracer.DBMPropagationModeFull))
+	opts := options.Client()
+	opts.Monitor = m.CommandMonitor
+	opts.ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(opts)
+	if err != nil {
+		panic(err)
+	}
+	inventory := client.Database("example").Collection("inventory")
+
+	// The comment correlates the command with Database Monitoring.
+	cursor, err := inventory.Find(ctx, bson.D{{Key: "item", Value: "canvas"}}, options.Find().SetComment(m.Comment(ctx)))
+	if err != nil {
+		panic(err)
+	}
+	defer cursor.Close(ctx)
+}
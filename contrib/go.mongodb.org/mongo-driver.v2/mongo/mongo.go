@@ -4,6 +4,12 @@
 // Copyright 2025 Datadog, Inc.
 
 // Package mongo provides functions to trace the mongodb/mongo-go-driver/v2 package (https://github.com/mongodb/mongo-go-driver).
+//
+// The driver doesn't allow the monitors to modify the commands, so the commands
+// are only correlated with Database Monitoring when the comment returned by
+// DBMMonitor.Comment is set on their operations by hand, like find, aggregate,
+// update or delete: the integration doesn't set it. WithDBMPropagation has no
+// effect on the monitors created with NewMonitor.
 package mongo
 
 import (
@@ -15,6 +21,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/dbm"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
@@ -35,8 +42,9 @@ type spanKey struct {
 
 type monitor struct {
 	sync.Mutex
-	spans map[spanKey]*tracer.Span
-	cfg   *config
+	spans    map[spanKey]*tracer.Span
+	cfg      *config
+	injected dbm.InjectedSpans
 }
 
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
@@ -56,6 +64,7 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
 		tracer.Tag(ext.DBSystem, ext.DBSystemMongoDB),
 	}
+	opts = append(opts, m.dbmSpanOptions(evt)...)
 	span, _ := tracer.StartSpanFromContext(ctx, m.cfg.spanName, opts...)
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
@@ -98,6 +107,10 @@ func NewMonitor(opts ...Option) *event.CommandMonitor {
 	for _, opt := range opts {
 		opt.apply(cfg)
 	}
+	return newMonitor(cfg)
+}
+
+func newMonitor(cfg *config) *event.CommandMonitor {
 	instr.Logger().Debug("contrib/go.mongodb.org/mongo-driver.v2/mongo: Creating Monitor: %#v", cfg)
 	m := &monitor{
 		spans: make(map[spanKey]*tracer.Span),
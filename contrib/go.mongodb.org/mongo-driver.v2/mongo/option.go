@@ -6,12 +6,17 @@
 package mongo
 
 import (
+	"os"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 )
 
 type config struct {
 	serviceName string
 	spanName    string
+
+	dbmPropagationMode tracer.DBMPropagationMode
 }
 
 // Option describes options for the Mongo integration.
@@ -29,6 +34,7 @@ func (fn OptionFn) apply(cfg *config) {
 func defaults(cfg *config) {
 	cfg.serviceName = instr.ServiceName(instrumentation.ComponentDefault, nil)
 	cfg.spanName = instr.OperationName(instrumentation.ComponentDefault, nil)
+	cfg.dbmPropagationMode = tracer.DBMPropagationMode(os.Getenv("DD_DBM_PROPAGATION_MODE"))
 }
 
 // WithService sets the given service name for this integration spans.
@@ -37,3 +43,23 @@ func WithService(name string) OptionFn {
 		cfg.serviceName = name
 	}
 }
+
+// WithDBMPropagation sets the mode of the comments returned by
+// DBMMonitor.Comment, which correlate the commands with Database Monitoring. It
+// defaults to the value of the DD_DBM_PROPAGATION_MODE environment variable.
+// The monitors created with NewMonitor don't return comments, so the option
+// only applies to NewDBMMonitor.
+//
+// DBMPropagationModeFull includes dynamic values like the span id, trace id and
+// the sampled flag, which make every command unique. Use
+// DBMPropagationModeService if this is a concern, for example for the query
+// plan cache.
+//
+// Note that enabling comment propagation results in potentially confidential data (service names)
+// being stored in the databases which can then be accessed by other 3rd parties that have been granted
+// access to the database.
+func WithDBMPropagation(mode tracer.DBMPropagationMode) OptionFn {
+	return func(cfg *config) {
+		cfg.dbmPropagationMode = mode
+	}
+}
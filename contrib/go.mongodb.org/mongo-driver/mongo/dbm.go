@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/dbm"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// DBMMonitor is a command monitor which also returns the comments correlating
+// the commands with Database Monitoring. The MongoDB driver doesn't allow
+// monitors to modify the commands, so the comments must be set on the
+// operations:
+//
+//	m := mongotrace.NewDBMMonitor(mongotrace.WithDBMPropagation(tracer.DBMPropagationModeFull))
+//	client, err := mongo.Connect(ctx, options.Client().SetMonitor(m.CommandMonitor))
+//	...
+//	coll.Find(ctx, filter, options.Find().SetComment(m.Comment(ctx)))
+type DBMMonitor struct {
+	*event.CommandMonitor
+	cfg *config
+}
+
+// NewDBMMonitor creates a new mongodb event CommandMonitor, along with the
+// comments of the commands it traces.
+func NewDBMMonitor(opts ...Option) *DBMMonitor {
+	cfg := new(config)
+	defaults(cfg)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return &DBMMonitor{CommandMonitor: newMonitor(cfg), cfg: cfg}
+}
+
+// Comment returns the comment to set on the find, aggregate, update and delete
+// operations run with ctx. It holds the service tags and, in
+// DBMPropagationModeFull, the trace context, as key-value pairs encoded like
+// the SQL comments of the SQL integrations. It returns an empty string when
+// the propagation is disabled.
+//
+// The span of the command started by the monitor uses the span ID injected in
+// its comment.
+func (m *DBMMonitor) Comment(ctx context.Context) string {
+	return dbm.Comment(ctx, m.cfg.dbmPropagationMode, m.cfg.serviceName)
+}
+
+// dbmSpanOptions returns the options of the span of the command started by
+// evt if its comment holds the trace context injected by DBMMonitor. The span
+// ID of the comment is only used by the first command holding it: the getMore
+// commands of a cursor copy the comment of the command which opened it, and
+// the retried commands hold the comment of the failed ones.
+func (m *monitor) dbmSpanOptions(evt *event.CommandStartedEvent) []tracer.StartSpanOption {
+	if evt.CommandName == "getMore" {
+		return nil
+	}
+	comment, _ := evt.Command.Lookup("comment").StringValueOK()
+	return m.injected.SpanOptions(comment)
+}
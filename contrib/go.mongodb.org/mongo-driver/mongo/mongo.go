@@ -7,6 +7,12 @@
 // It support v0.2.0 of github.com/mongodb/mongo-go-driver
 //
 // `NewMonitor` will return an event.CommandMonitor which is used to trace requests.
+//
+// The driver doesn't allow the monitors to modify the commands, so the commands
+// are only correlated with Database Monitoring when the comment returned by
+// DBMMonitor.Comment is set on their operations by hand, like find, aggregate,
+// update or delete: the integration doesn't set it. WithDBMPropagation has no
+// effect on the monitors created with NewMonitor.
 package mongo
 
 import (
@@ -19,6 +25,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/dbm"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/event"
@@ -39,8 +46,9 @@ type spanKey struct {
 
 type monitor struct {
 	sync.Mutex
-	spans map[spanKey]*tracer.Span
-	cfg   *config
+	spans    map[spanKey]*tracer.Span
+	cfg      *config
+	injected dbm.InjectedSpans
 }
 
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
@@ -63,6 +71,7 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 	if !math.IsNaN(m.cfg.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, m.cfg.analyticsRate))
 	}
+	opts = append(opts, m.dbmSpanOptions(evt)...)
 	span, _ := tracer.StartSpanFromContext(ctx, m.cfg.spanName, opts...)
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
@@ -105,6 +114,10 @@ func NewMonitor(opts ...Option) *event.CommandMonitor {
 	for _, opt := range opts {
 		opt.apply(cfg)
 	}
+	return newMonitor(cfg)
+}
+
+func newMonitor(cfg *config) *event.CommandMonitor {
 	instr.Logger().Debug("contrib/go.mongodb.org/mongo-driver/mongo: Creating Monitor: %#v", cfg)
 	m := &monitor{
 		spans: make(map[spanKey]*tracer.Span),
@@ -13,13 +13,16 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/dbm"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
 )
 
@@ -136,3 +139,95 @@ func TestAnalyticsSettings(t *testing.T) {
 		assertRate(t, mt, 0.23, WithAnalyticsRate(0.23))
 	})
 }
+
+func TestDBMComment(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	defer span.Finish()
+
+	t.Run("full", func(t *testing.T) {
+		comment := NewDBMMonitor(WithService("mongo-db"), WithDBMPropagation(tracer.DBMPropagationModeFull)).Comment(ctx)
+		assert.Regexp(t, `^dddbs='mongo-db',.*traceparent='00-[0-9a-f]{32}-[0-9a-f]{16}-0[01]'$`, comment)
+
+		sctx, err := dbm.Extract(comment)
+		require.NoError(t, err)
+		assert.Equal(t, span.Context().TraceIDLower(), sctx.TraceIDLower())
+		assert.NotZero(t, sctx.SpanID())
+	})
+
+	t.Run("service", func(t *testing.T) {
+		comment := NewDBMMonitor(WithService("mongo-db"), WithDBMPropagation(tracer.DBMPropagationModeService)).Comment(ctx)
+		assert.Regexp(t, `^dddbs='mongo-db'`, comment)
+		assert.NotContains(t, comment, "traceparent")
+
+		_, err := dbm.Extract(comment)
+		assert.Equal(t, tracer.ErrSpanContextNotFound, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Empty(t, NewDBMMonitor(WithDBMPropagation(tracer.DBMPropagationModeDisabled)).Comment(ctx))
+	})
+}
+
+func TestDBMPropagation(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	opts := options.Client()
+	m := NewDBMMonitor(WithDBMPropagation(tracer.DBMPropagationModeFull))
+	opts.Monitor = m.CommandMonitor
+	opts.ApplyURI("mongodb://localhost:27017/?connect=direct")
+	client, err := mongo.Connect(ctx, opts)
+	require.NoError(t, err)
+
+	comment := m.Comment(ctx)
+	sctx, err := dbm.Extract(comment)
+	require.NoError(t, err)
+	cursor, err := client.
+		Database("test-database").
+		Collection("test-collection").
+		Find(ctx, bson.D{}, options.Find().SetComment(comment))
+	require.NoError(t, err)
+	require.NoError(t, cursor.Close(ctx))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, "mongo.find", s.Tag(ext.ResourceName))
+	assert.Equal(t, sctx.SpanID(), s.SpanID())
+	assert.Equal(t, "true", s.Tag(dbm.KeyTraceInjected))
+}
+
+func TestDBMRetries(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	defer span.Finish()
+	m := NewDBMMonitor(WithDBMPropagation(tracer.DBMPropagationModeFull))
+	comment := m.Comment(ctx)
+	sctx, err := dbm.Extract(comment)
+	require.NoError(t, err)
+	cmd, err := bson.Marshal(bson.D{{Key: "find", Value: "test-collection"}, {Key: "comment", Value: comment}})
+	require.NoError(t, err)
+
+	// the command is retried, then its cursor is iterated
+	for i, name := range []string{"find", "find", "getMore"} {
+		m.Started(ctx, &event.CommandStartedEvent{Command: cmd, CommandName: name, ConnectionID: "localhost:27017", RequestID: int64(i)})
+		m.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{ConnectionID: "localhost:27017", RequestID: int64(i)}})
+	}
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, sctx.SpanID(), spans[0].SpanID())
+	assert.Equal(t, "true", spans[0].Tag(dbm.KeyTraceInjected))
+	for _, s := range spans[1:] {
+		assert.NotEqual(t, sctx.SpanID(), s.SpanID())
+		assert.Nil(t, s.Tag(dbm.KeyTraceInjected))
+	}
+}
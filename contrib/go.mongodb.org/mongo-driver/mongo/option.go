@@ -7,7 +7,9 @@ package mongo
 
 import (
 	"math"
+	"os"
 
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 )
 
@@ -15,6 +17,8 @@ type config struct {
 	serviceName   string
 	spanName      string
 	analyticsRate float64
+
+	dbmPropagationMode tracer.DBMPropagationMode
 }
 
 // Option describes options for the Mongo integration.
@@ -32,6 +36,7 @@ func (fn OptionFn) apply(cfg *config) {
 func defaults(cfg *config) {
 	cfg.serviceName = instr.ServiceName(instrumentation.ComponentDefault, nil)
 	cfg.spanName = instr.OperationName(instrumentation.ComponentDefault, nil)
+	cfg.dbmPropagationMode = tracer.DBMPropagationMode(os.Getenv("DD_DBM_PROPAGATION_MODE"))
 	cfg.analyticsRate = instr.AnalyticsRate(false)
 }
 
@@ -64,3 +69,23 @@ func WithAnalyticsRate(rate float64) OptionFn {
 		}
 	}
 }
+
+// WithDBMPropagation sets the mode of the comments returned by
+// DBMMonitor.Comment, which correlate the commands with Database Monitoring. It
+// defaults to the value of the DD_DBM_PROPAGATION_MODE environment variable.
+// The monitors created with NewMonitor don't return comments, so the option
+// only applies to NewDBMMonitor.
+//
+// DBMPropagationModeFull includes dynamic values like the span id, trace id and
+// the sampled flag, which make every command unique. Use
+// DBMPropagationModeService if this is a concern, for example for the query
+// plan cache.
+//
+// Note that enabling comment propagation results in potentially confidential data (service names)
+// being stored in the databases which can then be accessed by other 3rd parties that have been granted
+// access to the database.
+func WithDBMPropagation(mode tracer.DBMPropagationMode) OptionFn {
+	return func(cfg *config) {
+		cfg.dbmPropagationMode = mode
+	}
+}
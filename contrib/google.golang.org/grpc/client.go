@@ -42,15 +42,21 @@ func (cs *clientStream) RecvMsg(m interface{}) (err error) {
 		if p, ok := peer.FromContext(cs.Context()); ok {
 			setSpanTargetFromPeer(span, *p)
 		}
-		defer func() { finishWithError(span, err, cs.cfg) }()
+		defer func() {
+			if err == nil {
+				cs.cfg.linkMessage(span, m)
+			}
+			finishWithError(span, err, cs.cfg)
+		}()
 	}
 	err = cs.ClientStream.RecvMsg(m)
 	return err
 }
 
 func (cs *clientStream) SendMsg(m interface{}) (err error) {
+	var span *tracer.Span
 	if _, ok := cs.cfg.untracedMethods[cs.method]; cs.cfg.traceStreamMessages && !ok {
-		span, _ := startSpanFromContext(
+		span, _ = startSpanFromContext(
 			cs.Context(),
 			cs.method,
 			"grpc.message",
@@ -63,6 +69,7 @@ func (cs *clientStream) SendMsg(m interface{}) (err error) {
 		}
 		defer func() { finishWithError(span, err, cs.cfg) }()
 	}
+	cs.cfg.injectMessage(span, m)
 	err = cs.ClientStream.SendMsg(m)
 	return err
 }
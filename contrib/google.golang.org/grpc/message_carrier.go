@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+// MessageCarrier carries the trace context of a single stream message, for
+// instance in a map field of an envelope message wrapping the payload.
+type MessageCarrier interface {
+	tracer.TextMapWriter
+	tracer.TextMapReader
+}
+
+// MessageCarrierFunc returns the carrier of the trace context of a stream
+// message, or nil if the message can't carry one. See WithMessageCarrier.
+type MessageCarrierFunc func(msg interface{}) MessageCarrier
+
+// BinaryCarrier is a MessageCarrier over a map of byte values, like the
+// map<string, bytes> fields of protocol buffers messages.
+type BinaryCarrier map[string][]byte
+
+var _ MessageCarrier = BinaryCarrier(nil)
+
+// Set implements tracer.TextMapWriter.
+func (c BinaryCarrier) Set(key, val string) {
+	c[key] = []byte(val)
+}
+
+// ForeachKey implements tracer.TextMapReader.
+func (c BinaryCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		if err := handler(k, string(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectMessage injects the trace context of the span of the stream message m
+// into its carrier. Like linkMessage, it only applies to traced messages, and
+// it leaves a trace context already set by the application untouched.
+func (cfg *config) injectMessage(span *tracer.Span, m interface{}) {
+	if cfg.messageCarrier == nil || span == nil {
+		return
+	}
+	carrier := cfg.messageCarrier(m)
+	if carrier == nil {
+		return
+	}
+	if sctx, err := tracer.Extract(carrier); err == nil && sctx != nil {
+		return
+	}
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		instr.Logger().Debug("contrib/google.golang.org/grpc: failed to inject the context of a stream message: %s", err.Error())
+	}
+}
+
+// linkMessage links the span of the received stream message m to the span
+// which sent it, if m carries its trace context.
+func (cfg *config) linkMessage(span *tracer.Span, m interface{}) {
+	if cfg.messageCarrier == nil {
+		return
+	}
+	carrier := cfg.messageCarrier(m)
+	if carrier == nil {
+		return
+	}
+	sctx, err := tracer.Extract(carrier)
+	if err != nil || sctx == nil {
+		return
+	}
+	span.AddLink(messaging.SpanLink(sctx))
+}
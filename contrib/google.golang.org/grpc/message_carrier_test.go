@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/instrumentation/testutils/grpc/v2/fixturepb"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldCarrier carries a trace context in a string field of a fixture
+// message, as "|key=value" pairs appended to its value.
+type fieldCarrier struct {
+	field *string
+}
+
+func (c fieldCarrier) Set(key, val string) {
+	*c.field += "|" + key + "=" + val
+}
+
+func (c fieldCarrier) ForeachKey(handler func(key, val string) error) error {
+	parts := strings.Split(*c.field, "|")
+	for _, kv := range parts[1:] {
+		k, v, _ := strings.Cut(kv, "=")
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fixtureCarrier(msg interface{}) MessageCarrier {
+	switch msg := msg.(type) {
+	case *fixturepb.FixtureRequest:
+		return fieldCarrier{&msg.Name}
+	case *fixturepb.FixtureReply:
+		return fieldCarrier{&msg.Message}
+	}
+	return nil
+}
+
+func TestMessageCarrier(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rig, err := newRig(true, WithMessageCarrier(fixtureCarrier))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, rig.Close()) }()
+
+	stream, err := rig.client.StreamPing(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&fixturepb.FixtureRequest{Name: "pass"}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Message, "passed|"), "the reply carries a trace context")
+	require.NoError(t, stream.CloseSend())
+	// to flush the spans
+	stream.Recv()
+	waitForSpans(mt, 8)
+
+	messages := make(map[uint64]*mocktracer.Span)
+	for _, span := range mt.FinishedSpans() {
+		if span.OperationName() == "grpc.message" {
+			messages[span.SpanID()] = span
+		}
+	}
+	linked := 0
+	for _, span := range messages {
+		links := span.Links()
+		if len(links) == 0 {
+			continue
+		}
+		linked++
+		require.Len(t, links, 1)
+		sender, ok := messages[links[0].SpanID]
+		require.True(t, ok, "the message span is linked to the span of the message sent")
+		assert.Equal(t, sender.TraceID(), span.TraceID())
+		assert.Empty(t, sender.Links())
+	}
+	// the request and the reply, not the end of the stream
+	assert.Equal(t, 2, linked)
+}
+
+func TestMessageCarrierInject(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	carrier := BinaryCarrier{}
+	cfg := new(config)
+	clientDefaults(cfg)
+	WithMessageCarrier(func(interface{}) MessageCarrier { return carrier })(cfg)
+
+	// without a message span, nothing is propagated
+	cfg.injectMessage(nil, nil)
+	assert.Empty(t, carrier)
+
+	// the trace context set by the application is kept
+	app := tracer.StartSpan("app")
+	require.NoError(t, tracer.Inject(app.Context(), carrier))
+	span := tracer.StartSpan("grpc.message")
+	cfg.injectMessage(span, nil)
+	sctx, err := tracer.Extract(carrier)
+	require.NoError(t, err)
+	assert.Equal(t, app.Context().SpanID(), sctx.SpanID())
+	span.Finish()
+	app.Finish()
+
+	// no trace context to link
+	span = tracer.StartSpan("grpc.message")
+	cfg.messageCarrier = func(interface{}) MessageCarrier { return BinaryCarrier{} }
+	cfg.linkMessage(span, nil)
+	span.Finish()
+	assert.Empty(t, mt.FinishedSpans()[2].Links())
+}
@@ -66,6 +66,7 @@ type config struct {
 	withErrorDetailTags bool
 	spanOpts            []tracer.StartSpanOption
 	tags                map[string]interface{}
+	messageCarrier      MessageCarrierFunc
 }
 
 func defaults(cfg *config) {
@@ -123,6 +124,20 @@ func WithStreamMessages(enabled bool) OptionFn {
 	}
 }
 
+// WithMessageCarrier sets the function returning the carrier of the trace
+// context of each stream message, so that long-lived streams multiplexing many
+// logical requests can propagate a trace context per message. The trace
+// context of the span of each sent message is injected into its carrier, and
+// the span of each received message is linked to the span which sent it. A
+// trace context already set in the carrier by the application is left
+// untouched. As it works on the message spans, this option has no effect
+// with WithStreamMessages(false). It does not apply to the stats handler.
+func WithMessageCarrier(fn MessageCarrierFunc) OptionFn {
+	return func(cfg *config) {
+		cfg.messageCarrier = fn
+	}
+}
+
 // NoDebugStack disables debug stacks for traces with errors. This is useful in situations
 // where errors are frequent, and the overhead of calling debug.Stack may affect performance.
 func NoDebugStack() OptionFn {
@@ -50,6 +50,9 @@ func (ss *serverStream) RecvMsg(m interface{}) (err error) {
 		defer func() {
 			withMetadataTags(ss.ctx, ss.cfg, span)
 			withRequestTags(ss.cfg, m, span)
+			if err == nil {
+				ss.cfg.linkMessage(span, m)
+			}
 			finishWithError(span, err, ss.cfg)
 		}()
 	}
@@ -58,9 +61,10 @@ func (ss *serverStream) RecvMsg(m interface{}) (err error) {
 }
 
 func (ss *serverStream) SendMsg(m interface{}) (err error) {
+	var span *tracer.Span
 	_, um := ss.cfg.untracedMethods[ss.method]
 	if ss.cfg.traceStreamMessages && !um {
-		span, _ := startSpanFromContext(
+		span, _ = startSpanFromContext(
 			ss.ctx,
 			ss.method,
 			"grpc.message",
@@ -70,6 +74,7 @@ func (ss *serverStream) SendMsg(m interface{}) (err error) {
 		span.SetTag(ext.Component, componentName)
 		defer func() { finishWithError(span, err, ss.cfg) }()
 	}
+	ss.cfg.injectMessage(span, m)
 	err = ss.ServerStream.SendMsg(m)
 	return err
 }
@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package pgx
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+const keyDBMTraceInjected = "_dd.dbm_trace_injected"
+
+// pgx doesn't allow tracers to rewrite queries, so the Database Monitoring
+// comments are injected in the messages sent to the server instead: the
+// tracer sets the comment of the traced operation on the connection, and the
+// queries of the Query and Parse messages written while it's set are
+// prefixed with it.
+
+// dbmEnabled reports whether the Database Monitoring comments are injected.
+func (t *pgxTracer) dbmEnabled() bool {
+	m := t.cfg.dbmPropagationMode
+	return m != "" && m != tracer.DBMPropagationModeDisabled
+}
+
+// injectDBM sets up the connections made with config for the Database
+// Monitoring comments to be injected in their queries.
+func injectDBM(config *pgconn.Config) {
+	dial := config.DialFunc
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		c, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &dbmConn{Conn: c}, nil
+	}
+	build := config.BuildFrontend
+	if build == nil {
+		build = func(r io.Reader, w io.Writer) *pgproto3.Frontend {
+			return pgproto3.NewFrontend(r, w)
+		}
+	}
+	config.BuildFrontend = func(r io.Reader, w io.Writer) *pgproto3.Frontend {
+		// w is the connection returned by DialFunc, wrapped if TLS is used
+		if c := dbmConnOf(w); c != nil {
+			w = &dbmWriter{Writer: w, conn: c}
+		}
+		return build(r, w)
+	}
+}
+
+// dbmConn is a connection holding the Database Monitoring comment of the
+// operation in progress, if any.
+type dbmConn struct {
+	net.Conn
+	comment atomic.Pointer[string]
+}
+
+// dbmConnOf returns the dbmConn underlying c, if any.
+func dbmConnOf(c any) *dbmConn {
+	switch c := c.(type) {
+	case *dbmConn:
+		return c
+	case interface{ NetConn() net.Conn }:
+		// *tls.Conn
+		return dbmConnOf(c.NetConn())
+	}
+	return nil
+}
+
+// setDBMComment sets the comment injected in the queries sent on conn, until
+// it's reset with an empty comment.
+func setDBMComment(conn *pgx.Conn, comment string) {
+	if c := dbmConnOf(conn.PgConn().Conn()); c != nil {
+		c.comment.Store(&comment)
+	}
+}
+
+// pendingDBMComment returns the comment injected in the queries sent on conn.
+func pendingDBMComment(conn *pgx.Conn) string {
+	if c := dbmConnOf(conn.PgConn().Conn()); c != nil {
+		if comment := c.comment.Load(); comment != nil {
+			return *comment
+		}
+	}
+	return ""
+}
+
+// dbmPrepareKey is the context key marking the statements prepared outside of
+// a query, whose comment is reset once they're prepared.
+type dbmPrepareKey struct{}
+
+// dbmWriter injects the comment of its connection in the queries of the
+// messages written to it.
+type dbmWriter struct {
+	io.Writer
+	conn *dbmConn
+}
+
+func (w *dbmWriter) Write(b []byte) (int, error) {
+	comment := w.conn.comment.Load()
+	if comment == nil || *comment == "" {
+		return w.Writer.Write(b)
+	}
+	if _, err := w.Writer.Write(commentMessages(b, *comment)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// commentMessages returns the messages of buf with comment injected in the
+// queries of the Query and Parse messages. The frontend always writes whole
+// messages, the startup message which has no type being written before any
+// comment is set.
+func commentMessages(buf []byte, comment string) []byte {
+	out := make([]byte, 0, len(buf)+len(comment)+1)
+	for len(buf) >= 5 {
+		n := int(binary.BigEndian.Uint32(buf[1:5]))
+		if n < 4 || n+1 > len(buf) {
+			break
+		}
+		msg, body := buf[:n+1], buf[5:n+1]
+		buf = buf[n+1:]
+		var m interface {
+			Encode([]byte) ([]byte, error)
+		}
+		switch msg[0] {
+		case 'Q':
+			var q pgproto3.Query
+			if q.Decode(body) == nil && q.String != "" {
+				q.String = comment + " " + q.String
+				m = &q
+			}
+		case 'P':
+			var p pgproto3.Parse
+			if p.Decode(body) == nil && p.Query != "" {
+				p.Query = comment + " " + p.Query
+				m = &p
+			}
+		}
+		if m != nil {
+			if b, err := m.Encode(out); err == nil {
+				out = b
+				continue
+			}
+		}
+		out = append(out, msg...)
+	}
+	return append(out, buf...)
+}
+
+// dbmMode returns the propagation mode of a query executed in mode. Queries
+// cached as prepared statements on the server, or whose description is cached,
+// are only injected the service tags, as the span IDs would make every query
+// different and bust the cache.
+func (t *pgxTracer) dbmMode(mode pgx.QueryExecMode) tracer.DBMPropagationMode {
+	m := t.cfg.dbmPropagationMode
+	if m == tracer.DBMPropagationModeFull && (mode == pgx.QueryExecModeCacheStatement || mode == pgx.QueryExecModeCacheDescribe) {
+		return tracer.DBMPropagationModeService
+	}
+	return m
+}
+
+// dbmComment returns the Database Monitoring comment of a query executed in
+// mode, along with the span ID to use for the query span in full mode.
+func (t *pgxTracer) dbmComment(ctx context.Context, connConfig *pgx.ConnConfig, mode tracer.DBMPropagationMode) (comment string, spanID uint64) {
+	var spanCtx *tracer.SpanContext
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		spanCtx = span.Context()
+	}
+	carrier := tracer.SQLCommentCarrier{
+		Mode:           mode,
+		DBServiceName:  t.cfg.serviceName,
+		PeerDBHostname: connConfig.Host,
+		PeerDBName:     connConfig.Database,
+	}
+	if err := carrier.Inject(spanCtx); err != nil {
+		// this should never happen
+		instr.Logger().Warn("contrib/jackc/pgx.v5: failed to inject query comments: %s", err.Error())
+	}
+	if mode == tracer.DBMPropagationModeFull {
+		spanID = carrier.SpanID
+	}
+	return carrier.Query, spanID
+}
+
+// queryExecMode returns the mode a query with args is executed in.
+func queryExecMode(connConfig *pgx.ConnConfig, args []any) pgx.QueryExecMode {
+	mode := connConfig.DefaultQueryExecMode
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case pgx.QueryExecMode:
+			mode = arg
+		case pgx.QueryRewriter, pgx.QueryResultFormats, pgx.QueryResultFormatsByOID:
+		default:
+			return mode
+		}
+	}
+	return mode
+}
+
+// isStatementName reports whether sql is the name of a prepared statement
+// rather than a query, which is executed without sending the query again.
+func isStatementName(sql string) bool {
+	return !strings.ContainsAny(sql, " \t\r\n")
+}
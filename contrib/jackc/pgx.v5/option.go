@@ -6,6 +6,9 @@
 package pgx
 
 import (
+	"os"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 )
 
@@ -20,6 +23,8 @@ type config struct {
 	poolStats     bool
 	errCheck      func(error) bool
 	statsdClient  instrumentation.StatsdClient
+
+	dbmPropagationMode tracer.DBMPropagationMode
 }
 
 func defaultConfig() *config {
@@ -31,6 +36,8 @@ func defaultConfig() *config {
 		tracePrepare:  true,
 		traceConnect:  true,
 		traceAcquire:  true,
+
+		dbmPropagationMode: tracer.DBMPropagationMode(os.Getenv("DD_DBM_PROPAGATION_MODE")),
 	}
 }
 
@@ -121,3 +128,25 @@ func WithErrCheck(fn func(err error) bool) Option {
 		cfg.errCheck = fn
 	}
 }
+
+// WithDBMPropagation enables injection of tags as sql comments on traced
+// queries, batches and prepared statements, correlating them with Database
+// Monitoring. As pgx doesn't allow tracers to rewrite queries, the comments are
+// injected in the messages sent to the database by the connections created
+// with this package, leaving the queries and batches of the caller untouched.
+//
+// DBMPropagationModeFull injects dynamic values like the span id, trace id and
+// sampled flag, which make every query unique. As this would bust the
+// statement caches of pgx, the queries executed as prepared statements
+// (pgx.QueryExecModeCacheStatement, the default, and
+// pgx.QueryExecModeCacheDescribe) are only injected the service tags, as with
+// DBMPropagationModeService.
+//
+// Note that enabling sql comment propagation results in potentially confidential data (service names)
+// being stored in the databases which can then be accessed by other 3rd parties that have been granted
+// access to the database.
+func WithDBMPropagation(mode tracer.DBMPropagationMode) Option {
+	return func(cfg *config) {
+		cfg.dbmPropagationMode = mode
+	}
+}
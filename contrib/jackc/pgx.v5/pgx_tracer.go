@@ -108,7 +108,15 @@ func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pg
 	if t.wrapped.query != nil {
 		ctx = t.wrapped.query.TraceQueryStart(ctx, conn, data)
 	}
-	opts := t.spanOptions(conn.Config(), operationTypeQuery, data.SQL)
+	connConfig := conn.Config()
+	opts := t.spanOptions(connConfig, operationTypeQuery, data.SQL)
+	if t.dbmEnabled() && !isStatementName(data.SQL) {
+		mode := queryExecMode(connConfig, data.Args)
+		if comment, spanID := t.dbmComment(ctx, connConfig, t.dbmMode(mode)); comment != "" {
+			setDBMComment(conn, comment)
+			opts = append(opts, dbmSpanOptions(spanID)...)
+		}
+	}
 	_, ctx = tracer.StartSpanFromContext(ctx, "pgx.query", opts...)
 	return ctx
 }
@@ -120,6 +128,9 @@ func (t *pgxTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.
 	if t.wrapped.query != nil {
 		t.wrapped.query.TraceQueryEnd(ctx, conn, data)
 	}
+	if t.dbmEnabled() {
+		setDBMComment(conn, "")
+	}
 	span, ok := tracer.SpanFromContext(ctx)
 	if ok {
 		span.SetTag(tagRowsAffected, data.CommandTag.RowsAffected())
@@ -134,9 +145,18 @@ func (t *pgxTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pg
 	if t.wrapped.batch != nil {
 		ctx = t.wrapped.batch.TraceBatchStart(ctx, conn, data)
 	}
-	opts := t.spanOptions(conn.Config(), operationTypeBatch, "",
+	connConfig := conn.Config()
+	opts := t.spanOptions(connConfig, operationTypeBatch, "",
 		tracer.Tag(tagBatchNumQueries, data.Batch.Len()),
 	)
+	if t.dbmEnabled() {
+		// pgx doesn't support setting the mode of the queries of a batch.
+		comment, spanID := t.dbmComment(ctx, connConfig, t.dbmMode(connConfig.DefaultQueryExecMode))
+		if comment != "" {
+			setDBMComment(conn, comment)
+			opts = append(opts, dbmSpanOptions(spanID)...)
+		}
+	}
 	_, ctx = tracer.StartSpanFromContext(ctx, "pgx.batch", opts...)
 	return ctx
 }
@@ -171,6 +191,9 @@ func (t *pgxTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.
 	if t.wrapped.batch != nil {
 		t.wrapped.batch.TraceBatchEnd(ctx, conn, data)
 	}
+	if t.dbmEnabled() {
+		setDBMComment(conn, "")
+	}
 	if t.prevBatchQuery != nil {
 		t.prevBatchQuery.finish()
 		t.prevBatchQuery = nil
@@ -210,8 +233,18 @@ func (t *pgxTracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data
 	if t.wrapped.prepare != nil {
 		ctx = t.wrapped.prepare.TracePrepareStart(ctx, conn, data)
 	}
-	opts := t.spanOptions(conn.Config(), operationTypePrepare, data.SQL)
+	connConfig := conn.Config()
+	opts := t.spanOptions(connConfig, operationTypePrepare, data.SQL)
 	_, ctx = tracer.StartSpanFromContext(ctx, "pgx.prepare", opts...)
+	// The statements prepared by a query are injected the comment of the
+	// query. Others are only injected the service tags, so that all their
+	// executions are correlated with the service.
+	if t.dbmEnabled() && pendingDBMComment(conn) == "" {
+		if comment, _ := t.dbmComment(ctx, connConfig, tracer.DBMPropagationModeService); comment != "" {
+			setDBMComment(conn, comment)
+			ctx = context.WithValue(ctx, dbmPrepareKey{}, true)
+		}
+	}
 	return ctx
 }
 
@@ -222,6 +255,9 @@ func (t *pgxTracer) TracePrepareEnd(ctx context.Context, conn *pgx.Conn, data pg
 	if t.wrapped.prepare != nil {
 		t.wrapped.prepare.TracePrepareEnd(ctx, conn, data)
 	}
+	if _, ok := ctx.Value(dbmPrepareKey{}).(bool); ok {
+		setDBMComment(conn, "")
+	}
 	t.finishSpan(ctx, data.Err)
 }
 
@@ -300,6 +336,18 @@ func (t *pgxTracer) spanOptions(connConfig *pgx.ConnConfig, op operationType, sq
 	return opts
 }
 
+// dbmSpanOptions returns the options of a span whose ID was injected in the
+// Database Monitoring comment of the query, if any.
+func dbmSpanOptions(spanID uint64) []tracer.StartSpanOption {
+	if spanID == 0 {
+		return nil
+	}
+	return []tracer.StartSpanOption{
+		tracer.WithSpanID(spanID),
+		tracer.Tag(keyDBMTraceInjected, true),
+	}
+}
+
 func (t *pgxTracer) finishSpan(ctx context.Context, err error) {
 	span, ok := tracer.SpanFromContext(ctx)
 	if !ok {
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -271,6 +272,105 @@ func TestBatch(t *testing.T) {
 	assert.Equal(t, batchSpan.SpanID(), s.ParentID())
 }
 
+func TestDBMPropagation(t *testing.T) {
+	const currentQuery = "SELECT current_query()"
+
+	connect := func(t *testing.T, mode tracer.DBMPropagationMode, opts ...Option) *pgx.Conn {
+		opts = append(opts, WithService("pgx-db"), WithDBMPropagation(mode))
+		conn, err := Connect(context.Background(), postgresDSN, opts...)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, conn.Close(context.Background()))
+		})
+		return conn
+	}
+
+	t.Run("full", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		conn := connect(t, tracer.DBMPropagationModeFull)
+
+		var query string
+		err := conn.QueryRow(context.Background(), currentQuery, pgx.QueryExecModeExec).Scan(&query)
+		require.NoError(t, err)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		s := spans[1]
+		assert.Equal(t, "pgx.query", s.OperationName())
+		assert.Equal(t, currentQuery, s.Tag(ext.ResourceName))
+		assert.Equal(t, true, s.Tag(keyDBMTraceInjected))
+		assert.Regexp(t, `^/\*dddbs='pgx-db',.*traceparent='00-[0-9a-f]{32}-`+fmt.Sprintf("%016x", s.SpanID())+`-0[01]'.*\*/ `+regexp.QuoteMeta(currentQuery)+`$`, query)
+	})
+
+	t.Run("full-cached-statement", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		conn := connect(t, tracer.DBMPropagationModeFull)
+
+		var query string
+		err := conn.QueryRow(context.Background(), currentQuery).Scan(&query)
+		require.NoError(t, err)
+		assert.Regexp(t, `^/\*dddbs='pgx-db',.*\*/ `+regexp.QuoteMeta(currentQuery)+`$`, query)
+		assert.NotContains(t, query, "traceparent")
+
+		spans := mt.FinishedSpans()
+		require.NotEmpty(t, spans)
+		assert.Nil(t, spans[len(spans)-1].Tag(keyDBMTraceInjected))
+	})
+
+	t.Run("untraced", func(t *testing.T) {
+		conn := connect(t, tracer.DBMPropagationModeFull, WithTraceQuery(false))
+
+		var query string
+		err := conn.QueryRow(context.Background(), currentQuery).Scan(&query)
+		require.NoError(t, err)
+		assert.Equal(t, currentQuery, query)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		conn := connect(t, tracer.DBMPropagationModeService)
+
+		batch := &pgx.Batch{}
+		batch.Queue(currentQuery)
+		for range 2 {
+			var query string
+			err := conn.SendBatch(context.Background(), batch).QueryRow().Scan(&query)
+			require.NoError(t, err)
+			assert.Regexp(t, `^/\*dddbs='pgx-db',[^*]*\*/ `+regexp.QuoteMeta(currentQuery)+`$`, query)
+		}
+		// the batch of the caller is left untouched
+		assert.Equal(t, currentQuery, batch.QueuedQueries[0].SQL)
+
+		spans := mt.FinishedSpans()
+		require.NotEmpty(t, spans)
+		assert.Equal(t, currentQuery, spans[0].Tag(ext.ResourceName))
+	})
+
+	t.Run("prepare", func(t *testing.T) {
+		conn := connect(t, tracer.DBMPropagationModeFull)
+
+		_, err := conn.Prepare(context.Background(), "current_query", currentQuery)
+		require.NoError(t, err)
+		var query string
+		err = conn.QueryRow(context.Background(), "current_query").Scan(&query)
+		require.NoError(t, err)
+		assert.Regexp(t, `^/\*dddbs='pgx-db',.*\*/ `+regexp.QuoteMeta(currentQuery)+`$`, query)
+		assert.NotContains(t, query, "traceparent")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		conn := connect(t, tracer.DBMPropagationModeDisabled)
+
+		var query string
+		err := conn.QueryRow(context.Background(), currentQuery).Scan(&query)
+		require.NoError(t, err)
+		assert.Equal(t, currentQuery, query)
+	})
+}
+
 func TestCopyFrom(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
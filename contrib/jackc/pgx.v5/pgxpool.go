@@ -25,6 +25,9 @@ func NewPoolWithConfig(ctx context.Context, config *pgxpool.Config, opts ...Opti
 
 	tracer := wrapPgxTracer(config.ConnConfig.Tracer, opts...)
 	config.ConnConfig.Tracer = tracer
+	if tracer.dbmEnabled() {
+		injectDBM(&config.ConnConfig.Config)
+	}
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, err
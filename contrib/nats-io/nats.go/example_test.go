@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats_test
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	natstrace "github.com/DataDog/dd-trace-go/contrib/nats-io/nats.go/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nc.Close()
+	conn := natstrace.WrapConn(nc, natstrace.WithService("my-nats"))
+
+	// The messages passed to the handler are traced, the context holding
+	// their span.
+	sub, err := conn.Subscribe("greet", func(ctx context.Context, msg *nats.Msg) {
+		if err := conn.RespondMsg(ctx, msg, &nats.Msg{Data: []byte("hello " + string(msg.Data))}); err != nil {
+			log.Print(err)
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	reply, err := conn.Request("greet", []byte("world"), time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Print(string(reply.Data))
+}
+
+func ExampleWrapConsumer() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := natstrace.WrapJetStream(js, natstrace.WithDataStreams()).Publish(ctx, "orders.new", []byte("order")); err != nil {
+		log.Fatal(err)
+	}
+
+	c, err := js.Consumer(ctx, "ORDERS", "workers")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cc, err := natstrace.WrapConsumer(c, natstrace.WithDataStreams()).ConsumeWithContext(func(ctx context.Context, msg jetstream.Msg) {
+		// The span of the message is a child of the span which published it.
+		span, _ := tracer.StartSpanFromContext(ctx, "process.order")
+		defer span.Finish()
+		if err := msg.Ack(); err != nil {
+			span.SetTag("error", err)
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cc.Stop()
+}
@@ -0,0 +1,185 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+// JetStream wraps a jetstream.JetStream, tracing the messages it publishes.
+// The consumers it returns are not traced, see WrapConsumer.
+type JetStream struct {
+	jetstream.JetStream
+	cfg *config
+}
+
+// WrapJetStream returns a traced version of js.
+func WrapJetStream(js jetstream.JetStream, opts ...Option) *JetStream {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping JetStream: %#v", cfg)
+	return &JetStream{JetStream: js, cfg: cfg}
+}
+
+// Publish publishes payload to subject like jetstream.JetStream.Publish, the
+// span of the message being a child of the span of ctx.
+func (js *JetStream) Publish(ctx context.Context, subject string, payload []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	return js.PublishMsg(ctx, &nats.Msg{Subject: subject, Data: payload}, opts...)
+}
+
+// PublishMsg publishes msg like jetstream.JetStream.PublishMsg, the span of
+// the message being a child of the span of ctx. The span context is injected
+// in the message headers, and the span is finished once the message is
+// acknowledged by the server.
+func (js *JetStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	span, ctx := messaging.StartProduceSpan(ctx, js.cfg.messaging(), message(msg))
+	ack, err := js.JetStream.PublishMsg(ctx, msg, opts...)
+	if ack != nil {
+		span.SetTag("nats.stream", ack.Stream)
+	}
+	span.Finish(tracer.WithError(err))
+	return ack, err
+}
+
+// MessageHandler is a handler of the messages received by a JetStream
+// consumer, passed a context holding the span of the message.
+type MessageHandler func(ctx context.Context, msg jetstream.Msg)
+
+// Consumer wraps a jetstream.Consumer, tracing the messages it receives with
+// Consume, ConsumeWithContext and the Fetch methods.
+type Consumer struct {
+	jetstream.Consumer
+	cfg *config
+}
+
+var _ jetstream.Consumer = (*Consumer)(nil)
+
+// WrapConsumer returns a traced version of c.
+func WrapConsumer(c jetstream.Consumer, opts ...Option) *Consumer {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping Consumer: %#v", cfg)
+	return &Consumer{Consumer: c, cfg: cfg}
+}
+
+// Consume receives the messages of the consumer like jetstream.Consumer.Consume,
+// tracing the messages passed to h. The span of a message is a child of the
+// span which published it, and is finished when h returns. Use
+// ConsumeWithContext to pass the span to the handler.
+func (c *Consumer) Consume(h jetstream.MessageHandler, opts ...jetstream.PullConsumeOpt) (jetstream.ConsumeContext, error) {
+	return c.ConsumeWithContext(func(_ context.Context, msg jetstream.Msg) { h(msg) }, opts...)
+}
+
+// ConsumeWithContext is like Consume, h being passed a context holding the
+// span of the message.
+func (c *Consumer) ConsumeWithContext(h MessageHandler, opts ...jetstream.PullConsumeOpt) (jetstream.ConsumeContext, error) {
+	cfg := c.messaging()
+	return c.Consumer.Consume(func(msg jetstream.Msg) {
+		span, ctx := messaging.StartConsumeSpan(context.Background(), cfg, jetStreamMessage(msg))
+		defer span.Finish()
+		h(ctx, msg)
+	}, opts...)
+}
+
+// Fetch receives up to batch messages like jetstream.Consumer.Fetch. The
+// messages are traced by a single span, linked to the spans which published
+// them, which is finished once all the messages of the returned batch are
+// received.
+func (c *Consumer) Fetch(batch int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	start := time.Now()
+	b, err := c.Consumer.Fetch(batch, opts...)
+	return c.traceBatch(start, b, err)
+}
+
+// FetchBytes is like Fetch, receiving up to maxBytes bytes of messages like
+// jetstream.Consumer.FetchBytes.
+func (c *Consumer) FetchBytes(maxBytes int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	start := time.Now()
+	b, err := c.Consumer.FetchBytes(maxBytes, opts...)
+	return c.traceBatch(start, b, err)
+}
+
+// FetchNoWait is like Fetch, receiving the messages which are already
+// available like jetstream.Consumer.FetchNoWait.
+func (c *Consumer) FetchNoWait(batch int) (jetstream.MessageBatch, error) {
+	start := time.Now()
+	b, err := c.Consumer.FetchNoWait(batch)
+	return c.traceBatch(start, b, err)
+}
+
+// traceBatch returns b, whose messages are traced by a span started at start.
+func (c *Consumer) traceBatch(start time.Time, b jetstream.MessageBatch, err error) (jetstream.MessageBatch, error) {
+	var stream string
+	if info := c.CachedInfo(); info != nil {
+		stream = info.Stream
+	}
+	cfg := c.messaging()
+	if err != nil {
+		span, _ := messaging.StartBatchConsumeSpan(context.Background(), cfg, stream, nil,
+			tracer.StartTime(start),
+			tracer.Tag("nats.batch_size", 0),
+		)
+		span.Finish(tracer.WithError(err))
+		return b, err
+	}
+	tb := &messageBatch{
+		MessageBatch: b,
+		msgs:         make(chan jetstream.Msg, cap(b.Messages())),
+	}
+	go func() {
+		defer close(tb.msgs)
+		var messages []messaging.Message
+		for msg := range b.Messages() {
+			messages = append(messages, jetStreamMessage(msg))
+			tb.msgs <- msg
+		}
+		span, _ := messaging.StartBatchConsumeSpan(context.Background(), cfg, stream, messages,
+			tracer.StartTime(start),
+			tracer.Tag("nats.batch_size", len(messages)),
+		)
+		span.Finish(tracer.WithError(b.Error()))
+	}()
+	return tb, nil
+}
+
+// messageBatch is a jetstream.MessageBatch whose span is finished once its
+// messages are received.
+type messageBatch struct {
+	jetstream.MessageBatch
+	msgs chan jetstream.Msg
+}
+
+// Messages returns the channel of the messages of the batch, closed once the
+// span of the batch is finished.
+func (b *messageBatch) Messages() <-chan jetstream.Msg {
+	return b.msgs
+}
+
+// messaging returns the configuration of the spans, the consumer name being
+// the consumer group of the Data Streams Monitoring checkpoints.
+func (c *Consumer) messaging() messaging.Config {
+	if info := c.CachedInfo(); info != nil && info.Name != "" {
+		return c.cfg.messaging("group:" + info.Name)
+	}
+	return c.cfg.messaging()
+}
+
+// jetStreamMessage describes msg for the messaging package.
+func jetStreamMessage(msg jetstream.Msg) messaging.Message {
+	m := messaging.Message{
+		Destination: msg.Subject(),
+		PayloadSize: int64(len(msg.Data())),
+	}
+	if h := msg.Headers(); h != nil {
+		m.Carrier = messaging.HeadersCarrier(h)
+	}
+	return m
+}
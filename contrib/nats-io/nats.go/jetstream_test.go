@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// newConsumer creates the stream ORDERS of the subjects orders.* and its
+// consumer named workers.
+func newConsumer(t *testing.T, js jetstream.JetStream) jetstream.Consumer {
+	t.Helper()
+	ctx := context.Background()
+	_, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.*"}})
+	require.NoError(t, err)
+	c, err := js.CreateConsumer(ctx, "ORDERS", jetstream.ConsumerConfig{Durable: "workers"})
+	require.NoError(t, err)
+	return c
+}
+
+func TestJetStreamConsume(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	js, err := jetstream.New(runServer(t))
+	require.NoError(t, err)
+	tjs := WrapJetStream(js)
+	c := WrapConsumer(newConsumer(t, js))
+
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	ack, err := tjs.Publish(ctx, "orders.new", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "ORDERS", ack.Stream)
+	parent.Finish()
+
+	received := make(chan jetstream.Msg, 1)
+	cc, err := c.ConsumeWithContext(func(ctx context.Context, msg jetstream.Msg) {
+		_, ok := tracer.SpanFromContext(ctx)
+		assert.True(t, ok)
+		assert.NoError(t, msg.Ack())
+		received <- msg
+	})
+	require.NoError(t, err)
+	defer cc.Stop()
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "hello", string(msg.Data()))
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+	spans := waitSpans(t, mt, 3)
+	ps, cs := spans["nats.produce"], spans["nats.consume"]
+	require.NotNil(t, ps)
+	require.NotNil(t, cs)
+	assert.Equal(t, spans["parent"].SpanID(), ps.ParentID())
+	assert.Equal(t, "orders.new", ps.Tag(ext.ResourceName))
+	assert.Equal(t, "ORDERS", ps.Tag("nats.stream"))
+	assert.Equal(t, ext.SpanKindProducer, ps.Tag(ext.SpanKind))
+	assert.Equal(t, ps.SpanID(), cs.ParentID())
+	assert.Equal(t, ps.TraceID(), cs.TraceID())
+	assert.Equal(t, "orders.new", cs.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, cs.Tag(ext.SpanKind))
+}
+
+func TestJetStreamFetch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	js, err := jetstream.New(runServer(t))
+	require.NoError(t, err)
+	tjs := WrapJetStream(js, WithDataStreams())
+	c := WrapConsumer(newConsumer(t, js), WithDataStreams())
+
+	for _, subj := range []string{"orders.new", "orders.paid"} {
+		_, err := tjs.Publish(context.Background(), subj, []byte(subj))
+		require.NoError(t, err)
+	}
+	// published without tracing
+	_, err = js.Publish(context.Background(), "orders.new", nil)
+	require.NoError(t, err)
+
+	b, err := c.Fetch(3, jetstream.FetchMaxWait(5*time.Second))
+	require.NoError(t, err)
+	var msgs []jetstream.Msg
+	for msg := range b.Messages() {
+		msgs = append(msgs, msg)
+	}
+	require.NoError(t, b.Error())
+	require.Len(t, msgs, 3)
+	for _, msg := range msgs {
+		require.NoError(t, msg.Ack())
+	}
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	batch := spans[2]
+	assert.Equal(t, "nats.consume", batch.OperationName())
+	assert.Equal(t, "ORDERS", batch.Tag(ext.ResourceName))
+	assert.Equal(t, float64(3), batch.Tag("nats.batch_size"))
+	links := batch.Links()
+	require.Len(t, links, 2)
+	for i, l := range links {
+		assert.Equal(t, spans[i].SpanID(), l.SpanID)
+	}
+
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), headersCarrier(msgs[0].Headers())))
+	require.True(t, ok)
+	want, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:orders.new", "type:nats")
+	wantPathway, _ := datastreams.PathwayFromContext(want)
+	assert.Equal(t, wantPathway.GetHash(), got.GetHash())
+
+	t.Run("error", func(t *testing.T) {
+		mt.Reset()
+		_, err := c.Fetch(1, jetstream.FetchMaxWait(-1))
+		assert.Error(t, err)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+	})
+}
@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package nats provides functions to trace the nats-io/nats.go package (https://github.com/nats-io/nats.go),
+// including the JetStream API of its jetstream package.
+//
+// The trace context is propagated in the headers of the messages, and the
+// Data Streams Monitoring checkpoints are set when enabled with WithDataStreams.
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageNATSIONATSGo)
+}
+
+// MsgHandler is a handler of the messages received by a subscription, passed
+// a context holding the span of the message.
+type MsgHandler func(ctx context.Context, msg *nats.Msg)
+
+// Conn wraps a nats.Conn, tracing the messages it publishes and the messages
+// received by its subscriptions.
+type Conn struct {
+	*nats.Conn
+	cfg *config
+}
+
+// WrapConn returns a traced version of nc.
+func WrapConn(nc *nats.Conn, opts ...Option) *Conn {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping Conn: %#v", cfg)
+	return &Conn{Conn: nc, cfg: cfg}
+}
+
+// Publish publishes data to subj like nats.Conn.Publish.
+func (c *Conn) Publish(subj string, data []byte) error {
+	return c.PublishMsgWithContext(context.Background(), &nats.Msg{Subject: subj, Data: data})
+}
+
+// PublishMsg publishes msg like nats.Conn.PublishMsg.
+func (c *Conn) PublishMsg(msg *nats.Msg) error {
+	return c.PublishMsgWithContext(context.Background(), msg)
+}
+
+// PublishWithContext publishes data to subj, the span of the message being a
+// child of the span of ctx.
+func (c *Conn) PublishWithContext(ctx context.Context, subj string, data []byte) error {
+	return c.PublishMsgWithContext(ctx, &nats.Msg{Subject: subj, Data: data})
+}
+
+// PublishMsgWithContext publishes msg, the span of the message being a child
+// of the span of ctx. The span context is injected in the message headers.
+func (c *Conn) PublishMsgWithContext(ctx context.Context, msg *nats.Msg) error {
+	span, _ := messaging.StartProduceSpan(ctx, c.cfg.messaging(), message(msg))
+	err := c.Conn.PublishMsg(msg)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// RespondMsg publishes resp as the reply to the request req received by a
+// subscription, the span of the reply being a child of the span of ctx.
+func (c *Conn) RespondMsg(ctx context.Context, req, resp *nats.Msg) error {
+	if req.Reply == "" {
+		return nats.ErrMsgNoReply
+	}
+	resp.Subject = req.Reply
+	return c.PublishMsgWithContext(ctx, resp)
+}
+
+// Request sends a request with data to subj and waits for the reply like
+// nats.Conn.Request.
+func (c *Conn) Request(subj string, data []byte, timeout time.Duration) (*nats.Msg, error) {
+	return c.RequestMsg(&nats.Msg{Subject: subj, Data: data}, timeout)
+}
+
+// RequestMsg sends the request msg and waits for the reply like
+// nats.Conn.RequestMsg.
+func (c *Conn) RequestMsg(msg *nats.Msg, timeout time.Duration) (*nats.Msg, error) {
+	return c.request(context.Background(), msg, func() (*nats.Msg, error) {
+		return c.Conn.RequestMsg(msg, timeout)
+	})
+}
+
+// RequestWithContext sends a request with data to subj and waits for the
+// reply like nats.Conn.RequestWithContext.
+func (c *Conn) RequestWithContext(ctx context.Context, subj string, data []byte) (*nats.Msg, error) {
+	return c.RequestMsgWithContext(ctx, &nats.Msg{Subject: subj, Data: data})
+}
+
+// RequestMsgWithContext sends the request msg and waits for the reply like
+// nats.Conn.RequestMsgWithContext.
+func (c *Conn) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	return c.request(ctx, msg, func() (*nats.Msg, error) {
+		return c.Conn.RequestMsgWithContext(ctx, msg)
+	})
+}
+
+// request traces the request msg until its reply is received.
+func (c *Conn) request(ctx context.Context, msg *nats.Msg, do func() (*nats.Msg, error)) (*nats.Msg, error) {
+	span, _ := messaging.StartProduceSpan(ctx, c.cfg.messaging(), message(msg))
+	resp, err := do()
+	span.Finish(tracer.WithError(err))
+	return resp, err
+}
+
+// Subscribe subscribes to subj like nats.Conn.Subscribe, tracing the messages
+// passed to h. The span of a message is a child of the span which published it,
+// and is finished when h returns.
+func (c *Conn) Subscribe(subj string, h MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.Subscribe(subj, c.wrapHandler(h))
+}
+
+// QueueSubscribe subscribes to subj in the queue group queue like
+// nats.Conn.QueueSubscribe, tracing the messages passed to h. The queue group
+// is the consumer group of the Data Streams Monitoring checkpoints.
+func (c *Conn) QueueSubscribe(subj, queue string, h MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.QueueSubscribe(subj, queue, c.wrapHandler(h, "group:"+queue))
+}
+
+func (c *Conn) wrapHandler(h MsgHandler, edgeTags ...string) nats.MsgHandler {
+	cfg := c.cfg.messaging(edgeTags...)
+	return func(msg *nats.Msg) {
+		span, ctx := messaging.StartConsumeSpan(context.Background(), cfg, message(msg))
+		defer span.Finish()
+		h(ctx, msg)
+	}
+}
+
+// message describes msg for the messaging package, creating its headers so
+// that the span context can be injected.
+func message(msg *nats.Msg) messaging.Message {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	return messaging.Message{
+		Destination: msg.Subject,
+		Carrier:     messaging.HeadersCarrier(msg.Header),
+		PayloadSize: int64(len(msg.Data)),
+	}
+}
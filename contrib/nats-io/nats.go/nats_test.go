@@ -0,0 +1,208 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+// runServer starts an embedded NATS server with JetStream enabled and returns
+// a connection to it.
+func runServer(t *testing.T) *nats.Conn {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	s := natsserver.RunServer(&opts)
+	t.Cleanup(s.Shutdown)
+
+	nc, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	testutils.SetGlobalServiceName(t, "my-service")
+	nc := WrapConn(runServer(t))
+
+	received := make(chan *nats.Msg, 1)
+	sub, err := nc.Subscribe("orders.*", func(ctx context.Context, msg *nats.Msg) {
+		span, ok := tracer.SpanFromContext(ctx)
+		assert.True(t, ok)
+		span.SetTag("handled", true)
+		received <- msg
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	require.NoError(t, nc.PublishWithContext(ctx, "orders.new", []byte("hello")))
+	parent.Finish()
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "hello", string(msg.Data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+	spans := waitSpans(t, mt, 3)
+	ps, cs := spans["nats.produce"], spans["nats.consume"]
+	require.NotNil(t, ps)
+	require.NotNil(t, cs)
+
+	assert.Equal(t, spans["parent"].SpanID(), ps.ParentID())
+	assert.Equal(t, "nats", ps.Tag(ext.ServiceName))
+	assert.Equal(t, "orders.new", ps.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindProducer, ps.Tag(ext.SpanKind))
+	assert.Equal(t, "nats", ps.Tag(ext.MessagingSystem))
+	assert.Equal(t, "orders.new", ps.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, "nats-io/nats.go", ps.Tag(ext.Component))
+	assert.Equal(t, "nats-io/nats.go", ps.Integration())
+
+	assert.Equal(t, ps.SpanID(), cs.ParentID())
+	assert.Equal(t, ps.TraceID(), cs.TraceID())
+	assert.Equal(t, "my-service", cs.Tag(ext.ServiceName))
+	assert.Equal(t, "orders.new", cs.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, cs.Tag(ext.SpanKind))
+	assert.Equal(t, "true", cs.Tag("handled"))
+}
+
+func TestRequestReply(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	nc := WrapConn(runServer(t), WithService("nats-svc"))
+
+	sub, err := nc.QueueSubscribe("echo", "workers", func(ctx context.Context, msg *nats.Msg) {
+		assert.NoError(t, nc.RespondMsg(ctx, msg, &nats.Msg{Data: msg.Data}))
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	resp, err := nc.Request("echo", []byte("ping"), 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(resp.Data))
+
+	// the request, the handler and the reply
+	waitSpans(t, mt, 3)
+	var request, handler, reply *mocktracer.Span
+	for _, s := range mt.FinishedSpans() {
+		switch {
+		case s.OperationName() == "nats.consume":
+			handler = s
+		case s.Tag(ext.ResourceName) == "echo":
+			request = s
+		default:
+			reply = s
+		}
+	}
+	require.NotNil(t, request)
+	require.NotNil(t, handler)
+	require.NotNil(t, reply)
+	assert.Equal(t, request.SpanID(), handler.ParentID())
+	assert.Equal(t, handler.SpanID(), reply.ParentID())
+	assert.Equal(t, "nats-svc", request.Tag(ext.ServiceName))
+	assert.Equal(t, "nats-svc", handler.Tag(ext.ServiceName))
+
+	t.Run("error", func(t *testing.T) {
+		mt.Reset()
+		_, err := nc.Request("nobody", nil, time.Second)
+		require.ErrorIs(t, err, nats.ErrNoResponders)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+	})
+
+	t.Run("no-reply", func(t *testing.T) {
+		assert.ErrorIs(t, nc.RespondMsg(context.Background(), &nats.Msg{}, &nats.Msg{}), nats.ErrMsgNoReply)
+	})
+}
+
+func TestNamingSchema(t *testing.T) {
+	t.Setenv("DD_TRACE_SPAN_ATTRIBUTE_SCHEMA", "v1")
+	instrumentation.ReloadConfig()
+	defer instrumentation.ReloadConfig()
+	testutils.SetGlobalServiceName(t, "my-service")
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	nc := WrapConn(runServer(t))
+
+	received := make(chan struct{})
+	sub, err := nc.Subscribe("orders", func(context.Context, *nats.Msg) { close(received) })
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	require.NoError(t, nc.Publish("orders", nil))
+	<-received
+
+	spans := waitSpans(t, mt, 2)
+	require.NotNil(t, spans["nats.send"])
+	require.NotNil(t, spans["nats.process"])
+	assert.Equal(t, "my-service", spans["nats.send"].Tag(ext.ServiceName))
+	assert.Equal(t, "my-service", spans["nats.process"].Tag(ext.ServiceName))
+}
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	nc := WrapConn(runServer(t), WithDataStreams())
+
+	received := make(chan *nats.Msg, 1)
+	sub, err := nc.QueueSubscribe("orders", "workers", func(ctx context.Context, msg *nats.Msg) {
+		_, ok := datastreams.PathwayFromContext(ctx)
+		assert.True(t, ok)
+		received <- msg
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, nc.PublishMsg(&nats.Msg{Subject: "orders", Data: []byte("hello")}))
+	msg := <-received
+
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), headersCarrier(msg.Header)))
+	require.True(t, ok)
+	want, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:orders", "type:nats")
+	wantPathway, _ := datastreams.PathwayFromContext(want)
+	assert.Equal(t, wantPathway.GetHash(), got.GetHash())
+}
+
+// headersCarrier returns a carrier of the headers of a received message.
+func headersCarrier(h nats.Header) tracer.TextMapCarrier {
+	c := tracer.TextMapCarrier{}
+	for k, v := range h {
+		c[k] = v[0]
+	}
+	return c
+}
+
+// waitSpans waits for n spans to be finished, and returns them by name.
+func waitSpans(t *testing.T, mt mocktracer.Tracer, n int) map[string]*mocktracer.Span {
+	t.Helper()
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == n }, 5*time.Second, 10*time.Millisecond)
+	spans := make(map[string]*mocktracer.Span, n)
+	for _, s := range mt.FinishedSpans() {
+		spans[s.OperationName()] = s
+	}
+	return spans
+}
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+type config struct {
+	serviceName        string
+	dataStreamsEnabled bool
+}
+
+// Option describes options for the NATS integration.
+type Option func(cfg *config)
+
+func defaultConfig() *config {
+	return &config{
+		dataStreamsEnabled: instr.DataStreamsEnabled(),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// messaging returns the configuration of the spans and checkpoints, adding
+// edgeTags to the edge tags of the checkpoints.
+func (cfg *config) messaging(edgeTags ...string) messaging.Config {
+	return messaging.Config{
+		System:      "nats",
+		Component:   string(instrumentation.PackageNATSIONATSGo),
+		Instr:       instr,
+		ServiceName: cfg.serviceName,
+		DataStreams: cfg.dataStreamsEnabled,
+		EdgeTags:    edgeTags,
+	}
+}
+
+// WithService sets the service name of the producer and consumer spans.
+func WithService(serviceName string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = serviceName
+	}
+}
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() Option {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
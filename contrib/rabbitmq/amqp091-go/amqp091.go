@@ -0,0 +1,282 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package amqp091 provides functions to trace the rabbitmq/amqp091-go package (https://github.com/rabbitmq/amqp091-go).
+//
+// The trace context is propagated in the headers of the messages, and the
+// Data Streams Monitoring checkpoints are set when enabled with WithDataStreams.
+package amqp091
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageRabbitMQAMQP091Go)
+}
+
+// Channel wraps an amqp.Channel, tracing the messages it publishes and
+// receives.
+type Channel struct {
+	*amqp.Channel
+	cfg *config
+}
+
+// WrapChannel returns a traced version of ch.
+func WrapChannel(ch *amqp.Channel, opts ...Option) *Channel {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	instr.Logger().Debug("contrib/rabbitmq/amqp091-go: Wrapping Channel: %#v", cfg)
+	return &Channel{Channel: ch, cfg: cfg}
+}
+
+// Delivery is a message received from a queue, along with the context holding
+// its span.
+type Delivery struct {
+	amqp.Delivery
+	ctx     context.Context
+	pending *pendingSpans
+}
+
+// Context returns the context holding the span of the delivery, to trace its
+// processing.
+func (d Delivery) Context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
+// Ack acknowledges the delivery like amqp.Delivery.Ack, finishing its span,
+// and the spans of the previous deliveries of the consumer if multiple is true.
+func (d Delivery) Ack(multiple bool) error {
+	err := d.Delivery.Ack(multiple)
+	d.finish(multiple, err)
+	return err
+}
+
+// Nack negatively acknowledges the delivery like amqp.Delivery.Nack, finishing
+// its span, and the spans of the previous deliveries of the consumer if
+// multiple is true.
+func (d Delivery) Nack(multiple, requeue bool) error {
+	err := d.Delivery.Nack(multiple, requeue)
+	d.finish(multiple, err)
+	return err
+}
+
+// Reject rejects the delivery like amqp.Delivery.Reject, finishing its span.
+func (d Delivery) Reject(requeue bool) error {
+	err := d.Delivery.Reject(requeue)
+	d.finish(false, err)
+	return err
+}
+
+// Finish finishes the span of the delivery, for the deliveries which are
+// acknowledged automatically. It's a no-op if the span is already finished.
+func (d Delivery) Finish() {
+	d.finish(false, nil)
+}
+
+func (d Delivery) finish(multiple bool, err error) {
+	if d.pending == nil {
+		return
+	}
+	d.pending.finish(d.DeliveryTag, multiple, err)
+}
+
+// pendingSpans holds the spans of the deliveries of a consumer which are not
+// finished yet, by delivery tag. It's safe for concurrent use, as the
+// deliveries may be processed by several goroutines.
+type pendingSpans struct {
+	mu    sync.Mutex
+	spans map[uint64]*tracer.Span
+}
+
+func (p *pendingSpans) add(tag uint64, span *tracer.Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spans[tag] = span
+}
+
+// finish finishes the span of the delivery tag with err, and the spans of the
+// deliveries with a lower tag if multiple is true.
+func (p *pendingSpans) finish(tag uint64, multiple bool, err error) {
+	p.mu.Lock()
+	var spans []*tracer.Span
+	for t, span := range p.spans {
+		if t == tag || (multiple && t < tag) {
+			spans = append(spans, span)
+			delete(p.spans, t)
+		}
+	}
+	p.mu.Unlock()
+	for _, span := range spans {
+		span.Finish(tracer.WithError(err))
+	}
+}
+
+// finishAll finishes the spans of all the deliveries.
+func (p *pendingSpans) finishAll() {
+	p.mu.Lock()
+	spans := p.spans
+	p.spans = make(map[uint64]*tracer.Span)
+	p.mu.Unlock()
+	for _, span := range spans {
+		span.Finish()
+	}
+}
+
+// Publish publishes msg like amqp.Channel.Publish.
+func (ch *Channel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	return ch.PublishWithContext(context.Background(), exchange, key, mandatory, immediate, msg)
+}
+
+// PublishWithContext publishes msg like amqp.Channel.PublishWithContext, the
+// span of the message being a child of the span of ctx. The span context is
+// injected in the message headers.
+func (ch *Channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	// copy the headers so that the table of the caller isn't modified
+	headers := make(amqp.Table, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+	cfg := ch.cfg.messaging("exchange:"+exchange, "has_routing_key:"+strconv.FormatBool(key != ""))
+	span, _ := messaging.StartProduceSpan(ctx, cfg, messaging.Message{
+		Destination: destination(exchange, key),
+		Carrier:     tableCarrier(msg.Headers),
+		PayloadSize: int64(len(msg.Body)),
+	}, spanTags(exchange, key, "")...)
+	err := ch.Channel.PublishWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// Consume starts delivering the messages of queue like amqp.Channel.Consume.
+// See ConsumeWithContext.
+func (ch *Channel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan Delivery, error) {
+	return ch.ConsumeWithContext(context.Background(), queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+}
+
+// ConsumeWithContext starts delivering the messages of queue like
+// amqp.Channel.ConsumeWithContext, tracing the deliveries. The span of a
+// delivery is a child of the span which published the message. It's finished
+// when the delivery is acknowledged, negatively acknowledged or rejected,
+// including by the acknowledgement of a later delivery of the consumer with
+// multiple set, or when Delivery.Finish is called. The spans which are still
+// unfinished when the deliveries are closed are finished then. The consumers
+// which acknowledge the deliveries automatically should call Delivery.Finish
+// once a delivery is processed, or use WithFinishOnNextDelivery.
+func (ch *Channel) ConsumeWithContext(ctx context.Context, queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan Delivery, error) {
+	deliveries, err := ch.Channel.ConsumeWithContext(ctx, queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Delivery)
+	pending := &pendingSpans{spans: make(map[uint64]*tracer.Span)}
+	go func() {
+		defer close(out)
+		defer pending.finishAll()
+		var prev uint64
+		for d := range deliveries {
+			span, dctx := ch.startConsumeSpan(queue, d)
+			pending.add(d.DeliveryTag, span)
+			out <- Delivery{Delivery: d, ctx: dctx, pending: pending}
+			if ch.cfg.finishOnNextDelivery && prev != 0 {
+				// the consumer read the delivery, so it's done with the previous one
+				pending.finish(prev, false, nil)
+			}
+			prev = d.DeliveryTag
+		}
+	}()
+	return out, nil
+}
+
+// Get receives a single message from queue like amqp.Channel.Get. The span of
+// the delivery is a child of the span which published the message, and covers
+// the Get call. No span is started if the queue is empty.
+func (ch *Channel) Get(queue string, autoAck bool) (Delivery, bool, error) {
+	start := time.Now()
+	d, ok, err := ch.Channel.Get(queue, autoAck)
+	if !ok && err == nil {
+		return Delivery{Delivery: d}, ok, err
+	}
+	span, ctx := ch.startConsumeSpan(queue, d, tracer.StartTime(start))
+	span.Finish(tracer.WithError(err))
+	return Delivery{Delivery: d, ctx: ctx}, ok, err
+}
+
+func (ch *Channel) startConsumeSpan(queue string, d amqp.Delivery, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	msg := messaging.Message{
+		Destination: queue,
+		PayloadSize: int64(len(d.Body)),
+	}
+	if d.Headers != nil {
+		msg.Carrier = tableCarrier(d.Headers)
+	}
+	opts = append(spanTags(d.Exchange, d.RoutingKey, queue), opts...)
+	return messaging.StartConsumeSpan(context.Background(), ch.cfg.messaging(), msg, opts...)
+}
+
+// destination returns the destination of a message published to exchange
+// with key, the default exchange routing the messages to the queue named key.
+func destination(exchange, key string) string {
+	if exchange == "" {
+		return key
+	}
+	return exchange
+}
+
+// spanTags returns the tags of the exchange, routing key and queue of a
+// message, named according to the naming schema.
+func spanTags(exchange, key, queue string) []tracer.StartSpanOption {
+	exchangeTag, keyTag, queueTag := ext.AMQPExchange, ext.AMQPRoutingKey, ext.AMQPQueue
+	if instr.NamingSchemaV1() {
+		exchangeTag, keyTag, queueTag = ext.MessagingRabbitMQExchange, ext.MessagingRabbitMQRoutingKey, ext.MessagingRabbitMQQueue
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.Tag(exchangeTag, exchange),
+		tracer.Tag(keyTag, key),
+	}
+	if queue != "" {
+		opts = append(opts, tracer.Tag(queueTag, queue))
+	}
+	return opts
+}
+
+// tableCarrier returns a carrier of the string values of the headers t.
+func tableCarrier(t amqp.Table) messaging.Carrier {
+	return messaging.NewCarrier(
+		func(handler func(key, val string) error) error {
+			for k, v := range t {
+				s, ok := v.(string)
+				if !ok {
+					continue
+				}
+				if err := handler(k, s); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(key, val string) {
+			t[key] = val
+		},
+	)
+}
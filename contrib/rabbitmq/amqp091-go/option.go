@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package amqp091
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
+)
+
+type config struct {
+	serviceName          string
+	dataStreamsEnabled   bool
+	finishOnNextDelivery bool
+}
+
+// Option describes options for the RabbitMQ integration.
+type Option func(cfg *config)
+
+func defaultConfig() *config {
+	return &config{
+		dataStreamsEnabled: instr.DataStreamsEnabled(),
+	}
+}
+
+// messaging returns the configuration of the spans and checkpoints, adding
+// edgeTags to the edge tags of the checkpoints.
+func (cfg *config) messaging(edgeTags ...string) messaging.Config {
+	return messaging.Config{
+		System:      ext.MessagingSystemRabbitMQ,
+		Component:   string(instrumentation.PackageRabbitMQAMQP091Go),
+		Instr:       instr,
+		ServiceName: cfg.serviceName,
+		DataStreams: cfg.dataStreamsEnabled,
+		EdgeTags:    edgeTags,
+	}
+}
+
+// WithService sets the service name of the producer and consumer spans.
+func WithService(serviceName string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = serviceName
+	}
+}
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() Option {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
+
+// WithFinishOnNextDelivery finishes the span of a delivery once the next
+// delivery is read from the channel returned by Channel.Consume, for the
+// consumers which acknowledge the deliveries automatically without calling
+// Delivery.Finish. It's only accurate when the deliveries are read and
+// processed one at a time, by a single goroutine.
+func WithFinishOnNextDelivery() Option {
+	return func(cfg *config) {
+		cfg.finishOnNextDelivery = true
+	}
+}
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+type testMessage struct {
+	topic   string
+	headers []Header
+}
+
+func (m *testMessage) GetValue() []byte            { return nil }
+func (m *testMessage) GetKey() []byte              { return nil }
+func (m *testMessage) GetHeaders() []Header        { return m.headers }
+func (m *testMessage) SetHeaders(headers []Header) { m.headers = headers }
+func (m *testMessage) GetTopic() string            { return m.topic }
+func (m *testMessage) GetPartition() int           { return 0 }
+func (m *testMessage) GetOffset() int64            { return 0 }
+
+func TestConsumeBatch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewTracer(KafkaConfig{BootstrapServers: "localhost:9092"}, WithBatchConsume(2))
+	require.True(t, tr.BatchConsumeEnabled())
+
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	var producers []*tracer.Span
+	for range 3 {
+		msg := &testMessage{topic: "gotest"}
+		span := tracer.StartSpan("kafka.produce")
+		require.NoError(t, tracer.Inject(span.Context(), NewMessageCarrier(msg)))
+		span.Finish()
+		producers = append(producers, span)
+		tr.AddToConsumeBatch(ctx, msg)
+	}
+	tr.AddToConsumeBatch(ctx, &testMessage{topic: "gotest"})
+	tr.FinishConsumeBatch(errors.New("commit failed"))
+	// no batch in progress
+	tr.FinishConsumeBatch(nil)
+	parent.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 5)
+	batch := spans[3]
+	assert.Equal(t, "kafka.consume.batch", batch.OperationName())
+	assert.Equal(t, "gotest", batch.Tag(ext.ResourceName))
+	assert.Equal(t, "gotest", batch.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, ext.SpanKindConsumer, batch.Tag(ext.SpanKind))
+	assert.Equal(t, "segmentio/kafka.go.v0", batch.Tag(ext.Component))
+	assert.Equal(t, "localhost:9092", batch.Tag(ext.KafkaBootstrapServers))
+	assert.Equal(t, float64(4), batch.Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, "commit failed", batch.Tag(ext.ErrorMsg))
+	assert.Equal(t, parent.Context().SpanID(), batch.ParentID())
+
+	// the number of links is capped
+	links := batch.Links()
+	require.Len(t, links, 2)
+	for i, link := range links {
+		assert.Equal(t, producers[i].Context().TraceIDLower(), link.TraceID)
+		assert.Equal(t, producers[i].Context().SpanID(), link.SpanID)
+	}
+
+	t.Run("next", func(t *testing.T) {
+		mt.Reset()
+		tr.AddToConsumeBatch(context.Background(), &testMessage{topic: "other"})
+		tr.FinishConsumeBatch(nil)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "other", spans[0].Tag(ext.ResourceName))
+		assert.Equal(t, float64(1), spans[0].Tag(ext.MessagingBatchMessageCount))
+		assert.Zero(t, spans[0].ParentID())
+		assert.Empty(t, spans[0].Links())
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		mt.Reset()
+		tr.AddToConsumeBatch(context.Background(), &testMessage{topic: "b"})
+		tr.AddToConsumeBatch(context.Background(), &testMessage{topic: "a"})
+		tr.FinishConsumeBatch(nil)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "a,b", spans[0].Tag(ext.ResourceName))
+		assert.Nil(t, spans[0].Tag(ext.MessagingDestinationName))
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		mt.Reset()
+		tr := NewTracer(KafkaConfig{}, WithBatchConsume(0), WithBatchConsumeLimits(2, time.Millisecond))
+		for range 3 {
+			tr.AddToConsumeBatch(context.Background(), &testMessage{topic: "gotest"})
+		}
+		require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, time.Second, time.Millisecond)
+		spans := mt.FinishedSpans()
+		assert.Equal(t, float64(2), spans[0].Tag(ext.MessagingBatchMessageCount))
+		assert.Equal(t, float64(1), spans[1].Tag(ext.MessagingBatchMessageCount))
+		// the batch finished by the timer is not finished again
+		tr.FinishConsumeBatch(nil)
+		assert.Len(t, mt.FinishedSpans(), 2)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.False(t, NewTracer(KafkaConfig{}).BatchConsumeEnabled())
+		tr := NewTracer(KafkaConfig{}, WithBatchConsume(0))
+		assert.Equal(t, DefaultBatchMaxLinks, tr.batchLimits.MaxLinks)
+	})
+}
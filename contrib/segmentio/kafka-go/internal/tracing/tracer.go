@@ -7,8 +7,10 @@ package tracing
 
 import (
 	"math"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/messaging"
 )
 
 var instr *instrumentation.Instrumentation
@@ -25,6 +27,8 @@ type Tracer struct {
 	analyticsRate       float64
 	dataStreamsEnabled  bool
 	kafkaCfg            KafkaConfig
+	batchLimits         messaging.BatchLimits
+	batch               *messaging.ConsumeBatch
 }
 
 // Option describes options for the Kafka integration.
@@ -41,10 +45,14 @@ func NewTracer(kafkaCfg KafkaConfig, opts ...Option) *Tracer {
 		analyticsRate:       instr.AnalyticsRate(false),
 		dataStreamsEnabled:  instr.DataStreamsEnabled(),
 		kafkaCfg:            kafkaCfg,
+		batchLimits:         messaging.BatchLimits{MaxDuration: DefaultBatchMaxDuration},
 	}
 	for _, opt := range opts {
 		opt.apply(tr)
 	}
+	if tr.batchLimits.MaxLinks > 0 {
+		tr.batch = tr.newConsumeBatch()
+	}
 	return tr
 }
 
@@ -93,6 +101,36 @@ func WithDataStreams() Option {
 	})
 }
 
+// WithBatchConsume enables the batch mode of the readers, for the messages
+// read one at a time but processed as a batch. Instead of a span per message,
+// the messages read until the next commit are traced by a single span, linked
+// to the spans which produced them. At most maxLinks links are added to the
+// span, DefaultBatchMaxLinks if maxLinks is not positive.
+//
+// As the offsets may be committed by the reader itself, the batch also ends
+// when a read fails, or when it reaches the limits set by
+// WithBatchConsumeLimits.
+func WithBatchConsume(maxLinks int) Option {
+	return OptionFn(func(tr *Tracer) {
+		if maxLinks <= 0 {
+			maxLinks = DefaultBatchMaxLinks
+		}
+		tr.batchLimits.MaxLinks = maxLinks
+	})
+}
+
+// WithBatchConsumeLimits sets the maximum number of messages and the maximum
+// duration of the batches traced with WithBatchConsume. A batch which reaches
+// either of them is finished, its next message starting a new batch. A limit
+// which is not positive disables it. By default, the number of messages is
+// not limited and the duration is limited to DefaultBatchMaxDuration.
+func WithBatchConsumeLimits(maxMessages int, maxDuration time.Duration) Option {
+	return OptionFn(func(tr *Tracer) {
+		tr.batchLimits.MaxMessages = maxMessages
+		tr.batchLimits.MaxDuration = maxDuration
+	})
+}
+
 func Logger() instrumentation.Logger {
 	return instr.Logger()
 }
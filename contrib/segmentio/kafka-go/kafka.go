@@ -53,6 +53,7 @@ func (r *Reader) Close() error {
 		r.prev.Finish()
 		r.prev = nil
 	}
+	r.tracer.FinishConsumeBatch(nil)
 	return err
 }
 
@@ -64,10 +65,15 @@ func (r *Reader) ReadMessage(ctx context.Context) (kafka.Message, error) {
 	}
 	msg, err := r.Reader.ReadMessage(ctx)
 	if err != nil {
+		r.tracer.FinishConsumeBatch(nil)
 		return kafka.Message{}, err
 	}
 	tMsg := wrapMessage(&msg)
-	r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	if r.tracer.BatchConsumeEnabled() {
+		r.tracer.AddToConsumeBatch(ctx, tMsg)
+	} else {
+		r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	}
 	r.tracer.SetConsumeDSMCheckpoint(tMsg)
 	return msg, nil
 }
@@ -80,14 +86,27 @@ func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
 	}
 	msg, err := r.Reader.FetchMessage(ctx)
 	if err != nil {
+		r.tracer.FinishConsumeBatch(nil)
 		return msg, err
 	}
 	tMsg := wrapMessage(&msg)
-	r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	if r.tracer.BatchConsumeEnabled() {
+		r.tracer.AddToConsumeBatch(ctx, tMsg)
+	} else {
+		r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	}
 	r.tracer.SetConsumeDSMCheckpoint(tMsg)
 	return msg, nil
 }
 
+// CommitMessages calls the underlying Reader.CommitMessages. It finishes the
+// span of the current batch if the batch mode is enabled.
+func (r *Reader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	err := r.Reader.CommitMessages(ctx, msgs...)
+	r.tracer.FinishConsumeBatch(err)
+	return err
+}
+
 // Writer wraps a kafka.Writer with tracing config data
 type KafkaWriter struct {
 	*kafka.Writer
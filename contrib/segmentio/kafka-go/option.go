@@ -5,7 +5,11 @@
 
 package kafka
 
-import "github.com/DataDog/dd-trace-go/contrib/segmentio/kafka-go/v2/internal/tracing"
+import (
+	"time"
+
+	"github.com/DataDog/dd-trace-go/contrib/segmentio/kafka-go/v2/internal/tracing"
+)
 
 // Option describes options for the Kafka integration.
 type Option = tracing.Option
@@ -33,3 +37,24 @@ func WithAnalyticsRate(rate float64) Option {
 func WithDataStreams() Option {
 	return tracing.WithDataStreams()
 }
+
+// WithBatchConsume enables the batch mode of the reader, for the messages read
+// one at a time but processed as a batch. Instead of a span per message, the
+// messages read until the next call to CommitMessages are traced by a single
+// span, linked to the spans which produced them. At most maxLinks links are
+// added to the span, 128 if maxLinks is not positive.
+//
+// As the reader may commit the offsets itself, the batch also ends when a read
+// fails, or when it reaches the limits set by WithBatchConsumeLimits.
+func WithBatchConsume(maxLinks int) Option {
+	return tracing.WithBatchConsume(maxLinks)
+}
+
+// WithBatchConsumeLimits sets the maximum number of messages and the maximum
+// duration of the batches traced with WithBatchConsume. A batch which reaches
+// either of them is finished, its next message starting a new batch. A limit
+// which is not positive disables it. By default, the number of messages is not
+// limited and the duration is limited to 10 seconds.
+func WithBatchConsumeLimits(maxMessages int, maxDuration time.Duration) Option {
+	return tracing.WithBatchConsumeLimits(maxMessages, maxDuration)
+}
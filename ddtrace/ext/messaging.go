@@ -10,12 +10,15 @@ const (
 	MessagingSystem = "messaging.system"
 	// MessagingDestinationName identifies message destination name
 	MessagingDestinationName = "messaging.destination.name"
+	// MessagingBatchMessageCount defines the number of messages of a batch
+	MessagingBatchMessageCount = "messaging.batch.message_count"
 )
 
 // Available values for messaging.system.
 const (
 	MessagingSystemGCPPubsub = "googlepubsub"
 	MessagingSystemKafka     = "kafka"
+	MessagingSystemRabbitMQ  = "rabbitmq"
 )
 
 // Kafka tags.
@@ -25,3 +28,23 @@ const (
 	// KafkaBootstrapServers holds a comma separated list of bootstrap servers as defined in producer or consumer config.
 	KafkaBootstrapServers = "messaging.kafka.bootstrap.servers"
 )
+
+// RabbitMQ tags following the naming schema v0.
+const (
+	// AMQPExchange defines the exchange a message is published to or was routed by.
+	AMQPExchange = "amqp.exchange"
+	// AMQPRoutingKey defines the routing key of a message.
+	AMQPRoutingKey = "amqp.routing_key"
+	// AMQPQueue defines the queue a message is consumed from.
+	AMQPQueue = "amqp.queue"
+)
+
+// RabbitMQ tags following the naming schema v1.
+const (
+	// MessagingRabbitMQExchange defines the exchange a message is published to or was routed by.
+	MessagingRabbitMQExchange = "messaging.rabbitmq.exchange"
+	// MessagingRabbitMQRoutingKey defines the routing key of a message.
+	MessagingRabbitMQRoutingKey = "messaging.rabbitmq.destination.routing_key"
+	// MessagingRabbitMQQueue defines the queue a message is consumed from.
+	MessagingRabbitMQQueue = "messaging.rabbitmq.queue"
+)
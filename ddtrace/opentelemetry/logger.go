@@ -0,0 +1,266 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/version"
+)
+
+var _ otellog.Logger = (*logger)(nil)
+
+// logger writes the log records of an instrumentation scope.
+type logger struct {
+	embedded.Logger
+
+	provider *LoggerProvider
+	scope    string
+	version  string
+}
+
+// Enabled reports whether the logger emits log records. It returns false once
+// the provider has been shut down.
+func (l *logger) Enabled(_ context.Context, _ otellog.EnabledParameters) bool {
+	return atomic.LoadUint32(&l.provider.stopped) == 0
+}
+
+// Emit writes the log record r, correlated with the span held by ctx, if any.
+func (l *logger) Emit(ctx context.Context, r otellog.Record) {
+	if atomic.LoadUint32(&l.provider.stopped) != 0 {
+		return
+	}
+	if r.ObservedTimestamp().IsZero() {
+		r.SetObservedTimestamp(time.Now())
+	}
+	sc := spanContextFromContext(ctx)
+	var (
+		line []byte
+		err  error
+	)
+	switch l.provider.cfg.format {
+	case LogFormatOTLP:
+		line, err = l.encodeOTLP(r, sc)
+	default:
+		line, err = l.encodeJSON(r, sc)
+	}
+	if err != nil {
+		log.Error("opentelemetry: unable to encode log record: %s", err.Error())
+		return
+	}
+	if err := l.provider.write(append(line, '\n')); err != nil {
+		log.Error("opentelemetry: unable to write log record: %s", err.Error())
+	}
+}
+
+// logSpanContext identifies the span with which a log record is correlated.
+type logSpanContext struct {
+	traceID [16]byte
+	spanID  uint64
+	sampled bool
+}
+
+func (sc logSpanContext) valid() bool {
+	return sc.traceID != [16]byte{} && sc.spanID != 0
+}
+
+// spanContextFromContext returns the context of the Datadog span held by ctx or,
+// if there is none, of the OpenTelemetry span held by ctx.
+func spanContextFromContext(ctx context.Context) logSpanContext {
+	if ctx == nil {
+		return logSpanContext{}
+	}
+	if s, ok := tracer.SpanFromContext(ctx); ok {
+		c := s.Context()
+		p, ok := c.SamplingPriority()
+		return logSpanContext{
+			traceID: c.TraceIDBytes(),
+			spanID:  c.SpanID(),
+			sampled: ok && p > 0,
+		}
+	}
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		sid := sc.SpanID()
+		return logSpanContext{
+			traceID: sc.TraceID(),
+			spanID:  binary.BigEndian.Uint64(sid[:]),
+			sampled: sc.IsSampled(),
+		}
+	}
+	return logSpanContext{}
+}
+
+// severityText returns the severity text of r, defaulting to the name of its severity.
+func severityText(r otellog.Record) string {
+	if t := r.SeverityText(); t != "" {
+		return t
+	}
+	if r.Severity() == otellog.SeverityUndefined {
+		return ""
+	}
+	return r.Severity().String()
+}
+
+// encodeJSON encodes r as a JSON object, using the attribute names recognized
+// by Datadog log pipelines.
+func (l *logger) encodeJSON(r otellog.Record, sc logSpanContext) ([]byte, error) {
+	m := make(map[string]any, r.AttributesLen()+10)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		m[kv.Key] = logValueToAny(kv.Value)
+		return true
+	})
+	ts := r.Timestamp()
+	if ts.IsZero() {
+		ts = r.ObservedTimestamp()
+	}
+	m["timestamp"] = ts.Format(time.RFC3339Nano)
+	if s := severityText(r); s != "" {
+		m["status"] = s
+	}
+	m["message"] = logValueToAny(r.Body())
+	if n := r.EventName(); n != "" {
+		m["event.name"] = n
+	}
+	if l.scope != "" {
+		m["logger.name"] = l.scope
+	}
+	if s := globalconfig.ServiceName(); s != "" {
+		m["dd.service"] = s
+	}
+	if e := globalconfig.Env(); e != "" {
+		m["dd.env"] = e
+	}
+	if v := globalconfig.ServiceVersion(); v != "" {
+		m["dd.version"] = v
+	}
+	if sc.valid() {
+		if l.provider.cfg.log128bits {
+			m[ext.LogKeyTraceID] = pcommon.TraceID(sc.traceID).String()
+		} else {
+			m[ext.LogKeyTraceID] = strconv.FormatUint(binary.BigEndian.Uint64(sc.traceID[8:]), 10)
+		}
+		m[ext.LogKeySpanID] = strconv.FormatUint(sc.spanID, 10)
+	}
+	return json.Marshal(m)
+}
+
+// encodeOTLP encodes r as an OTLP/JSON logs export request.
+func (l *logger) encodeOTLP(r otellog.Record, sc logSpanContext) ([]byte, error) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	attrs := rl.Resource().Attributes()
+	if s := globalconfig.ServiceName(); s != "" {
+		attrs.PutStr("service.name", s)
+	}
+	if e := globalconfig.Env(); e != "" {
+		attrs.PutStr("deployment.environment", e)
+	}
+	if v := globalconfig.ServiceVersion(); v != "" {
+		attrs.PutStr("service.version", v)
+	}
+	attrs.PutStr("telemetry.sdk.name", "datadog")
+	attrs.PutStr("telemetry.sdk.language", "go")
+	attrs.PutStr("telemetry.sdk.version", version.Tag)
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(l.scope)
+	sl.Scope().SetVersion(l.version)
+
+	lr := sl.LogRecords().AppendEmpty()
+	if ts := r.Timestamp(); !ts.IsZero() {
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	}
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(r.ObservedTimestamp()))
+	lr.SetSeverityNumber(plog.SeverityNumber(r.Severity()))
+	lr.SetSeverityText(r.SeverityText())
+	lr.SetEventName(r.EventName())
+	putLogValue(lr.Body(), r.Body())
+	lr.Attributes().EnsureCapacity(r.AttributesLen())
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		putLogValue(lr.Attributes().PutEmpty(kv.Key), kv.Value)
+		return true
+	})
+	if sc.valid() {
+		lr.SetTraceID(sc.traceID)
+		var sid pcommon.SpanID
+		binary.BigEndian.PutUint64(sid[:], sc.spanID)
+		lr.SetSpanID(sid)
+		lr.SetFlags(plog.DefaultLogRecordFlags.WithIsSampled(sc.sampled))
+	}
+	return (&plog.JSONMarshaler{}).MarshalLogs(ld)
+}
+
+// logValueToAny converts v to a value which can be encoded to JSON.
+func logValueToAny(v otellog.Value) any {
+	switch v.Kind() {
+	case otellog.KindBool:
+		return v.AsBool()
+	case otellog.KindFloat64:
+		return v.AsFloat64()
+	case otellog.KindInt64:
+		return v.AsInt64()
+	case otellog.KindString:
+		return v.AsString()
+	case otellog.KindBytes:
+		return v.AsBytes()
+	case otellog.KindSlice:
+		s := v.AsSlice()
+		out := make([]any, len(s))
+		for i, e := range s {
+			out[i] = logValueToAny(e)
+		}
+		return out
+	case otellog.KindMap:
+		kvs := v.AsMap()
+		out := make(map[string]any, len(kvs))
+		for _, kv := range kvs {
+			out[kv.Key] = logValueToAny(kv.Value)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// putLogValue stores v into dst.
+func putLogValue(dst pcommon.Value, v otellog.Value) {
+	switch v.Kind() {
+	case otellog.KindBool:
+		dst.SetBool(v.AsBool())
+	case otellog.KindFloat64:
+		dst.SetDouble(v.AsFloat64())
+	case otellog.KindInt64:
+		dst.SetInt(v.AsInt64())
+	case otellog.KindString:
+		dst.SetStr(v.AsString())
+	case otellog.KindBytes:
+		dst.SetEmptyBytes().FromRaw(v.AsBytes())
+	case otellog.KindSlice:
+		s := dst.SetEmptySlice()
+		for _, e := range v.AsSlice() {
+			putLogValue(s.AppendEmpty(), e)
+		}
+	case otellog.KindMap:
+		m := dst.SetEmptyMap()
+		for _, kv := range v.AsMap() {
+			putLogValue(m.PutEmpty(kv.Key), kv.Value)
+		}
+	}
+}
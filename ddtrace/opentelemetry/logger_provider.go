@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+)
+
+var _ otellog.LoggerProvider = (*LoggerProvider)(nil)
+
+// LogFormat is the format in which a LoggerProvider writes log records.
+type LogFormat int
+
+const (
+	// LogFormatJSON writes each log record as a line of JSON, with the
+	// dd.trace_id and dd.span_id attributes used by Datadog to correlate
+	// logs and traces.
+	LogFormatJSON LogFormat = iota
+
+	// LogFormatOTLP writes each log record as a line of OTLP/JSON, as read by
+	// the otlpjsonfile receiver of the OpenTelemetry Collector. The trace and
+	// span IDs are set on the OTLP log record.
+	LogFormatOTLP
+)
+
+// LoggerProvider provides an implementation of the OpenTelemetry LoggerProvider
+// interface, which bridges the OpenTelemetry Logs API to a writer. Log records
+// emitted with a context holding a Datadog span, or any OpenTelemetry span, are
+// correlated with it.
+//
+// The service, env and version of the records are those configured in the
+// tracer, which should be started before calling NewLoggerProvider.
+type LoggerProvider struct {
+	embedded.LoggerProvider
+
+	cfg     loggerProviderConfig
+	mu      sync.Mutex // guards writes to cfg.writer
+	stopped uint32     // stopped indicates whether the LoggerProvider has been shutdown.
+}
+
+// LoggerProviderOption configures a LoggerProvider.
+type LoggerProviderOption func(*loggerProviderConfig)
+
+type loggerProviderConfig struct {
+	writer     io.Writer
+	format     LogFormat
+	log128bits bool
+}
+
+// WithLogWriter sets the writer to which log records are written. It defaults to os.Stdout.
+func WithLogWriter(w io.Writer) LoggerProviderOption {
+	return func(c *loggerProviderConfig) {
+		c.writer = w
+	}
+}
+
+// WithLogFormat sets the format in which log records are written. It defaults to LogFormatJSON.
+func WithLogFormat(f LogFormat) LoggerProviderOption {
+	return func(c *loggerProviderConfig) {
+		c.format = f
+	}
+}
+
+// NewLoggerProvider returns an instance of an OpenTelemetry LoggerProvider
+// writing log records correlated with the active spans.
+func NewLoggerProvider(opts ...LoggerProviderOption) *LoggerProvider {
+	cfg := loggerProviderConfig{
+		writer:     os.Stdout,
+		format:     LogFormatJSON,
+		log128bits: internal.BoolEnv("DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED", true),
+	}
+	for _, fn := range opts {
+		fn(&cfg)
+	}
+	return &LoggerProvider{cfg: cfg}
+}
+
+// Logger returns a logger for the given instrumentation scope.
+// If the LoggerProvider has already been shut down, this will return a no-op logger.
+func (p *LoggerProvider) Logger(name string, opts ...otellog.LoggerOption) otellog.Logger {
+	if atomic.LoadUint32(&p.stopped) != 0 {
+		return lognoop.NewLoggerProvider().Logger("")
+	}
+	cfg := otellog.NewLoggerConfig(opts...)
+	return &logger{
+		provider: p,
+		scope:    name,
+		version:  cfg.InstrumentationVersion(),
+	}
+}
+
+// Shutdown stops the LoggerProvider. Records emitted afterwards are dropped.
+// Subsequent calls are valid but become no-op.
+func (p *LoggerProvider) Shutdown() error {
+	atomic.StoreUint32(&p.stopped, 1)
+	return nil
+}
+
+// write writes line to the configured writer.
+func (p *LoggerProvider) write(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.cfg.writer.Write(line)
+	return err
+}
@@ -0,0 +1,169 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	otellog "go.opentelemetry.io/otel/log"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+)
+
+func startLogTestTracer(t *testing.T) {
+	tracer.Start(
+		tracer.WithService("log-service"),
+		tracer.WithEnv("test-env"),
+		tracer.WithServiceVersion("1.2.3"),
+		tracer.WithLogStartup(false),
+	)
+	t.Cleanup(func() {
+		tracer.Stop()
+		globalconfig.SetServiceName("")
+		globalconfig.SetServiceVersion("")
+		globalconfig.SetEnv("")
+	})
+}
+
+func newTestRecord() otellog.Record {
+	var r otellog.Record
+	r.SetTimestamp(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	r.SetSeverity(otellog.SeverityWarn)
+	r.SetBody(otellog.StringValue("disk almost full"))
+	r.AddAttributes(
+		otellog.String("disk", "/dev/sda1"),
+		otellog.Int("usage", 95),
+		otellog.Map("details", otellog.Bool("alert", true)),
+	)
+	return r
+}
+
+func TestLoggerProviderJSON(t *testing.T) {
+	startLogTestTracer(t)
+	var buf bytes.Buffer
+	lp := NewLoggerProvider(WithLogWriter(&buf))
+	l := lp.Logger("app/storage")
+
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "op")
+	defer sp.Finish()
+	l.Emit(ctx, newTestRecord())
+	l.Emit(context.Background(), newTestRecord())
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &m))
+	assert.Equal(t, "disk almost full", m["message"])
+	assert.Equal(t, "WARN", m["status"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", m["timestamp"])
+	assert.Equal(t, "/dev/sda1", m["disk"])
+	assert.Equal(t, 95.0, m["usage"])
+	assert.Equal(t, map[string]any{"alert": true}, m["details"])
+	assert.Equal(t, "app/storage", m["logger.name"])
+	assert.Equal(t, "log-service", m["dd.service"])
+	assert.Equal(t, "test-env", m["dd.env"])
+	assert.Equal(t, "1.2.3", m["dd.version"])
+	assert.Equal(t, sp.Context().TraceID(), m[ext.LogKeyTraceID])
+	assert.Equal(t, strconv.FormatUint(sp.Context().SpanID(), 10), m[ext.LogKeySpanID])
+
+	m = nil
+	require.NoError(t, json.Unmarshal(lines[1], &m))
+	assert.NotContains(t, m, ext.LogKeyTraceID)
+	assert.NotContains(t, m, ext.LogKeySpanID)
+}
+
+func TestLoggerProvider64BitTraceID(t *testing.T) {
+	t.Setenv("DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED", "false")
+	startLogTestTracer(t)
+	var buf bytes.Buffer
+	l := NewLoggerProvider(WithLogWriter(&buf)).Logger("")
+
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "op")
+	defer sp.Finish()
+	l.Emit(ctx, newTestRecord())
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, strconv.FormatUint(sp.Context().TraceIDLower(), 10), m[ext.LogKeyTraceID])
+}
+
+func TestLoggerProviderOTLP(t *testing.T) {
+	startLogTestTracer(t)
+	var buf bytes.Buffer
+	lp := NewLoggerProvider(WithLogWriter(&buf), WithLogFormat(LogFormatOTLP))
+	l := lp.Logger("app/storage", otellog.WithInstrumentationVersion("0.1"))
+
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "op")
+	defer sp.Finish()
+	l.Emit(ctx, newTestRecord())
+
+	ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(bytes.TrimSpace(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	res := rl.Resource().Attributes().AsRaw()
+	assert.Equal(t, "log-service", res["service.name"])
+	assert.Equal(t, "test-env", res["deployment.environment"])
+	assert.Equal(t, "1.2.3", res["service.version"])
+	sl := rl.ScopeLogs().At(0)
+	assert.Equal(t, "app/storage", sl.Scope().Name())
+	assert.Equal(t, "0.1", sl.Scope().Version())
+
+	lr := sl.LogRecords().At(0)
+	assert.Equal(t, "disk almost full", lr.Body().Str())
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, map[string]any{
+		"disk":    "/dev/sda1",
+		"usage":   int64(95),
+		"details": map[string]any{"alert": true},
+	}, lr.Attributes().AsRaw())
+	assert.Equal(t, sp.Context().TraceIDBytes(), [16]byte(lr.TraceID()))
+	sid := lr.SpanID()
+	assert.Equal(t, sp.Context().SpanID(), binary.BigEndian.Uint64(sid[:]))
+	assert.True(t, lr.Flags().IsSampled())
+}
+
+func TestLoggerProviderOTelSpan(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLoggerProvider(WithLogWriter(&buf)).Logger("")
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     oteltrace.SpanID{0, 0, 0, 0, 0, 0, 0, 42},
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+	l.Emit(oteltrace.ContextWithSpanContext(context.Background(), sc), newTestRecord())
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", m[ext.LogKeyTraceID])
+	assert.Equal(t, "42", m[ext.LogKeySpanID])
+}
+
+func TestLoggerProviderShutdown(t *testing.T) {
+	var buf bytes.Buffer
+	lp := NewLoggerProvider(WithLogWriter(&buf))
+	l := lp.Logger("")
+	assert.True(t, l.Enabled(context.Background(), otellog.EnabledParameters{}))
+
+	assert.NoError(t, lp.Shutdown())
+	assert.NoError(t, lp.Shutdown())
+	assert.False(t, l.Enabled(context.Background(), otellog.EnabledParameters{}))
+	l.Emit(context.Background(), newTestRecord())
+	lp.Logger("").Emit(context.Background(), newTestRecord())
+	assert.Zero(t, buf.Len())
+}
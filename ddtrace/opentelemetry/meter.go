@@ -0,0 +1,344 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+var _ metric.Meter = (*meter)(nil)
+
+// meter creates instruments which send their measurements to DogStatsD.
+type meter struct {
+	embedded.Meter
+
+	statsd internal.StatsdClient
+	tags   []string // tags added to all metrics
+
+	mu        sync.Mutex      // guards callbacks
+	callbacks []*registration // callbacks invoked to collect observable instruments
+}
+
+// tagsFor returns the tags of a measurement with the given attributes.
+func (m *meter) tagsFor(attrs attribute.Set) []string {
+	tags := make([]string, len(m.tags), len(m.tags)+attrs.Len())
+	copy(tags, m.tags)
+	for iter := attrs.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		tags = append(tags, string(kv.Key)+":"+kv.Value.Emit())
+	}
+	return tags
+}
+
+// register adds f to the callbacks invoked on each collection.
+func (m *meter) register(f func(context.Context) error) *registration {
+	r := &registration{meter: m, f: f}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, r)
+	return r
+}
+
+// collect invokes all the registered callbacks.
+func (m *meter) collect(ctx context.Context) {
+	m.mu.Lock()
+	callbacks := slices.Clone(m.callbacks)
+	m.mu.Unlock()
+	for _, r := range callbacks {
+		if err := r.f(ctx); err != nil {
+			log.Warn("opentelemetry: metric callback failed: %s", err.Error())
+		}
+	}
+}
+
+func (m *meter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &counter[int64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Int64UpDownCounter(name string, _ ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return &counter[int64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Int64Histogram(name string, _ ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return &histogram[int64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Int64Gauge(name string, _ ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	return &gauge[int64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	cfg := metric.NewInt64ObservableCounterConfig(options...)
+	return m.int64Observable(name, false, cfg.Callbacks()), nil
+}
+
+func (m *meter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	cfg := metric.NewInt64ObservableUpDownCounterConfig(options...)
+	// Non-monotonic cumulative values are reported as gauges, as the
+	// OpenTelemetry exporters do.
+	return m.int64Observable(name, true, cfg.Callbacks()), nil
+}
+
+func (m *meter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	cfg := metric.NewInt64ObservableGaugeConfig(options...)
+	return m.int64Observable(name, true, cfg.Callbacks()), nil
+}
+
+func (m *meter) Float64Counter(name string, _ ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	return &counter[float64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Float64UpDownCounter(name string, _ ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	return &counter[float64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &histogram[float64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Float64Gauge(name string, _ ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	return &gauge[float64]{name: name, meter: m}, nil
+}
+
+func (m *meter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	cfg := metric.NewFloat64ObservableCounterConfig(options...)
+	return m.float64Observable(name, false, cfg.Callbacks()), nil
+}
+
+func (m *meter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	cfg := metric.NewFloat64ObservableUpDownCounterConfig(options...)
+	// Non-monotonic cumulative values are reported as gauges, as the
+	// OpenTelemetry exporters do.
+	return m.float64Observable(name, true, cfg.Callbacks()), nil
+}
+
+func (m *meter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	cfg := metric.NewFloat64ObservableGaugeConfig(options...)
+	return m.float64Observable(name, true, cfg.Callbacks()), nil
+}
+
+// RegisterCallback registers f to be called on each collection. Observations made
+// by f on instruments which were not created by this meter are ignored.
+func (m *meter) RegisterCallback(f metric.Callback, _ ...metric.Observable) (metric.Registration, error) {
+	return m.register(func(ctx context.Context) error {
+		return f(ctx, observer{})
+	}), nil
+}
+
+func (m *meter) int64Observable(name string, isGauge bool, callbacks []metric.Int64Callback) *int64Observable {
+	o := &int64Observable{asyncInstrument: newAsyncInstrument(m, name, isGauge)}
+	for _, cb := range callbacks {
+		m.register(func(ctx context.Context) error {
+			return cb(ctx, int64Observer{inst: o.asyncInstrument})
+		})
+	}
+	return o
+}
+
+func (m *meter) float64Observable(name string, isGauge bool, callbacks []metric.Float64Callback) *float64Observable {
+	o := &float64Observable{asyncInstrument: newAsyncInstrument(m, name, isGauge)}
+	for _, cb := range callbacks {
+		m.register(func(ctx context.Context) error {
+			return cb(ctx, float64Observer{inst: o.asyncInstrument})
+		})
+	}
+	return o
+}
+
+// registration is a callback registered on a meter.
+type registration struct {
+	embedded.Registration
+
+	meter *meter
+	f     func(context.Context) error
+}
+
+func (r *registration) Unregister() error {
+	m := r.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = slices.DeleteFunc(m.callbacks, func(cb *registration) bool { return cb == r })
+	return nil
+}
+
+// remainders carries the fractional part of float counts over to the next
+// measurement of the same series, as DogStatsD counts are integers.
+type remainders struct {
+	mu sync.Mutex
+	m  map[attribute.Distinct]float64
+}
+
+// add adds v to the series key and returns the integer part to be sent.
+func (r *remainders) add(key attribute.Distinct, v float64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.m == nil {
+		r.m = make(map[attribute.Distinct]float64)
+	}
+	v += r.m[key]
+	n := int64(v)
+	r.m[key] = v - float64(n)
+	return n
+}
+
+// counter implements the synchronous counters and up-down counters, which are
+// sent as DogStatsD counts.
+type counter[N int64 | float64] struct {
+	embedded.Int64Counter
+	embedded.Int64UpDownCounter
+	embedded.Float64Counter
+	embedded.Float64UpDownCounter
+
+	name  string
+	meter *meter
+	rem   remainders
+}
+
+func (c *counter[N]) Add(_ context.Context, incr N, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	var n int64
+	switch v := any(incr).(type) {
+	case int64:
+		n = v
+	case float64:
+		n = c.rem.add(attrs.Equivalent(), v)
+	}
+	if n == 0 {
+		return
+	}
+	c.meter.statsd.Count(c.name, n, c.meter.tagsFor(attrs), 1)
+}
+
+// histogram implements the synchronous histograms, which are sent as DogStatsD
+// distributions.
+type histogram[N int64 | float64] struct {
+	embedded.Int64Histogram
+	embedded.Float64Histogram
+
+	name  string
+	meter *meter
+}
+
+func (h *histogram[N]) Record(_ context.Context, value N, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	h.meter.statsd.DistributionSamples(h.name, []float64{float64(value)}, h.meter.tagsFor(attrs), 1)
+}
+
+// gauge implements the synchronous gauges, which are sent as DogStatsD gauges.
+type gauge[N int64 | float64] struct {
+	embedded.Int64Gauge
+	embedded.Float64Gauge
+
+	name  string
+	meter *meter
+}
+
+func (g *gauge[N]) Record(_ context.Context, value N, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	g.meter.statsd.Gauge(g.name, float64(value), g.meter.tagsFor(attrs), 1)
+}
+
+// asyncInstrument records the observations of an observable instrument.
+type asyncInstrument struct {
+	name    string
+	isGauge bool
+	meter   *meter
+
+	mu   sync.Mutex                     // guards last
+	last map[attribute.Distinct]float64 // last observed value of each series of a counter
+	rem  remainders
+}
+
+func newAsyncInstrument(m *meter, name string, isGauge bool) *asyncInstrument {
+	return &asyncInstrument{
+		name:    name,
+		isGauge: isGauge,
+		meter:   m,
+		last:    make(map[attribute.Distinct]float64),
+	}
+}
+
+// observe records value. Observable counters report cumulative values, so the
+// difference with the previous observation of the series is sent as a count.
+// Observable gauges and up-down counters are sent as gauges.
+func (a *asyncInstrument) observe(value float64, attrs attribute.Set) {
+	if a.isGauge {
+		a.meter.statsd.Gauge(a.name, value, a.meter.tagsFor(attrs), 1)
+		return
+	}
+	key := attrs.Equivalent()
+	a.mu.Lock()
+	prev := a.last[key]
+	a.last[key] = value
+	a.mu.Unlock()
+	if n := a.rem.add(key, value-prev); n != 0 {
+		a.meter.statsd.Count(a.name, n, a.meter.tagsFor(attrs), 1)
+	}
+}
+
+// int64Observable implements the int64 observable instruments.
+type int64Observable struct {
+	metric.Int64Observable
+	embedded.Int64ObservableCounter
+	embedded.Int64ObservableUpDownCounter
+	embedded.Int64ObservableGauge
+
+	*asyncInstrument
+}
+
+// float64Observable implements the float64 observable instruments.
+type float64Observable struct {
+	metric.Float64Observable
+	embedded.Float64ObservableCounter
+	embedded.Float64ObservableUpDownCounter
+	embedded.Float64ObservableGauge
+
+	*asyncInstrument
+}
+
+type int64Observer struct {
+	embedded.Int64Observer
+	inst *asyncInstrument
+}
+
+func (o int64Observer) Observe(value int64, options ...metric.ObserveOption) {
+	o.inst.observe(float64(value), metric.NewObserveConfig(options).Attributes())
+}
+
+type float64Observer struct {
+	embedded.Float64Observer
+	inst *asyncInstrument
+}
+
+func (o float64Observer) Observe(value float64, options ...metric.ObserveOption) {
+	o.inst.observe(value, metric.NewObserveConfig(options).Attributes())
+}
+
+// observer is passed to the callbacks registered with RegisterCallback.
+type observer struct {
+	embedded.Observer
+}
+
+func (observer) ObserveFloat64(obsrv metric.Float64Observable, value float64, options ...metric.ObserveOption) {
+	if o, ok := obsrv.(*float64Observable); ok {
+		o.observe(value, metric.NewObserveConfig(options).Attributes())
+	}
+}
+
+func (observer) ObserveInt64(obsrv metric.Int64Observable, value int64, options ...metric.ObserveOption) {
+	if o, ok := obsrv.(*int64Observable); ok {
+		o.observe(float64(value), metric.NewObserveConfig(options).Attributes())
+	}
+}
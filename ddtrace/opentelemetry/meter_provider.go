@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+var _ metric.MeterProvider = (*MeterProvider)(nil)
+
+// defaultCollectionPeriod is the default interval at which observable
+// instruments are collected.
+const defaultCollectionPeriod = 10 * time.Second
+
+// MeterProvider provides an implementation of the OpenTelemetry MeterProvider
+// interface which sends metrics to DogStatsD. Measurements are mapped onto
+// DogStatsD metrics as follows:
+//
+//   - counters and up-down counters are sent as counts,
+//   - histograms are sent as distributions,
+//   - gauges are sent as gauges,
+//   - observable counters report the difference with the previous observation
+//     as a count, and observable up-down counters and gauges are sent as gauges.
+//
+// Measurement attributes are sent as tags, in addition to the global tags, env,
+// service and version configured in the tracer. The tracer must therefore be
+// started before calling NewMeterProvider.
+//
+// The instrumentation scope, description and unit of instruments are ignored.
+type MeterProvider struct {
+	embedded.MeterProvider
+
+	meter   *meter
+	stopped uint32 // stopped indicates whether the MeterProvider has been shutdown.
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	sync.Once
+}
+
+// MeterProviderOption configures a MeterProvider.
+type MeterProviderOption func(*meterProviderConfig)
+
+type meterProviderConfig struct {
+	period time.Duration
+	statsd internal.StatsdClient
+}
+
+// WithCollectionPeriod sets the interval at which the callbacks of observable
+// instruments are invoked. It defaults to 10 seconds.
+func WithCollectionPeriod(period time.Duration) MeterProviderOption {
+	return func(c *meterProviderConfig) {
+		if period > 0 {
+			c.period = period
+		}
+	}
+}
+
+// withStatsdClient sets the statsd client used by the MeterProvider; used in tests.
+func withStatsdClient(s internal.StatsdClient) MeterProviderOption {
+	return func(c *meterProviderConfig) {
+		c.statsd = s
+	}
+}
+
+// NewMeterProvider returns an instance of an OpenTelemetry MeterProvider sending
+// metrics to the DogStatsD address configured in the tracer.
+// This MeterProvider only supports a singleton meter, and repeated calls to
+// the Meter() method will return the same instance each time.
+func NewMeterProvider(opts ...MeterProviderOption) (*MeterProvider, error) {
+	cfg := meterProviderConfig{period: defaultCollectionPeriod}
+	for _, fn := range opts {
+		fn(&cfg)
+	}
+	if cfg.statsd == nil {
+		client, err := internal.NewStatsdClient(globalconfig.DogstatsdAddr(), nil)
+		if err != nil {
+			return nil, err
+		}
+		cfg.statsd = client
+	}
+	tags := globalconfig.StatsTags()
+	if s := globalconfig.ServiceName(); s != "" {
+		tags = append(tags, "service:"+s)
+	}
+	if v := globalconfig.ServiceVersion(); v != "" {
+		tags = append(tags, "version:"+v)
+	}
+	p := &MeterProvider{
+		meter: &meter{
+			statsd: cfg.statsd,
+			tags:   tags,
+		},
+		stop: make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		tick := time.NewTicker(cfg.period)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				p.meter.collect(context.Background())
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p, nil
+}
+
+// Meter returns the singleton meter created when NewMeterProvider was called, ignoring
+// the provided name and any provided options to this method.
+// If the MeterProvider has already been shut down, this will return a no-op meter.
+func (p *MeterProvider) Meter(_ string, _ ...metric.MeterOption) metric.Meter {
+	if atomic.LoadUint32(&p.stopped) != 0 {
+		return metricnoop.NewMeterProvider().Meter("")
+	}
+	return p.meter
+}
+
+// Shutdown collects the observable instruments a last time, flushes the metrics
+// and closes the connection to DogStatsD. Subsequent calls are valid but become no-op.
+func (p *MeterProvider) Shutdown() error {
+	var err error
+	p.Once.Do(func() {
+		atomic.StoreUint32(&p.stopped, 1)
+		close(p.stop)
+		p.wg.Wait()
+		p.meter.collect(context.Background())
+		err = p.meter.statsd.Close()
+	})
+	return err
+}
+
+// ForceFlush collects the observable instruments and flushes the buffered metrics.
+func (p *MeterProvider) ForceFlush(timeout time.Duration, callback func(ok bool)) {
+	if atomic.LoadUint32(&p.stopped) != 0 {
+		log.Warn("Cannot perform (*MeterProvider).Flush since the meter provider is already stopped.")
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		p.meter.collect(context.Background())
+		p.meter.statsd.Flush()
+		close(done)
+	}()
+	select {
+	case <-time.After(timeout):
+		callback(false)
+	case <-done:
+		callback(true)
+	}
+}
@@ -0,0 +1,218 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opentelemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+// newTestMeterProvider starts the tracer and returns a MeterProvider sending its
+// metrics to a test statsd client.
+func newTestMeterProvider(t *testing.T, opts ...MeterProviderOption) (*MeterProvider, *statsdtest.TestStatsdClient) {
+	tracer.Start(
+		tracer.WithService("meter-service"),
+		tracer.WithEnv("test-env"),
+		tracer.WithServiceVersion("1.2.3"),
+		tracer.WithGlobalTag("team", "apm"),
+		tracer.WithLogStartup(false),
+	)
+	var tg statsdtest.TestStatsdClient
+	mp, err := NewMeterProvider(append(opts, withStatsdClient(&tg))...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		mp.Shutdown()
+		tracer.Stop()
+		globalconfig.SetServiceName("")
+		globalconfig.SetServiceVersion("")
+	})
+	return mp, &tg
+}
+
+// forceFlush flushes mp and fails the test if it times out.
+func forceFlush(t *testing.T, mp *MeterProvider) {
+	var flushed bool
+	mp.ForceFlush(time.Second, func(ok bool) { flushed = ok })
+	require.True(t, flushed)
+}
+
+func TestMeterProviderTags(t *testing.T) {
+	mp, tg := newTestMeterProvider(t)
+	c, err := mp.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	c.Add(context.Background(), 1, metric.WithAttributes(attribute.String("route", "/home"), attribute.Int("code", 200)))
+
+	calls := tg.CountCalls()
+	require.Len(t, calls, 1)
+	tags := calls[0].Tags()
+	assert.Contains(t, tags, "env:test-env")
+	assert.Contains(t, tags, "service:meter-service")
+	assert.Contains(t, tags, "version:1.2.3")
+	assert.Contains(t, tags, "team:apm")
+	assert.Contains(t, tags, "route:/home")
+	assert.Contains(t, tags, "code:200")
+}
+
+func TestMeterProviderSyncInstruments(t *testing.T) {
+	ctx := context.Background()
+	mp, tg := newTestMeterProvider(t)
+	m := mp.Meter("test")
+
+	t.Run("counter", func(t *testing.T) {
+		tg.Reset()
+		ic, err := m.Int64Counter("int.counter")
+		require.NoError(t, err)
+		ic.Add(ctx, 2)
+		ic.Add(ctx, 3)
+		fc, err := m.Float64Counter("float.counter")
+		require.NoError(t, err)
+		for range 4 {
+			fc.Add(ctx, 0.5)
+		}
+		counts := tg.Counts()
+		assert.Equal(t, int64(5), counts["int.counter"])
+		// fractional parts are carried over to the next measurements
+		assert.Equal(t, int64(2), counts["float.counter"])
+		assert.Len(t, tg.GetCallsByName("float.counter"), 2)
+	})
+
+	t.Run("up-down-counter", func(t *testing.T) {
+		tg.Reset()
+		ic, err := m.Int64UpDownCounter("int.updown")
+		require.NoError(t, err)
+		ic.Add(ctx, 5)
+		ic.Add(ctx, -2)
+		fc, err := m.Float64UpDownCounter("float.updown")
+		require.NoError(t, err)
+		fc.Add(ctx, 1.5)
+		fc.Add(ctx, -3.5)
+		counts := tg.Counts()
+		assert.Equal(t, int64(3), counts["int.updown"])
+		assert.Equal(t, int64(-2), counts["float.updown"])
+	})
+
+	t.Run("histogram", func(t *testing.T) {
+		tg.Reset()
+		ih, err := m.Int64Histogram("int.histogram")
+		require.NoError(t, err)
+		ih.Record(ctx, 7)
+		fh, err := m.Float64Histogram("float.histogram")
+		require.NoError(t, err)
+		fh.Record(ctx, 0.25)
+		fh.Record(ctx, 0.75)
+		calls := tg.DistributionCalls()
+		require.Len(t, calls, 3)
+		assert.Equal(t, "int.histogram", calls[0].Name())
+		assert.Equal(t, 7.0, calls[0].FloatVal())
+		assert.Equal(t, 0.25, calls[1].FloatVal())
+		assert.Equal(t, 0.75, calls[2].FloatVal())
+	})
+
+	t.Run("gauge", func(t *testing.T) {
+		tg.Reset()
+		ig, err := m.Int64Gauge("int.gauge")
+		require.NoError(t, err)
+		ig.Record(ctx, 42)
+		fg, err := m.Float64Gauge("float.gauge")
+		require.NoError(t, err)
+		fg.Record(ctx, 0.5)
+		calls := tg.GaugeCalls()
+		require.Len(t, calls, 2)
+		assert.Equal(t, "int.gauge", calls[0].Name())
+		assert.Equal(t, 42.0, calls[0].FloatVal())
+		assert.Equal(t, "float.gauge", calls[1].Name())
+		assert.Equal(t, 0.5, calls[1].FloatVal())
+	})
+}
+
+func TestMeterProviderObservableInstruments(t *testing.T) {
+	mp, tg := newTestMeterProvider(t, WithCollectionPeriod(time.Hour))
+	m := mp.Meter("test")
+
+	var total int64
+	_, err := m.Int64ObservableCounter("observed.counter", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(total, metric.WithAttributes(attribute.String("kind", "a")))
+		return nil
+	}))
+	require.NoError(t, err)
+	var level float64
+	_, err = m.Float64ObservableGauge("observed.gauge", metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+		o.Observe(level)
+		return nil
+	}))
+	require.NoError(t, err)
+	updown, err := m.Int64ObservableUpDownCounter("observed.updown")
+	require.NoError(t, err)
+	var queued int64
+	reg, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(updown, queued)
+		return nil
+	}, updown)
+	require.NoError(t, err)
+
+	total, level, queued = 10, 0.5, 4
+	forceFlush(t, mp)
+	counts := tg.Counts()
+	assert.Equal(t, int64(10), counts["observed.counter"])
+	assert.NotContains(t, counts, "observed.updown")
+	require.Len(t, tg.GaugeCalls(), 2)
+	assert.Equal(t, 0.5, tg.GetCallsByName("observed.gauge")[0].FloatVal())
+	// observable up-down counters report their cumulative value as a gauge
+	assert.Equal(t, float64(4), tg.GetCallsByName("observed.updown")[0].FloatVal())
+
+	// observable counters report cumulative values, only the difference is sent
+	tg.Reset()
+	total, level, queued = 15, 0.25, 1
+	forceFlush(t, mp)
+	counts = tg.Counts()
+	assert.Equal(t, int64(5), counts["observed.counter"])
+	assert.Equal(t, float64(1), tg.GetCallsByName("observed.updown")[0].FloatVal())
+	assert.Equal(t, 0.25, tg.GetCallsByName("observed.gauge")[0].FloatVal())
+
+	tg.Reset()
+	require.NoError(t, reg.Unregister())
+	queued = 10
+	forceFlush(t, mp)
+	assert.Empty(t, tg.GetCallsByName("observed.updown"))
+}
+
+func TestMeterProviderCollectionPeriod(t *testing.T) {
+	mp, tg := newTestMeterProvider(t, WithCollectionPeriod(10*time.Millisecond))
+	_, err := mp.Meter("test").Int64ObservableGauge("periodic", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(1)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(tg.GaugeCalls()) >= 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestMeterProviderShutdown(t *testing.T) {
+	mp, tg := newTestMeterProvider(t)
+	m := mp.Meter("test")
+	assert.Same(t, m, mp.Meter("other"))
+	_, err := m.Int64ObservableGauge("last", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(1)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.NoError(t, mp.Shutdown())
+	assert.NoError(t, mp.Shutdown())
+	assert.True(t, tg.Closed())
+	assert.Len(t, tg.GaugeCalls(), 1)
+	assert.IsType(t, metricnoop.Meter{}, mp.Meter("test"))
+}
@@ -26,6 +26,24 @@
 // the OpenTelemetry Tracing API (https://opentelemetry.io/docs/reference/specification/trace/api)
 // to allow users to send traces to Datadog using existing OpenTelemetry code with minimal changes to the application.
 // Span events (https://opentelemetry.io/docs/concepts/signals/traces/#span-events) are not supported at this time.
+//
+// The package also provides a MeterProvider, which sends the measurements of the
+// OpenTelemetry Metrics API to DogStatsD, using the configuration of the started tracer:
+//
+//	tracer.Start()
+//	defer tracer.Stop()
+//	mp, err := opentelemetry.NewMeterProvider()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer mp.Shutdown()
+//	otel.SetMeterProvider(mp)
+//
+// Finally, the LoggerProvider bridges the OpenTelemetry Logs API, writing log records
+// correlated with the active Datadog span as JSON lines or OTLP/JSON:
+//
+//	lp := opentelemetry.NewLoggerProvider(opentelemetry.WithLogWriter(os.Stderr))
+//	global.SetLoggerProvider(lp)
 package opentelemetry
 
 import (
@@ -118,6 +118,9 @@ type abandonedSpansDebugger struct {
 	// In takes candidate spans and adds them to the debugger.
 	In chan *abandonedSpanCandidate
 
+	// snapshots takes requests for the list of tracked spans.
+	snapshots chan chan []abandonedSpanCandidate
+
 	// waits for any active goroutines
 	wg sync.WaitGroup
 
@@ -135,8 +138,9 @@ type abandonedSpansDebugger struct {
 // newAbandonedSpansDebugger creates a new abandonedSpansDebugger debugger
 func newAbandonedSpansDebugger() *abandonedSpansDebugger {
 	d := &abandonedSpansDebugger{
-		buckets: make(map[int64]*bucket[uint64, *abandonedSpanCandidate]),
-		In:      make(chan *abandonedSpanCandidate, 10000),
+		buckets:   make(map[int64]*bucket[uint64, *abandonedSpanCandidate]),
+		In:        make(chan *abandonedSpanCandidate, 10000),
+		snapshots: make(chan chan []abandonedSpanCandidate),
 	}
 	atomic.SwapUint32(&d.stopped, 1)
 	return d
@@ -173,12 +177,48 @@ func (d *abandonedSpansDebugger) runConsumer(tick *time.Ticker, interval *time.D
 			} else {
 				d.add(s, *interval)
 			}
+		case c := <-d.snapshots:
+			c <- d.openSpans()
 		case <-d.stop:
 			return
 		}
 	}
 }
 
+// snapshot returns the spans which are not finished yet, oldest first, up to
+// max spans. It returns nil if the debugger is stopped.
+func (d *abandonedSpansDebugger) snapshot(max int) []abandonedSpanCandidate {
+	if d == nil || atomic.LoadUint32(&d.stopped) > 0 {
+		return nil
+	}
+	c := make(chan []abandonedSpanCandidate, 1)
+	select {
+	case d.snapshots <- c:
+	case <-d.stop:
+		return nil
+	}
+	spans := <-c
+	if len(spans) > max {
+		spans = spans[:max]
+	}
+	return spans
+}
+
+// openSpans returns copies of all the tracked spans, sorted by start time. It
+// must be called from the consumer goroutine.
+func (d *abandonedSpansDebugger) openSpans() []abandonedSpanCandidate {
+	var spans []abandonedSpanCandidate
+	for _, b := range d.buckets {
+		for e := b.data.Front(); e != nil; e = e.Next() {
+			spans = append(spans, *e.Value.(*abandonedSpanCandidate))
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+	return spans
+}
+
 func (d *abandonedSpansDebugger) Stop() {
 	if d == nil {
 		return
@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+)
+
+const (
+	// envBaggageMaxItems is the maximum number of items of the baggage header.
+	envBaggageMaxItems = "DD_TRACE_BAGGAGE_MAX_ITEMS"
+	// envBaggageMaxBytes is the maximum size in bytes of the baggage header.
+	envBaggageMaxBytes = "DD_TRACE_BAGGAGE_MAX_BYTES"
+	// envBaggageTagKeys lists the keys of the baggage items promoted to span
+	// tags, "*" promoting all of them.
+	envBaggageTagKeys = "DD_TRACE_BAGGAGE_TAG_KEYS"
+	// envBaggageTagKeysDenylist lists the keys of the baggage items never
+	// promoted to span tags.
+	envBaggageTagKeysDenylist = "DD_TRACE_BAGGAGE_TAG_KEYS_DENYLIST"
+)
+
+// baggageTagPrefix prefixes the tags of the baggage items promoted to span tags.
+const baggageTagPrefix = "baggage."
+
+// defaultBaggageTagKeys are the keys of the baggage items promoted to span
+// tags if envBaggageTagKeys is not set.
+var defaultBaggageTagKeys = []string{"user.id", "account.id", "session.id"}
+
+// sensitiveBaggageKeyRegexp matches the keys of the baggage items likely to
+// hold secrets, which are not promoted to span tags unless listed explicitly
+// in envBaggageTagKeys. The patterns match whole segments of the keys, split
+// on dots, dashes, underscores and camel case, so that "auth.token" matches
+// but "author" doesn't.
+var sensitiveBaggageKeyRegexp = regexp.MustCompile(`(?i)(?:^|[._-])(?:pass(?:[_.-]?phrase|w(?:or)?d)?s?|secrets?|tokens?|(?:api|private|access|secret)[_.-]?keys?|auth|cookies?|credentials?|bearer|signatures?)(?:$|[._-])`)
+
+// camelCaseBoundaryRegexp matches the boundaries between the words of camel
+// case keys, like "apiKey".
+var camelCaseBoundaryRegexp = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// sensitiveBaggageKey reports whether key is likely to hold a secret.
+func sensitiveBaggageKey(key string) bool {
+	return sensitiveBaggageKeyRegexp.MatchString(camelCaseBoundaryRegexp.ReplaceAllString(key, "${1}_${2}"))
+}
+
+// baggageTagger selects the baggage items of the extracted span contexts
+// promoted to tags of the spans started from them.
+type baggageTagger struct {
+	all  bool                // promote all the items, except the denied and sensitive ones.
+	keys map[string]struct{} // promote the items with these keys, even if they're sensitive.
+	deny map[string]struct{} // never promote the items with these keys.
+}
+
+// newBaggageTagger returns a baggageTagger configured from the environment.
+func newBaggageTagger() *baggageTagger {
+	t := &baggageTagger{
+		keys: make(map[string]struct{}),
+		deny: make(map[string]struct{}),
+	}
+	keys := defaultBaggageTagKeys
+	if v, ok := os.LookupEnv(envBaggageTagKeys); ok {
+		keys = strings.Split(v, ",")
+	}
+	for _, k := range keys {
+		switch k = strings.TrimSpace(k); k {
+		case "":
+		case "*":
+			t.all = true
+		default:
+			t.keys[k] = struct{}{}
+		}
+	}
+	for _, k := range strings.Split(os.Getenv(envBaggageTagKeysDenylist), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			t.deny[k] = struct{}{}
+		}
+	}
+	return t
+}
+
+// promoted reports whether the baggage item with the given key is promoted to
+// a span tag. The keys denied by envBaggageTagKeysDenylist are never promoted,
+// while the keys listed explicitly in envBaggageTagKeys are promoted even if
+// they're likely to hold secrets.
+func (t *baggageTagger) promoted(key string) bool {
+	if _, ok := t.deny[key]; ok {
+		return false
+	}
+	if _, ok := t.keys[key]; ok {
+		return true
+	}
+	return t.all && !sensitiveBaggageKey(key)
+}
+
+// tags returns the span tags of the promoted items of baggage, nil if there
+// are none.
+func (t *baggageTagger) tags(baggage map[string]string) map[string]string {
+	var tags map[string]string
+	for k, v := range baggage {
+		if !t.promoted(k) {
+			continue
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[baggageTagPrefix+k] = v
+	}
+	return tags
+}
+
+// baggageLimits returns the maximum number of items and size in bytes of the
+// baggage header, read from the environment.
+func baggageLimits() (maxItems, maxBytes int) {
+	maxItems = internal.IntEnv(envBaggageMaxItems, baggageMaxItems)
+	if maxItems <= 0 {
+		maxItems = baggageMaxItems
+	}
+	maxBytes = internal.IntEnv(envBaggageMaxBytes, baggageMaxBytes)
+	if maxBytes <= 0 {
+		maxBytes = baggageMaxBytes
+	}
+	return maxItems, maxBytes
+}
+
+// reportBaggageTruncated counts a baggage header truncated because of the
+// given reason, either "baggage_item_count_exceeded" or
+// "baggage_byte_count_exceeded".
+func reportBaggageTruncated(reason string) {
+	telemetry.Count(telemetry.NamespaceTracers, "context_header.truncated", []string{"header_style:baggage", "truncation_reason:" + reason}).Submit(1)
+}
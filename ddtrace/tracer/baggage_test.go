@@ -0,0 +1,164 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/telemetrytest"
+)
+
+func TestBaggageTagger(t *testing.T) {
+	baggage := map[string]string{
+		"user.id":    "1234",
+		"account.id": "456",
+		"color":      "blue",
+		"api_key":    "s3cr3t",
+		"auth.token": "s3cr3t",
+	}
+
+	t.Run("default", func(t *testing.T) {
+		tags := newBaggageTagger().tags(baggage)
+		assert.Equal(t, map[string]string{
+			"baggage.user.id":    "1234",
+			"baggage.account.id": "456",
+		}, tags)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Setenv(envBaggageTagKeys, "*")
+		tags := newBaggageTagger().tags(baggage)
+		assert.Equal(t, map[string]string{
+			"baggage.user.id":    "1234",
+			"baggage.account.id": "456",
+			"baggage.color":      "blue",
+		}, tags)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		t.Setenv(envBaggageTagKeys, " color ,api_key")
+		tags := newBaggageTagger().tags(baggage)
+		assert.Equal(t, map[string]string{
+			"baggage.color":   "blue",
+			"baggage.api_key": "s3cr3t",
+		}, tags, "the keys listed explicitly are promoted even if they're sensitive")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(envBaggageTagKeys, "")
+		assert.Nil(t, newBaggageTagger().tags(baggage))
+	})
+
+	t.Run("denylist", func(t *testing.T) {
+		t.Setenv(envBaggageTagKeys, "*")
+		t.Setenv(envBaggageTagKeysDenylist, "user.id, color")
+		tags := newBaggageTagger().tags(baggage)
+		assert.Equal(t, map[string]string{"baggage.account.id": "456"}, tags)
+
+		t.Setenv(envBaggageTagKeys, "user.id,api_key")
+		t.Setenv(envBaggageTagKeysDenylist, "api_key")
+		tags = newBaggageTagger().tags(baggage)
+		assert.Equal(t, map[string]string{"baggage.user.id": "1234"}, tags, "the denylist overrides the keys")
+	})
+}
+
+func TestSensitiveBaggageKey(t *testing.T) {
+	for _, key := range []string{
+		"password", "db.passwd", "pass_phrase", "secret", "client-secret",
+		"token", "auth.token", "refresh_tokens", "api_key", "apiKey", "API-KEY",
+		"private.key", "auth", "Cookie", "credentials", "bearer", "signature",
+	} {
+		assert.True(t, sensitiveBaggageKey(key), key)
+	}
+	for _, key := range []string{
+		"user.id", "author", "passenger", "tokenizer", "authority", "keyboard",
+		"compass", "session.id",
+	} {
+		assert.False(t, sensitiveBaggageKey(key), key)
+	}
+}
+
+func TestBaggagePromotion(t *testing.T) {
+	tracer, err := newTracer()
+	require.NoError(t, err)
+	defer tracer.Stop()
+
+	carrier := TextMapCarrier{
+		DefaultTraceIDHeader:    "1",
+		DefaultParentIDHeader:   "2",
+		"baggage":               "user.id=1234,color=blue,password=hunter2",
+		"ot-baggage-session.id": "789",
+	}
+	sctx, err := tracer.Extract(carrier)
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("kafka.consume", ChildOf(sctx))
+	assert.Equal(t, "1234", span.meta["baggage.user.id"])
+	assert.NotContains(t, span.meta, "baggage.color")
+	assert.NotContains(t, span.meta, "baggage.password")
+	assert.NotContains(t, span.meta, "baggage.session.id", "only the items of the baggage header are promoted")
+
+	child := tracer.StartSpan("child", ChildOf(span.Context()))
+	assert.NotContains(t, child.meta, "baggage.user.id", "only the spans started from the extracted context are tagged")
+
+	t.Run("baggage-only", func(t *testing.T) {
+		sctx, err := tracer.Extract(TextMapCarrier{"baggage": "account.id=456"})
+		require.NoError(t, err)
+		span := tracer.StartSpan("grpc.server", ChildOf(sctx))
+		assert.Equal(t, "456", span.meta["baggage.account.id"])
+	})
+}
+
+func TestExtractBaggageLimits(t *testing.T) {
+	items := make([]string, 0, 10)
+	for i := range 10 {
+		items = append(items, "key"+strconv.Itoa(i)+"=val")
+	}
+	header := strings.Join(items, ",")
+
+	t.Run("items", func(t *testing.T) {
+		telemetryClient := new(telemetrytest.RecordClient)
+		defer telemetry.MockClient(telemetryClient)()
+
+		p := NewPropagator(&PropagatorConfig{BaggageMaxItems: 3})
+		sctx, err := p.Extract(TextMapCarrier{"baggage": header})
+		require.NoError(t, err)
+		assert.Len(t, sctx.baggage, 3)
+		assert.NotZero(t, telemetryClient.Count(telemetry.NamespaceTracers, "context_header.truncated", []string{"header_style:baggage", "truncation_reason:baggage_item_count_exceeded"}).Get())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		telemetryClient := new(telemetrytest.RecordClient)
+		defer telemetry.MockClient(telemetryClient)()
+
+		// each item is 8 bytes, plus a separating comma
+		t.Setenv(envBaggageMaxBytes, "20")
+		p := NewPropagator(nil)
+		sctx, err := p.Extract(TextMapCarrier{"baggage": header})
+		require.NoError(t, err)
+		assert.Len(t, sctx.baggage, 2)
+		assert.NotZero(t, telemetryClient.Count(telemetry.NamespaceTracers, "context_header.truncated", []string{"header_style:baggage", "truncation_reason:baggage_byte_count_exceeded"}).Get())
+	})
+
+	t.Run("inject", func(t *testing.T) {
+		t.Setenv(envBaggageMaxItems, "2")
+		p := NewPropagator(nil)
+		sctx, err := p.Extract(TextMapCarrier{"baggage": header})
+		require.NoError(t, err)
+		assert.Len(t, sctx.baggage, 2)
+		sctx.setBaggageItem("extra", "val")
+
+		carrier := TextMapCarrier{}
+		require.NoError(t, (&propagatorBaggage{&PropagatorConfig{BaggageMaxItems: 2}}).Inject(sctx, carrier))
+		assert.Len(t, strings.Split(carrier["baggage"], ","), 2)
+	})
+}
@@ -0,0 +1,226 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// breakerFailureThreshold is the number of consecutive failed sends after
+	// which the circuit opens.
+	breakerFailureThreshold = 5
+
+	// breakerMinBackoff and breakerMaxBackoff bound the time during which the
+	// circuit stays open. It doubles each time a probe fails.
+	breakerMinBackoff = time.Second
+	breakerMaxBackoff = time.Minute
+
+	// breakerSlowLatency is the send latency above which the agent is
+	// considered overloaded, and the payloads are shrunk.
+	breakerSlowLatency = 2 * time.Second
+
+	// breakerMinPayloadSize is the smallest payload size limit the payloads
+	// are shrunk to.
+	breakerMinPayloadSize = int(payloadSizeLimit / 32)
+)
+
+// breakerState is the state of a circuitBreaker.
+type breakerState int
+
+const (
+	// breakerClosed lets all payloads be sent.
+	breakerClosed breakerState = iota
+	// breakerOpen drops the payloads until the backoff elapses.
+	breakerOpen
+	// breakerHalfOpen lets a single probe payload be sent to test the agent.
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// sendFailure classifies the error returned when sending a payload.
+type sendFailure int
+
+const (
+	// failureNetwork is a network error, or an unexpected response.
+	failureNetwork sendFailure = iota
+	// failureTooLarge is a 413 response: the payload must be split.
+	failureTooLarge
+	// failureOverloaded is a 429 or 503 response: the agent must be given time to recover.
+	failureOverloaded
+)
+
+// classifySendError returns the kind of failure err reports.
+func classifySendError(err error) sendFailure {
+	var apiErr *agentAPIError
+	if !errors.As(err, &apiErr) {
+		return failureNetwork
+	}
+	switch apiErr.statusCode {
+	case http.StatusRequestEntityTooLarge:
+		return failureTooLarge
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return failureOverloaded
+	default:
+		return failureNetwork
+	}
+}
+
+// overloadBackoff returns the time to wait before retrying to send a payload
+// to an overloaded agent: the retry interval doubled for each failed attempt,
+// up to breakerMaxBackoff. It doesn't overflow for any number of attempts.
+func overloadBackoff(interval time.Duration, attempt int) time.Duration {
+	for range attempt {
+		if interval >= breakerMaxBackoff/2 {
+			return breakerMaxBackoff
+		}
+		interval *= 2
+	}
+	return min(interval, breakerMaxBackoff)
+}
+
+// circuitBreaker tracks the health of the agent from the outcome and latency
+// of the payloads sent to it. It adapts the payload size limit, and opens a
+// circuit dropping the payloads without sending them when the agent keeps
+// failing, to avoid piling up uploads. Once the backoff elapses, a single probe
+// payload is sent, closing the circuit if it succeeds. The payload size limit
+// then grows back gradually.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    breakerState
+	failures int           // consecutive failed sends
+	backoff  time.Duration // time during which the circuit stays open
+	retryAt  time.Time     // time at which the open circuit lets a probe through
+	probing  bool          // whether a probe is being sent while half-open
+	latency  time.Duration // moving average of the successful send latencies
+	maxSize  int           // current payload size limit
+
+	now func() time.Time // replaced in tests
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{
+		backoff: breakerMinBackoff,
+		maxSize: payloadSizeLimit,
+		now:     time.Now,
+	}
+}
+
+// payloadSizeLimit returns the size above which payloads are flushed.
+func (b *circuitBreaker) payloadSizeLimit() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.maxSize
+}
+
+// allow reports whether a payload can be sent. It returns false while the
+// circuit is open, and lets a single probe through once the backoff elapsed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Before(b.retryAt) {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a payload sent in the given time. It closes the circuit, and
+// grows the payload size limit back if the agent is fast enough.
+func (b *circuitBreaker) success(latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.probing = false
+	b.failures = 0
+	b.backoff = breakerMinBackoff
+	if b.latency == 0 {
+		b.latency = latency
+	} else {
+		b.latency = (b.latency*7 + latency) / 8
+	}
+	if b.latency > breakerSlowLatency {
+		b.shrink()
+	} else if b.maxSize < payloadSizeLimit {
+		b.maxSize = min(payloadSizeLimit, b.maxSize+b.maxSize/4)
+	}
+}
+
+// failure records a failed send, and reports whether the circuit opened.
+func (b *circuitBreaker) failure(f sendFailure) (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if f != failureNetwork {
+		b.shrink()
+	}
+	if f == failureTooLarge {
+		// the agent is healthy, the payload is too large
+		if b.state == breakerHalfOpen {
+			b.probing = false
+		}
+		return false
+	}
+	b.failures++
+	switch b.state {
+	case breakerHalfOpen:
+		// the probe failed: back off longer
+		b.backoff = min(breakerMaxBackoff, 2*b.backoff)
+		b.open()
+		return true
+	case breakerClosed:
+		if b.failures >= breakerFailureThreshold {
+			b.open()
+			return true
+		}
+	}
+	return false
+}
+
+// open opens the circuit for the current backoff. b.mu must be held.
+func (b *circuitBreaker) open() {
+	b.state = breakerOpen
+	b.probing = false
+	b.retryAt = b.now().Add(b.backoff)
+}
+
+// shrink halves the payload size limit. b.mu must be held.
+func (b *circuitBreaker) shrink() {
+	b.maxSize = max(breakerMinPayloadSize, b.maxSize/2)
+}
+
+// currentState returns the state of the circuit.
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
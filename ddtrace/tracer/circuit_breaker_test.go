@@ -0,0 +1,231 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+func TestClassifySendError(t *testing.T) {
+	assert.Equal(t, failureNetwork, classifySendError(errors.New("connection refused")))
+	assert.Equal(t, failureNetwork, classifySendError(&agentAPIError{statusCode: http.StatusBadRequest}))
+	assert.Equal(t, failureTooLarge, classifySendError(&agentAPIError{statusCode: http.StatusRequestEntityTooLarge}))
+	assert.Equal(t, failureOverloaded, classifySendError(&agentAPIError{statusCode: http.StatusTooManyRequests}))
+	assert.Equal(t, failureOverloaded, classifySendError(fmt.Errorf("wrapped: %w", &agentAPIError{statusCode: http.StatusServiceUnavailable})))
+}
+
+func TestTransportAgentAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	}))
+	defer srv.Close()
+
+	trc := newHTTPTransport(srv.URL, defaultHTTPClient(time.Second, false))
+	p := newPayload()
+	require.NoError(t, p.push([]*Span{newBasicSpan("op")}))
+	_, err := trc.send(p)
+	require.Error(t, err)
+	assert.Equal(t, "slow down (Status: Too Many Requests)", err.Error())
+	assert.Equal(t, failureOverloaded, classifySendError(err))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker()
+	b.now = func() time.Time { return now }
+
+	t.Run("open", func(t *testing.T) {
+		for i := 1; i < breakerFailureThreshold; i++ {
+			assert.False(t, b.failure(failureNetwork))
+			assert.True(t, b.allow())
+		}
+		assert.True(t, b.failure(failureNetwork))
+		assert.Equal(t, breakerOpen, b.currentState())
+		assert.False(t, b.allow())
+		assert.Equal(t, payloadSizeLimit, float64(b.payloadSizeLimit()))
+	})
+
+	t.Run("probe-failed", func(t *testing.T) {
+		now = now.Add(breakerMinBackoff)
+		assert.True(t, b.allow())
+		assert.Equal(t, breakerHalfOpen, b.currentState())
+		assert.False(t, b.allow(), "a single probe is sent")
+		assert.True(t, b.failure(failureOverloaded))
+		assert.Equal(t, payloadSizeLimit/2, float64(b.payloadSizeLimit()))
+
+		// the backoff doubled
+		now = now.Add(breakerMinBackoff)
+		assert.False(t, b.allow())
+		now = now.Add(breakerMinBackoff)
+		assert.True(t, b.allow())
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		b.success(time.Millisecond)
+		assert.Equal(t, breakerClosed, b.currentState())
+		assert.True(t, b.allow())
+		assert.True(t, b.allow())
+		// the payload size limit grows back gradually
+		assert.Equal(t, int(payloadSizeLimit/2*1.25), b.payloadSizeLimit())
+		for range 10 {
+			b.success(time.Millisecond)
+		}
+		assert.Equal(t, payloadSizeLimit, float64(b.payloadSizeLimit()))
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		for range 10 {
+			assert.False(t, b.failure(failureTooLarge))
+		}
+		assert.Equal(t, breakerClosed, b.currentState())
+		assert.Equal(t, breakerMinPayloadSize, b.payloadSizeLimit())
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		b := newCircuitBreaker()
+		b.success(2 * breakerSlowLatency)
+		assert.Equal(t, payloadSizeLimit/2, float64(b.payloadSizeLimit()))
+		assert.Equal(t, breakerClosed, b.currentState())
+	})
+}
+
+// statusTransport responds to the sent payloads with the status codes in codes,
+// then succeeds. A 200 status code is a success.
+type statusTransport struct {
+	dummyTransport
+	mu       sync.Mutex
+	codes    []int
+	attempts int
+}
+
+func (t *statusTransport) send(p *payload) (io.ReadCloser, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts++
+	if len(t.codes) > 0 {
+		code := t.codes[0]
+		t.codes = t.codes[1:]
+		if code != http.StatusOK {
+			return nil, &agentAPIError{statusCode: code, msg: http.StatusText(code)}
+		}
+	}
+	return io.NopCloser(strings.NewReader(`{}`)), nil
+}
+
+func TestTraceWriterCircuitBreaker(t *testing.T) {
+	t.Run("open", func(t *testing.T) {
+		codes := make([]int, breakerFailureThreshold)
+		for i := range codes {
+			codes[i] = http.StatusServiceUnavailable
+		}
+		tr := &statusTransport{codes: codes}
+		c, err := newTestConfig(func(c *config) {
+			c.transport = tr
+			c.sendRetries = 2 * breakerFailureThreshold
+			c.retryInterval = time.Microsecond
+		})
+		require.NoError(t, err)
+		var tg statsdtest.TestStatsdClient
+		h := newAgentTraceWriter(c, newPrioritySampler(), &tg)
+
+		h.add([]*Span{makeSpan(0)})
+		h.flush()
+		h.wg.Wait()
+		// the retries stop when the circuit opens
+		assert.Equal(t, breakerFailureThreshold, tr.attempts)
+		assert.Equal(t, breakerOpen, h.breaker.currentState())
+		assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.circuit_breaker.opened"])
+
+		// the next payloads are dropped without being sent
+		h.add([]*Span{makeSpan(0)})
+		h.flush()
+		h.wg.Wait()
+		assert.Equal(t, breakerFailureThreshold, tr.attempts)
+		dropped := statsdtest.FilterCallsByName(tg.CountCalls(), "datadog.tracer.traces_dropped")
+		require.Len(t, dropped, 2)
+		assert.Equal(t, []string{"reason:send_failed"}, dropped[0].Tags())
+		assert.Equal(t, []string{"reason:circuit_open"}, dropped[1].Tags())
+
+		// the probe succeeds once the backoff elapsed
+		h.breaker.now = func() time.Time { return time.Now().Add(breakerMinBackoff) }
+		h.add([]*Span{makeSpan(0)})
+		h.flush()
+		h.wg.Wait()
+		assert.Equal(t, breakerFailureThreshold+1, tr.attempts)
+		assert.Equal(t, breakerClosed, h.breaker.currentState())
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		tr := &statusTransport{codes: []int{http.StatusRequestEntityTooLarge}}
+		c, err := newTestConfig(func(c *config) {
+			c.transport = tr
+			c.sendRetries = 3
+			c.retryInterval = time.Microsecond
+		})
+		require.NoError(t, err)
+		var tg statsdtest.TestStatsdClient
+		h := newAgentTraceWriter(c, newPrioritySampler(), &tg)
+
+		h.add([]*Span{makeSpan(0)})
+		h.flush()
+		h.wg.Wait()
+		assert.Equal(t, 1, tr.attempts, "payloads which are too large are not retried")
+		dropped := statsdtest.FilterCallsByName(tg.CountCalls(), "datadog.tracer.traces_dropped")
+		require.Len(t, dropped, 1)
+		assert.Equal(t, []string{"reason:payload_too_large"}, dropped[0].Tags())
+		assert.Equal(t, payloadSizeLimit/2, float64(h.breaker.payloadSizeLimit()))
+		assert.Equal(t, breakerClosed, h.breaker.currentState())
+	})
+
+	t.Run("split", func(t *testing.T) {
+		tr := &statusTransport{codes: []int{http.StatusRequestEntityTooLarge, http.StatusOK, http.StatusRequestEntityTooLarge}}
+		c, err := newTestConfig(func(c *config) {
+			c.transport = tr
+			c.sendRetries = 3
+			c.retryInterval = time.Microsecond
+		})
+		require.NoError(t, err)
+		var tg statsdtest.TestStatsdClient
+		h := newAgentTraceWriter(c, newPrioritySampler(), &tg)
+
+		for i := range 3 {
+			h.add([]*Span{makeSpan(i)})
+		}
+		h.flush()
+		h.wg.Wait()
+		// 3 traces, then 1 and 2 traces, the latter being split in 1 and 1
+		// traces
+		assert.Equal(t, 5, tr.attempts)
+		assert.Empty(t, statsdtest.FilterCallsByName(tg.CountCalls(), "datadog.tracer.traces_dropped"))
+		assert.Equal(t, int64(3), tg.Counts()["datadog.tracer.flush_traces"])
+		assert.Equal(t, int64(2), tg.Counts()["datadog.tracer.payload_split"])
+	})
+}
+
+func TestOverloadBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Millisecond, overloadBackoff(10*time.Millisecond, 0))
+	assert.Equal(t, 80*time.Millisecond, overloadBackoff(10*time.Millisecond, 3))
+	// the backoff doesn't overflow
+	for _, attempt := range []int{40, 63, 64, 1000} {
+		assert.Equal(t, breakerMaxBackoff, overloadBackoff(10*time.Millisecond, attempt))
+	}
+	assert.Equal(t, breakerMaxBackoff, overloadBackoff(time.Hour, 1))
+	assert.Zero(t, overloadBackoff(0, 1000))
+}
@@ -0,0 +1,281 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"encoding/json"
+	"html/template"
+	"maps"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+)
+
+var (
+	// debugTracesSize is the number of recently finished traces kept for the
+	// debug handler.
+	debugTracesSize = 100
+
+	// debugOpenSpansMax is the maximum number of open spans reported by the
+	// debug handler.
+	debugOpenSpansMax = 1000
+)
+
+// debugTraces holds the recently finished traces once DebugHandler was called.
+var debugTraces atomic.Pointer[debugTraceRing]
+
+// DebugHandler returns an http.Handler reporting the state of the running
+// tracer, to help troubleshooting it without access to the agent: the recently
+// finished traces, the open spans, the sampling rules, the effective
+// configuration, and the configuration applied through remote configuration.
+//
+// The report is served as JSON when the request has the "format=json" query
+// parameter or accepts "application/json", and as a minimal HTML page
+// otherwise. Open spans are only reported when the abandoned spans debugger is
+// enabled with WithDebugSpansMode.
+//
+// Finished traces are only recorded once DebugHandler was called. The handler
+// exposes tag values, so it must not be reachable from untrusted networks.
+func DebugHandler() http.Handler {
+	debugTraces.CompareAndSwap(nil, newDebugTraceRing(debugTracesSize))
+	return debugHandler{}
+}
+
+// debugSpan is a copy of a finished span.
+type debugSpan struct {
+	Name     string             `json:"name"`
+	Service  string             `json:"service"`
+	Resource string             `json:"resource"`
+	Type     string             `json:"type,omitempty"`
+	TraceID  string             `json:"trace_id"`
+	SpanID   uint64             `json:"span_id"`
+	ParentID uint64             `json:"parent_id"`
+	Start    time.Time          `json:"start"`
+	Duration time.Duration      `json:"duration"`
+	Error    int32              `json:"error"`
+	Meta     map[string]string  `json:"meta,omitempty"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+}
+
+func newDebugSpan(s *Span) debugSpan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return debugSpan{
+		Name:     s.name,
+		Service:  s.service,
+		Resource: s.resource,
+		Type:     s.spanType,
+		TraceID:  s.context.TraceID(),
+		SpanID:   s.spanID,
+		ParentID: s.parentID,
+		Start:    time.Unix(0, s.start),
+		Duration: time.Duration(s.duration),
+		Error:    s.error,
+		Meta:     maps.Clone(s.meta),
+		Metrics:  maps.Clone(s.metrics),
+	}
+}
+
+// debugTrace is a trace chunk sent to the agent.
+type debugTrace struct {
+	Time  time.Time   `json:"time"`
+	Spans []debugSpan `json:"spans"`
+}
+
+// debugTraceRing is a bounded ring of the most recently sent trace chunks.
+type debugTraceRing struct {
+	mu     sync.Mutex
+	traces []debugTrace
+	next   int // index of the next trace to overwrite, once the ring is full
+}
+
+func newDebugTraceRing(size int) *debugTraceRing {
+	return &debugTraceRing{traces: make([]debugTrace, 0, size)}
+}
+
+// push records a copy of spans, replacing the oldest trace if the ring is full.
+func (r *debugTraceRing) push(spans []*Span) {
+	t := debugTrace{
+		Time:  time.Now(),
+		Spans: make([]debugSpan, len(spans)),
+	}
+	for i, s := range spans {
+		t.Spans[i] = newDebugSpan(s)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.traces) < cap(r.traces) {
+		r.traces = append(r.traces, t)
+		return
+	}
+	r.traces[r.next] = t
+	r.next = (r.next + 1) % len(r.traces)
+}
+
+// list returns the recorded traces, most recent first.
+func (r *debugTraceRing) list() []debugTrace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	traces := make([]debugTrace, 0, len(r.traces))
+	for i := len(r.traces) - 1; i >= 0; i-- {
+		traces = append(traces, r.traces[(r.next+i)%len(r.traces)])
+	}
+	return traces
+}
+
+// debugOpenSpan is a span which is not finished yet.
+type debugOpenSpan struct {
+	Name        string        `json:"name"`
+	Integration string        `json:"integration"`
+	TraceID     uint64        `json:"trace_id"`
+	SpanID      uint64        `json:"span_id"`
+	Start       time.Time     `json:"start"`
+	Age         time.Duration `json:"age"`
+}
+
+// debugSampling holds the sampling configuration currently in use.
+type debugSampling struct {
+	SampleRate  *float64       `json:"sample_rate,omitempty"`
+	RateLimit   float64        `json:"rate_limit"`
+	TraceRules  []SamplingRule `json:"trace_rules"`
+	SpanRules   []SamplingRule `json:"span_rules"`
+	TailEnabled bool           `json:"tail_sampling_enabled"`
+}
+
+// debugReport is the report served by the debug handler.
+type debugReport struct {
+	Started bool `json:"started"`
+	// OpenSpansEnabled reports whether the abandoned spans debugger tracks
+	// the open spans.
+	OpenSpansEnabled bool                      `json:"open_spans_enabled"`
+	Config           *startupInfo              `json:"config,omitempty"`
+	Sampling         *debugSampling            `json:"sampling,omitempty"`
+	RemoteConfig     []telemetry.Configuration `json:"remote_config,omitempty"`
+	OpenSpans        []debugOpenSpan           `json:"open_spans"`
+	Traces           []debugTrace              `json:"traces"`
+}
+
+// newDebugReport returns the report of the global tracer.
+func newDebugReport() debugReport {
+	var r debugReport
+	if ring := debugTraces.Load(); ring != nil {
+		r.Traces = ring.list()
+	}
+	t, ok := getGlobalTracer().(*tracer)
+	if !ok {
+		return r
+	}
+	r.Started = true
+	info := newStartupInfo(t)
+	r.Config = &info
+
+	s := &debugSampling{
+		TraceRules:  t.config.traceSampleRules.get(),
+		SpanRules:   t.config.spanRules,
+		TailEnabled: t.tailSampler != nil,
+	}
+	if rate := t.config.traceSampleRate.get(); !math.IsNaN(rate) {
+		s.SampleRate = &rate
+	}
+	if limit, ok := t.rulesSampling.TraceRateLimit(); ok {
+		s.RateLimit = limit
+	}
+	r.Sampling = s
+
+	r.RemoteConfig = []telemetry.Configuration{
+		t.config.traceSampleRate.toTelemetry(),
+		t.config.traceSampleRules.toTelemetry(),
+		t.config.headerAsTags.toTelemetry(),
+		t.config.globalTags.toTelemetry(),
+		t.config.enabled.toTelemetry(),
+	}
+	for i, c := range r.RemoteConfig {
+		if v, ok := c.Value.(float64); ok && math.IsNaN(v) {
+			// NaN can't be encoded to JSON, it stands for an unset value
+			r.RemoteConfig[i].Value = nil
+		}
+	}
+
+	if d := t.abandonedSpansDebugger; d != nil {
+		r.OpenSpansEnabled = true
+		now := time.Now()
+		for _, c := range d.snapshot(debugOpenSpansMax) {
+			start := time.Unix(0, c.Start)
+			r.OpenSpans = append(r.OpenSpans, debugOpenSpan{
+				Name:        c.Name,
+				Integration: c.Integration,
+				TraceID:     c.TraceID,
+				SpanID:      c.SpanID,
+				Start:       start,
+				Age:         now.Sub(start),
+			})
+		}
+	}
+	return r
+}
+
+// debugHandler serves the report of the global tracer.
+type debugHandler struct{}
+
+func (debugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := newDebugReport()
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Debug("Failed to encode the tracer debug report: %s", err.Error())
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := debugPage.Execute(w, report); err != nil {
+		log.Debug("Failed to render the tracer debug report: %s", err.Error())
+	}
+}
+
+var debugPage = template.Must(template.New("debug").Funcs(template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>Datadog tracer</title></head>
+<body>
+<h1>Datadog tracer</h1>
+{{if not .Started}}<p>The tracer is not started.</p>{{else}}
+<h2>Configuration</h2>
+<pre>{{json .Config}}</pre>
+<h2>Sampling</h2>
+<pre>{{json .Sampling}}</pre>
+<h2>Remote configuration</h2>
+<table>
+<tr><th>Name</th><th>Value</th><th>Origin</th></tr>
+{{range .RemoteConfig}}<tr><td>{{.Name}}</td><td>{{json .Value}}</td><td>{{.Origin}}</td></tr>
+{{end}}</table>
+<h2>Open spans</h2>
+{{if not .OpenSpansEnabled}}<p>Open spans are tracked when the tracer is started with WithDebugSpansMode.</p>{{else}}
+<table>
+<tr><th>Name</th><th>Integration</th><th>Trace ID</th><th>Span ID</th><th>Age</th></tr>
+{{range .OpenSpans}}<tr><td>{{.Name}}</td><td>{{.Integration}}</td><td>{{.TraceID}}</td><td>{{.SpanID}}</td><td>{{.Age}}</td></tr>
+{{end}}</table>{{end}}{{end}}
+<h2>Recent traces</h2>
+{{range .Traces}}<h3>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</h3>
+<table>
+<tr><th>Name</th><th>Service</th><th>Resource</th><th>Trace ID</th><th>Span ID</th><th>Parent ID</th><th>Duration</th><th>Error</th></tr>
+{{range .Spans}}<tr><td>{{.Name}}</td><td>{{.Service}}</td><td>{{.Resource}}</td><td>{{.TraceID}}</td><td>{{.SpanID}}</td><td>{{.ParentID}}</td><td>{{.Duration}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+{{else}}<p>No traces were sent since the handler was created.</p>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+func TestDebugTraceRing(t *testing.T) {
+	r := newDebugTraceRing(2)
+	for _, name := range []string{"a", "b", "c"} {
+		r.push([]*Span{newBasicSpan(name)})
+	}
+	traces := r.list()
+	require.Len(t, traces, 2)
+	assert.Equal(t, "c", traces[0].Spans[0].Name)
+	assert.Equal(t, "b", traces[1].Spans[0].Name)
+}
+
+func TestDebugHandler(t *testing.T) {
+	t.Cleanup(func() { debugTraces.Store(nil) })
+	h := DebugHandler()
+
+	t.Run("not-started", func(t *testing.T) {
+		var report debugReport
+		serveDebugJSON(t, h, &report)
+		assert.False(t, report.Started)
+	})
+
+	tracer, _, flush, stop, err := startTestTracer(t,
+		WithDebugSpansMode(time.Minute),
+		WithSamplingRules(TraceSamplingRules(Rule{ServiceGlob: "web", Rate: 0.5})),
+	)
+	require.NoError(t, err)
+	defer stop()
+
+	root := tracer.StartSpan("http.request", ServiceName("web"), ResourceName("GET /"))
+	tracer.StartSpan("db.query", ChildOf(root.Context()), ResourceName("SELECT")).Finish()
+	root.SetTag(ext.ManualKeep, true)
+	root.Finish()
+	flush(1)
+	open := tracer.StartSpan("open")
+	defer open.Finish()
+
+	t.Run("json", func(t *testing.T) {
+		var report debugReport
+		require.Eventually(t, func() bool {
+			report = debugReport{}
+			serveDebugJSON(t, h, &report)
+			return len(report.OpenSpans) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.True(t, report.Started)
+		assert.True(t, report.OpenSpansEnabled)
+		assert.Equal(t, "open", report.OpenSpans[0].Name)
+		require.NotNil(t, report.Config)
+		assert.Equal(t, tracer.config.serviceName, report.Config.Service)
+		require.NotNil(t, report.Sampling)
+		require.Len(t, report.Sampling.TraceRules, 1)
+		assert.Equal(t, 0.5, report.Sampling.TraceRules[0].Rate)
+		assert.NotEmpty(t, report.RemoteConfig)
+
+		require.Len(t, report.Traces, 1)
+		spans := report.Traces[0].Spans
+		require.Len(t, spans, 2)
+		assert.Equal(t, "http.request", spans[0].Name)
+		assert.Equal(t, "web", spans[0].Service)
+		assert.Equal(t, "db.query", spans[1].Name)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "<td>http.request</td>")
+		assert.Contains(t, body, "<td>open</td>")
+	})
+}
+
+func serveDebugJSON(t *testing.T, h http.Handler, report *debugReport) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), report))
+}
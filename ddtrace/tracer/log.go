@@ -63,6 +63,8 @@ type startupInfo struct {
 	TracingAsTransport          bool                         `json:"tracing_as_transport"`      // Whether the tracer is disabled and other products are using it as a transport
 	DogstatsdAddr               string                       `json:"dogstatsd_address"`         // Destination of statsd payloads
 	DataStreamsEnabled          bool                         `json:"data_streams_enabled"`      // Whether Data Streams is enabled
+	OTLPEndpoint                string                       `json:"otlp_endpoint,omitempty"`   // The OTLP endpoint traces are sent to, if any
+	RuntimeMetricsHistograms    bool                         `json:"runtime_metrics_histograms_enabled"`
 }
 
 // checkEndpoint tries to connect to the URL specified by endpoint.
@@ -83,9 +85,8 @@ func checkEndpoint(c *http.Client, endpoint string) error {
 	return nil
 }
 
-// logStartup generates a startupInfo for a tracer and writes it to the log in
-// JSON format.
-func logStartup(t *tracer) {
+// newStartupInfo returns the startupInfo describing the configuration of t.
+func newStartupInfo(t *tracer) startupInfo {
 	tags := make(map[string]string)
 	for k, v := range t.config.globalTags.get() {
 		tags[k] = fmt.Sprintf("%v", v)
@@ -135,6 +136,7 @@ func logStartup(t *tracer) {
 		Tags:                        tags,
 		RuntimeMetricsEnabled:       t.config.runtimeMetrics,
 		RuntimeMetricsV2Enabled:     t.config.runtimeMetricsV2,
+		RuntimeMetricsHistograms:    t.config.runtimeMetricsHistograms,
 		ApplicationVersion:          t.config.version,
 		ProfilerCodeHotspotsEnabled: t.config.profilerHotspots,
 		ProfilerEndpointsEnabled:    t.config.profilerEndpoints,
@@ -154,13 +156,23 @@ func logStartup(t *tracer) {
 		DogstatsdAddr:               t.config.dogstatsdAddr,
 		DataStreamsEnabled:          t.config.dataStreamsMonitoringEnabled,
 	}
+	if t.config.otlpEndpoint != nil {
+		info.OTLPEndpoint = t.config.otlpEndpoint.Redacted()
+	}
 	if _, _, err := samplingRulesFromEnv(); err != nil {
 		info.SamplingRulesError = fmt.Sprintf("%s", err.Error())
 	}
 	if limit, ok := t.rulesSampling.TraceRateLimit(); ok {
 		info.SampleRateLimit = fmt.Sprintf("%v", limit)
 	}
-	if !t.config.logToStdout {
+	return info
+}
+
+// logStartup generates a startupInfo for a tracer and writes it to the log in
+// JSON format.
+func logStartup(t *tracer) {
+	info := newStartupInfo(t)
+	if !t.config.logToStdout && t.config.otlpEndpoint == nil {
 		if err := checkEndpoint(t.config.httpClient, t.config.transport.endpoint()); err != nil {
 			info.AgentError = fmt.Sprintf("%s", err.Error())
 			log.Warn("DIAGNOSTICS Unable to reach agent intake: %s", err.Error())
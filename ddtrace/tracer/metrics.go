@@ -6,11 +6,17 @@
 package tracer
 
 import (
+	"maps"
+	"math"
 	"runtime"
 	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/internal/tracerstats"
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 )
 
@@ -116,3 +122,174 @@ func (t *tracer) reportHealthMetricsAtInterval(interval time.Duration) {
 		}
 	}
 }
+
+// runtimeMetricsHistogramsGoroutines lists the runtime/metrics reported by
+// reportRuntimeMetricsHistograms, alongside the runtime.MemStats based metrics
+// of reportRuntimeMetrics, which runtime metrics v2 don't report, by metric
+// name. The metrics which are not supported by the Go version in use are
+// skipped.
+var runtimeMetricsHistogramsGoroutines = map[string]string{
+	"/sched/goroutines/not-in-go:goroutines": "runtime.go.goroutines",
+	"/sched/goroutines/runnable:goroutines":  "runtime.go.goroutines",
+	"/sched/goroutines/running:goroutines":   "runtime.go.goroutines",
+	"/sched/goroutines/waiting:goroutines":   "runtime.go.goroutines",
+}
+
+// runtimeMetricsHistogramsDetails lists the runtime/metrics reported by
+// reportRuntimeMetricsHistograms as histograms, or broken down by tags, by
+// metric name. They are also reported by the runtime metrics v2 emitter (see
+// DD_RUNTIME_METRICS_V2_ENABLED), so they are only reported when it is
+// disabled.
+var runtimeMetricsHistogramsDetails = map[string]string{
+	"/sched/pauses/total/gc:seconds": "runtime.go.gc.pauses",
+	"/sched/latencies:seconds":       "runtime.go.sched.latencies",
+	"/gc/heap/allocs-by-size:bytes":  "runtime.go.gc.heap.allocs_by_size",
+	"/sync/mutex/wait/total:seconds": "runtime.go.sync.mutex.wait_total_ns",
+}
+
+// runtimeMetricsHistogramsMaxSamples is the maximum number of values submitted for each
+// histogram at each report. When more events were recorded, the values are
+// sampled, and submitted with the corresponding sample rate.
+const runtimeMetricsHistogramsMaxSamples = 1000
+
+// runtimeMetricsCollector reports the runtime metrics read from runtime/metrics
+// which reportRuntimeMetrics doesn't report. Histograms are reported as distributions of the values recorded since the
+// previous report.
+type runtimeMetricsCollector struct {
+	statsd  globalinternal.StatsdClient
+	samples []metrics.Sample
+	names   map[string]string // names of the reported metrics, by metric name
+
+	// prev holds the bucket counts of the histograms at the previous report, by metric name.
+	prev map[string][]uint64
+}
+
+// newRuntimeMetricsCollector returns a collector of the goroutine states,
+// and of the detailed runtime metrics unless the runtime metrics v2 emitter
+// reports them already.
+func newRuntimeMetricsCollector(statsd globalinternal.StatsdClient, details bool) *runtimeMetricsCollector {
+	c := &runtimeMetricsCollector{
+		statsd: statsd,
+		names:  maps.Clone(runtimeMetricsHistogramsGoroutines),
+		prev:   make(map[string][]uint64),
+	}
+	if details {
+		maps.Copy(c.names, runtimeMetricsHistogramsDetails)
+	}
+	for _, d := range metrics.All() {
+		if _, ok := c.names[d.Name]; ok {
+			c.samples = append(c.samples, metrics.Sample{Name: d.Name})
+		}
+	}
+	return c
+}
+
+// report reads the runtime metrics and submits them.
+func (c *runtimeMetricsCollector) report() {
+	metrics.Read(c.samples)
+	statsd := c.statsd
+	for _, s := range c.samples {
+		name := c.names[s.Name]
+		switch s.Name {
+		case "/sched/pauses/total/gc:seconds", "/sched/latencies:seconds":
+			c.reportDurations(name, s.Name, s.Value.Float64Histogram())
+		case "/gc/heap/allocs-by-size:bytes":
+			c.reportSizeClasses(name, s.Name, s.Value.Float64Histogram())
+		case "/sched/goroutines/not-in-go:goroutines", "/sched/goroutines/runnable:goroutines",
+			"/sched/goroutines/running:goroutines", "/sched/goroutines/waiting:goroutines":
+			state := strings.TrimSuffix(strings.TrimPrefix(s.Name, "/sched/goroutines/"), ":goroutines")
+			statsd.Gauge(name, float64(s.Value.Uint64()), []string{"state:" + state}, 1)
+		case "/sync/mutex/wait/total:seconds":
+			statsd.Gauge(name, s.Value.Float64()*float64(time.Second), nil, 1)
+		}
+	}
+}
+
+// delta returns the number of values recorded in each bucket of the histogram
+// h since the previous report.
+func (c *runtimeMetricsCollector) delta(metric string, h *metrics.Float64Histogram) []uint64 {
+	prev := c.prev[metric]
+	delta := make([]uint64, len(h.Counts))
+	for i, n := range h.Counts {
+		if i < len(prev) && n >= prev[i] {
+			n -= prev[i]
+		}
+		delta[i] = n
+	}
+	c.prev[metric] = append(prev[:0], h.Counts...)
+	return delta
+}
+
+// reportDurations submits the durations recorded in the histogram h, in
+// seconds, as a distribution of nanoseconds.
+func (c *runtimeMetricsCollector) reportDurations(name, metric string, h *metrics.Float64Histogram) {
+	delta := c.delta(metric, h)
+	var total uint64
+	for _, n := range delta {
+		total += n
+	}
+	if total == 0 {
+		return
+	}
+	rate := 1.0
+	if total > runtimeMetricsHistogramsMaxSamples {
+		rate = float64(runtimeMetricsHistogramsMaxSamples) / float64(total)
+	}
+	values := make([]float64, 0, min(total, runtimeMetricsHistogramsMaxSamples))
+	for i, n := range delta {
+		k := int(math.Round(float64(n) * rate))
+		v := bucketValue(h.Buckets[i], h.Buckets[i+1]) * float64(time.Second)
+		for range k {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	c.statsd.DistributionSamples(name, values, nil, rate)
+}
+
+// reportSizeClasses submits the number of allocations recorded in each bucket
+// of the histogram h, tagged with the upper bound of the size class in bytes.
+func (c *runtimeMetricsCollector) reportSizeClasses(name, metric string, h *metrics.Float64Histogram) {
+	for i, n := range c.delta(metric, h) {
+		if n == 0 {
+			continue
+		}
+		class := "inf"
+		if upper := h.Buckets[i+1]; !math.IsInf(upper, 1) {
+			class = strconv.FormatFloat(upper, 'f', -1, 64)
+		}
+		c.statsd.Count(name, int64(n), []string{"size_class:" + class}, 1)
+	}
+}
+
+// bucketValue returns the value representing the histogram bucket [lower, upper).
+func bucketValue(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	default:
+		return (lower + upper) / 2
+	}
+}
+
+// reportRuntimeMetricsHistograms periodically reports the go runtime metrics
+// read from runtime/metrics at the given interval, in addition to the ones
+// reported by reportRuntimeMetrics.
+func (t *tracer) reportRuntimeMetricsHistograms(interval time.Duration) {
+	c := newRuntimeMetricsCollector(t.statsd, !t.config.runtimeMetricsV2)
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			log.Debug("Reporting runtime metrics histograms...")
+			c.report()
+		case <-t.stop:
+			return
+		}
+	}
+}
@@ -6,11 +6,15 @@
 package tracer
 
 import (
+	"math"
+	"runtime"
+	"runtime/metrics"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
@@ -45,6 +49,131 @@ func TestReportRuntimeMetrics(t *testing.T) {
 	assert.Contains(calls, "runtime.go.gc_stats.pause_quantiles.75p")
 }
 
+func TestReportRuntimeMetricsHistograms(t *testing.T) {
+	report := func(t *testing.T, v2 bool) *statsdtest.TestStatsdClient {
+		var tg statsdtest.TestStatsdClient
+		trc, err := newUnstartedTracer(withStatsdClient(&tg))
+		require.NoError(t, err)
+		defer trc.statsd.Close()
+		trc.config.runtimeMetricsV2 = v2
+
+		runtime.GC()
+		trc.wg.Add(1)
+		go func() {
+			defer trc.wg.Done()
+			trc.reportRuntimeMetricsHistograms(time.Millisecond)
+		}()
+		err = tg.Wait(assert.New(t), 10, 1*time.Second)
+		trc.Stop()
+		assert.NoError(t, err)
+		return &tg
+	}
+
+	t.Run("details", func(t *testing.T) {
+		tg := report(t, false)
+		assert := assert.New(t)
+		calls := tg.CallNames()
+		assert.Contains(calls, "runtime.go.goroutines")
+		assert.Contains(calls, "runtime.go.sync.mutex.wait_total_ns")
+		// the runtime.MemStats based metrics are reported by reportRuntimeMetrics
+		assert.NotContains(calls, "runtime.go.num_goroutine")
+
+		var pauses, latencies int
+		for _, c := range tg.DistributionCalls() {
+			switch c.Name() {
+			case "runtime.go.gc.pauses":
+				pauses++
+			case "runtime.go.sched.latencies":
+				latencies++
+			}
+			assert.Positive(c.FloatVal())
+		}
+		assert.Positive(pauses)
+		assert.Positive(latencies)
+
+		allocs := statsdtest.FilterCallsByName(tg.CountCalls(), "runtime.go.gc.heap.allocs_by_size")
+		assert.NotEmpty(allocs)
+		for _, c := range allocs {
+			assert.Positive(c.IntVal())
+			assert.Len(c.Tags(), 1)
+			assert.Contains(c.Tags()[0], "size_class:")
+		}
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		// the runtime metrics v2 emitter reports the details already
+		tg := report(t, true)
+		calls := tg.CallNames()
+		assert.Contains(t, calls, "runtime.go.goroutines")
+		assert.NotContains(t, calls, "runtime.go.sync.mutex.wait_total_ns")
+		for _, c := range statsdtest.FilterCallsByName(tg.GaugeCalls(), "runtime.go.goroutines") {
+			assert.Len(t, c.Tags(), 1)
+			assert.Contains(t, c.Tags()[0], "state:")
+		}
+		assert.Empty(t, tg.DistributionCalls())
+		assert.Empty(t, tg.CountCalls())
+	})
+}
+
+func TestRuntimeMetricsCollectorHistograms(t *testing.T) {
+	var tg statsdtest.TestStatsdClient
+	c := newRuntimeMetricsCollector(&tg, true)
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 0},
+		Buckets: []float64{math.Inf(-1), 0.001, 0.003, math.Inf(1)},
+	}
+
+	c.reportDurations("test.durations", "/test:seconds", h)
+	calls := tg.DistributionCalls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, float64(time.Millisecond), calls[0].FloatVal())
+	assert.Equal(t, float64(2*time.Millisecond), calls[1].FloatVal())
+	assert.Equal(t, float64(2*time.Millisecond), calls[2].FloatVal())
+	assert.Equal(t, 1.0, calls[0].Rate())
+
+	// only the values recorded since the previous report are submitted
+	tg.Reset()
+	h.Counts = []uint64{1, 2, 1}
+	c.reportDurations("test.durations", "/test:seconds", h)
+	calls = tg.DistributionCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, float64(3*time.Millisecond), calls[0].FloatVal())
+
+	// nothing happened
+	tg.Reset()
+	c.reportDurations("test.durations", "/test:seconds", h)
+	assert.Empty(t, tg.DistributionCalls())
+
+	// many values are sampled
+	tg.Reset()
+	h.Counts = []uint64{1, 2 + 3*runtimeMetricsHistogramsMaxSamples, 1 + runtimeMetricsHistogramsMaxSamples}
+	c.reportDurations("test.durations", "/test:seconds", h)
+	calls = tg.DistributionCalls()
+	assert.Len(t, calls, runtimeMetricsHistogramsMaxSamples)
+	assert.Equal(t, 0.25, calls[0].Rate())
+
+	tg.Reset()
+	c.reportSizeClasses("test.sizes", "/test:bytes", &metrics.Float64Histogram{
+		Counts:  []uint64{3, 0, 1},
+		Buckets: []float64{0, 8, 16, math.Inf(1)},
+	})
+	counts := tg.CountCalls()
+	require.Len(t, counts, 2)
+	assert.Equal(t, int64(3), counts[0].IntVal())
+	assert.Equal(t, []string{"size_class:8"}, counts[0].Tags())
+	assert.Equal(t, int64(1), counts[1].IntVal())
+	assert.Equal(t, []string{"size_class:inf"}, counts[1].Tags())
+}
+
+func TestWithRuntimeMetricsHistograms(t *testing.T) {
+	c, err := newTestConfig(WithRuntimeMetricsHistograms())
+	require.NoError(t, err)
+	assert.True(t, c.runtimeMetrics)
+	assert.True(t, c.runtimeMetricsHistograms)
+	// runtime metrics v2 are left as configured
+	assert.True(t, c.runtimeMetricsV2)
+}
+
 func TestReportHealthMetricsAtInterval(t *testing.T) {
 	assert := assert.New(t)
 	var tg statsdtest.TestStatsdClient
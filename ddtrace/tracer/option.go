@@ -87,8 +87,10 @@ var contribIntegrations = map[string]struct {
 	"github.com/labstack/echo/v4":                   {"echo v4", false},
 	"log/slog":                                      {"log/slog", false},
 	"github.com/miekg/dns":                          {"miekg/dns", false},
+	"github.com/nats-io/nats.go":                    {"NATS", false},
 	"net/http":                                      {"HTTP", false},
 	"gopkg.in/olivere/elastic.v5":                   {"Elasticsearch v5", false},
+	"github.com/rabbitmq/amqp091-go":                {"RabbitMQ", false},
 	"github.com/redis/go-redis/v9":                  {"Redis v9", false},
 	"github.com/redis/rueidis":                      {"Rueidis", false},
 	"github.com/segmentio/kafka-go":                 {"Kafka v0", false},
@@ -208,6 +210,10 @@ type config struct {
 	// runtimeMetricsV2 specifies whether collection of runtime metrics v2 is enabled.
 	runtimeMetricsV2 bool
 
+	// runtimeMetricsHistograms specifies whether runtime metrics are collected
+	// from runtime/metrics, including histograms, instead of runtime.MemStats.
+	runtimeMetricsHistograms bool
+
 	// dogstatsdAddr specifies the address to connect for sending metrics to the
 	// Datadog Agent. If not set, it defaults to "localhost:8125" or to the
 	// combination of the environment variables DD_AGENT_HOST and DD_DOGSTATSD_PORT.
@@ -310,6 +316,34 @@ type config struct {
 
 	// traceRateLimitPerSecond specifies the rate limit for traces.
 	traceRateLimitPerSecond float64
+
+	// otlpEndpoint, when set, is the OTLP endpoint to which traces are sent instead
+	// of the Datadog Agent.
+	otlpEndpoint *url.URL
+
+	// spillDir, when set, is the directory where trace payloads which could not be
+	// sent to the agent are stored until they can be replayed.
+	spillDir string
+
+	// spillMaxBytes is the maximum total size of the payloads stored in spillDir.
+	spillMaxBytes int64
+
+	// tailSamplingWindow is the time during which dropped traces are held to
+	// apply tailSamplingRules.
+	tailSamplingWindow time.Duration
+
+	// tailSamplingRules decide whether to keep traces after they finished.
+	tailSamplingRules []TailSamplingRule
+
+	// tagScrubRules are the rules scrubbing span tags before they are sent.
+	tagScrubRules []TagScrubRule
+
+	// tagScrubHashKey is the key of the HMAC of the values hashed by
+	// tagScrubRules. A random key is used if it is empty.
+	tagScrubHashKey []byte
+
+	// spanProcessors are notified of started spans and finished traces.
+	spanProcessors []SpanProcessor
 }
 
 // orchestrionConfig contains Orchestrion configuration.
@@ -466,6 +500,16 @@ func newConfig(opts ...StartOption) (*config, error) {
 	}
 	c.statsComputationEnabled = internal.BoolEnv("DD_TRACE_STATS_COMPUTATION_ENABLED", true)
 	c.dataStreamsMonitoringEnabled, _, _ = stableconfig.Bool("DD_DATA_STREAMS_ENABLED", false)
+	if v, _ := stableconfig.String("DD_TRACE_TAG_SCRUBBING_RULES", ""); v != "" {
+		rules, err := tagScrubRulesFromEnv(v)
+		if err != nil {
+			log.Warn("DIAGNOSTICS Error parsing DD_TRACE_TAG_SCRUBBING_RULES: %s", err.Error())
+		}
+		c.tagScrubRules = rules
+	}
+	if v, _ := stableconfig.String("DD_TRACE_TAG_SCRUBBING_HASH_KEY", ""); v != "" {
+		c.tagScrubHashKey = []byte(v)
+	}
 	c.partialFlushEnabled = internal.BoolEnv("DD_TRACE_PARTIAL_FLUSH_ENABLED", false)
 	c.partialFlushMinSpans = internal.IntEnv("DD_TRACE_PARTIAL_FLUSH_MIN_SPANS", partialFlushMinSpansDefault)
 	if c.partialFlushMinSpans <= 0 {
@@ -539,6 +583,8 @@ func newConfig(opts ...StartOption) (*config, error) {
 			}
 		}
 	}
+	globalconfig.SetServiceVersion(c.version)
+	globalconfig.SetEnv(c.env)
 	if c.serviceName == "" {
 		if v, ok := globalTags["service"]; ok {
 			if s, ok := v.(string); ok {
@@ -586,8 +632,8 @@ func newConfig(opts ...StartOption) (*config, error) {
 		c.ciVisibilityAgentless = ciTransport.agentless
 	}
 
-	// if using stdout or traces are disabled or we are in ci visibility agentless mode, agent is disabled
-	agentDisabled := c.logToStdout || !c.enabled.current || c.ciVisibilityAgentless
+	// if using stdout or an OTLP endpoint, traces are disabled or we are in ci visibility agentless mode, agent is disabled
+	agentDisabled := c.logToStdout || c.otlpEndpoint != nil || !c.enabled.current || c.ciVisibilityAgentless
 	c.agent = loadAgentFeatures(agentDisabled, c.agentURL, c.httpClient)
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
@@ -967,6 +1013,101 @@ func WithSendRetries(retries int) StartOption {
 	}
 }
 
+// WithOTLPExporter configures the tracer to send traces to the OpenTelemetry
+// Protocol (OTLP) endpoint at the given URL, such as an OpenTelemetry Collector,
+// instead of the Datadog Agent. The URL scheme selects the protocol: "http" and
+// "https" use OTLP/HTTP with protobuf encoding (the path defaults to /v1/traces),
+// "grpc" uses OTLP/gRPC over plaintext and "grpcs" uses OTLP/gRPC over TLS.
+// For example: "http://localhost:4318" or "grpc://localhost:4317".
+//
+// As there is no Agent to apply sampling decisions, traces rejected by the
+// samplers are dropped by the tracer and client-side stats are not computed.
+func WithOTLPExporter(endpoint string) StartOption {
+	return func(c *config) {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			log.Warn("Fail to parse OTLP endpoint: %s", err.Error())
+			return
+		}
+		switch u.Scheme {
+		case "http", "https", "grpc", "grpcs":
+			c.otlpEndpoint = u
+		default:
+			log.Warn("Unsupported protocol %q in OTLP endpoint %q. Must be one of: http, https, grpc, grpcs.", u.Scheme, u.Redacted())
+		}
+	}
+}
+
+// WithPayloadSpillDir enables spilling trace payloads to disk when they cannot be
+// sent to the agent after all retries (see WithSendRetries), for instance while the
+// agent restarts. Spilled payloads are written to dir in the background, and replayed
+// once the agent is reachable again, which is checked every few seconds even if no
+// traces are sent. Payloads left by a previous run of the program are replayed when
+// the tracer starts. At most maxBytes of
+// payloads are kept; when the limit is reached the oldest payloads are dropped.
+// The directory must not be shared with other processes.
+func WithPayloadSpillDir(dir string, maxBytes int64) StartOption {
+	return func(c *config) {
+		c.spillDir = dir
+		c.spillMaxBytes = maxBytes
+	}
+}
+
+// WithTailSampling enables tail sampling: traces which were dropped by the
+// sampling decision taken when their root span started are held for the given
+// window of time after they finish, and kept if any of the rules matches one of
+// their spans, for instance because it has an error (see TailSampleErrors) or
+// took too long (see TailSampleSlowerThan). The decision maker of kept traces
+// is reported as tail sampling. The traces kept by tail sampling count towards
+// the rate limit of the sampling rules (see DD_TRACE_RATE_LIMIT): the traces
+// matching a rule beyond the limit stay dropped.
+//
+// Holding traces delays their submission by up to window, rounded up to the
+// flush interval, and uses memory proportional to the number of dropped spans.
+// Only the spans of a trace local to this process are considered.
+func WithTailSampling(window time.Duration, rules ...TailSamplingRule) StartOption {
+	return func(c *config) {
+		c.tailSamplingWindow = window
+		c.tailSamplingRules = rules
+	}
+}
+
+// WithTagScrubber sets the rules scrubbing the tags of every span before it is
+// sent, to keep sensitive data such as headers or user information from leaving
+// the application. For each tag, the first matching rule is applied. The rules
+// also apply to the attributes of the span links and span events, and to the
+// keys of the structured tags set by the tracer's products, which are only
+// dropped. It overrides the rules set in the DD_TRACE_TAG_SCRUBBING_RULES
+// environment variable. Invalid rules are ignored, and reported in a warning
+// when the tracer starts.
+func WithTagScrubber(rules ...TagScrubRule) StartOption {
+	return func(c *config) {
+		c.tagScrubRules = rules
+	}
+}
+
+// WithTagScrubbingHashKey sets the key of the HMAC replacing the values hashed
+// by the TagScrubHash rules, so that low-entropy values like user IDs can't be
+// recovered by hashing the possible values. Sharing the key between services
+// keeps the hashes of a value identical across them. It overrides the key set
+// in the DD_TRACE_TAG_SCRUBBING_HASH_KEY environment variable. Without a key,
+// a random one is generated when the tracer starts, and the hashes of a value
+// change with each run of the program.
+func WithTagScrubbingHashKey(key []byte) StartOption {
+	return func(c *config) {
+		c.tagScrubHashKey = key
+	}
+}
+
+// WithSpanProcessors registers span processors, which are called in the given
+// order when spans start and when traces finish, before the trace metrics are
+// computed and the spans are sent. See SpanProcessor.
+func WithSpanProcessors(processors ...SpanProcessor) StartOption {
+	return func(c *config) {
+		c.spanProcessors = append(c.spanProcessors, processors...)
+	}
+}
+
 // WithRetryInterval sets the interval, in seconds, for retrying submitting payloads to the agent.
 func WithRetryInterval(interval int) StartOption {
 	return func(c *config) {
@@ -1174,6 +1315,23 @@ func WithRuntimeMetrics() StartOption {
 	}
 }
 
+// WithRuntimeMetricsHistograms enables automatic collection of runtime metrics
+// every 10 seconds, like WithRuntimeMetrics, along with detailed metrics read
+// from the runtime/metrics package: the goroutine counts by state and, when
+// runtime metrics v2 are disabled with DD_RUNTIME_METRICS_V2_ENABLED=false, the
+// GC pauses and scheduler latencies as distributions, the allocations by size
+// class and the time spent waiting on mutexes. Runtime metrics v2, enabled by
+// default, report the latter already. The metrics which are not supported by
+// the Go version in use are not reported.
+func WithRuntimeMetricsHistograms() StartOption {
+	return func(cfg *config) {
+		telemetry.RegisterAppConfig("runtime_metrics_enabled", true, telemetry.OriginCode)
+		telemetry.RegisterAppConfig("runtime_metrics_histograms_enabled", true, telemetry.OriginCode)
+		cfg.runtimeMetrics = true
+		cfg.runtimeMetricsHistograms = true
+	}
+}
+
 // WithDogstatsdAddr specifies the address to connect to for sending metrics to the Datadog
 // Agent. It should be a "host:port" string, or the path to a unix domain socket.If not set, it
 // attempts to determine the address of the statsd service according to the following rules:
@@ -86,6 +86,8 @@ var propagationMapping = map[string]string{
 	"b3":           "b3 single header",
 	"b3multi":      "b3multi",
 	"datadog":      "datadog",
+	"xray":         "xray",
+	"jaeger":       "jaeger",
 	"none":         "none",
 }
 
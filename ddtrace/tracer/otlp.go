@@ -0,0 +1,339 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/version"
+)
+
+const (
+	// otlpTracesPath is the default URL path of the OTLP/HTTP traces endpoint.
+	otlpTracesPath = "/v1/traces"
+
+	// otlpScopeName is the instrumentation scope name set on all exported spans.
+	otlpScopeName = "github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// otlpExporter sends OTLP trace export requests to an OTLP endpoint.
+type otlpExporter interface {
+	// export sends req to the endpoint.
+	export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error)
+	// endpoint returns the URL to which the exporter sends traces.
+	endpoint() string
+	// close releases any resources held by the exporter.
+	close()
+}
+
+// newOTLPExporter returns an exporter for the given endpoint. The URL scheme
+// selects the protocol: "http" and "https" use OTLP/HTTP with protobuf encoding,
+// "grpc" uses OTLP/gRPC over plaintext and "grpcs" uses OTLP/gRPC over TLS.
+func newOTLPExporter(endpoint *url.URL, timeout time.Duration) (otlpExporter, error) {
+	switch endpoint.Scheme {
+	case "http", "https":
+		u := *endpoint
+		if u.Path == "" || u.Path == "/" {
+			u.Path = otlpTracesPath
+		}
+		return &otlpHTTPExporter{
+			url:    u.String(),
+			client: defaultHTTPClient(timeout, false),
+			headers: map[string]string{
+				"Content-Type": "application/x-protobuf",
+				"User-Agent":   "dd-trace-go/" + version.Tag,
+			},
+		}, nil
+	case "grpc", "grpcs":
+		creds := insecure.NewCredentials()
+		if endpoint.Scheme == "grpcs" {
+			creds = credentials.NewClientTLSFromCert(nil, "")
+		}
+		conn, err := grpc.NewClient(endpoint.Host,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithUserAgent("dd-trace-go/"+version.Tag))
+		if err != nil {
+			return nil, err
+		}
+		return &otlpGRPCExporter{
+			target: endpoint.Host,
+			conn:   conn,
+			client: ptraceotlp.NewGRPCClient(conn),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTLP endpoint scheme %q, must be one of: http, https, grpc, grpcs", endpoint.Scheme)
+	}
+}
+
+// otlpHTTPExporter sends traces using OTLP/HTTP with binary protobuf encoding.
+type otlpHTTPExporter struct {
+	url     string            // the delivery URL for traces
+	client  *http.Client      // the HTTP client used in the POST
+	headers map[string]string // the request headers
+}
+
+func (e *otlpHTTPExporter) export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	resp := ptraceotlp.NewExportResponse()
+	body, err := req.MarshalProto()
+	if err != nil {
+		return resp, fmt.Errorf("cannot encode OTLP request: %s", err.Error())
+	}
+	hreq, err := http.NewRequestWithContext(ctx, "POST", e.url, bytes.NewReader(body))
+	if err != nil {
+		return resp, fmt.Errorf("cannot create http request: %s", err.Error())
+	}
+	for header, value := range e.headers {
+		hreq.Header.Set(header, value)
+	}
+	hresp, err := e.client.Do(hreq)
+	if err != nil {
+		return resp, err
+	}
+	defer hresp.Body.Close()
+	if code := hresp.StatusCode; code >= 400 {
+		msg := make([]byte, 1000)
+		n, _ := hresp.Body.Read(msg)
+		txt := http.StatusText(code)
+		if n > 0 {
+			return resp, fmt.Errorf("%s (Status: %s)", msg[:n], txt)
+		}
+		return resp, fmt.Errorf("%s", txt)
+	}
+	b, err := io.ReadAll(hresp.Body)
+	if err != nil || len(b) == 0 {
+		// the data was accepted, the response body is informational only
+		return resp, nil
+	}
+	if strings.HasPrefix(hresp.Header.Get("Content-Type"), "application/json") {
+		err = resp.UnmarshalJSON(b)
+	} else {
+		err = resp.UnmarshalProto(b)
+	}
+	if err != nil {
+		log.Debug("Unable to decode OTLP response: %s", err.Error())
+	}
+	return resp, nil
+}
+
+func (e *otlpHTTPExporter) endpoint() string {
+	return e.url
+}
+
+func (e *otlpHTTPExporter) close() {
+	e.client.CloseIdleConnections()
+}
+
+// otlpGRPCExporter sends traces using OTLP/gRPC.
+type otlpGRPCExporter struct {
+	target string
+	conn   *grpc.ClientConn
+	client ptraceotlp.GRPCClient
+}
+
+func (e *otlpGRPCExporter) export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	return e.client.Export(ctx, req)
+}
+
+func (e *otlpGRPCExporter) endpoint() string {
+	return e.target
+}
+
+func (e *otlpGRPCExporter) close() {
+	e.conn.Close()
+}
+
+// otlpResourceAttributes sets the resource attributes describing the given
+// service on attrs.
+func otlpResourceAttributes(c *config, service string, attrs pcommon.Map) {
+	attrs.PutStr("service.name", service)
+	if c.env != "" {
+		attrs.PutStr("deployment.environment", c.env)
+	}
+	if c.version != "" && (c.universalVersion || service == c.serviceName) {
+		attrs.PutStr("service.version", c.version)
+	}
+	if c.hostname != "" {
+		attrs.PutStr("host.name", c.hostname)
+	}
+	attrs.PutStr("telemetry.sdk.name", "datadog")
+	attrs.PutStr("telemetry.sdk.language", "go")
+	attrs.PutStr("telemetry.sdk.version", version.Tag)
+	attrs.PutStr("process.runtime.version", runtime.Version())
+}
+
+// otlpSpanKind maps the span.kind tag to its OTLP equivalent.
+func otlpSpanKind(kind string) ptrace.SpanKind {
+	switch kind {
+	case ext.SpanKindServer:
+		return ptrace.SpanKindServer
+	case ext.SpanKindClient:
+		return ptrace.SpanKindClient
+	case ext.SpanKindProducer:
+		return ptrace.SpanKindProducer
+	case ext.SpanKindConsumer:
+		return ptrace.SpanKindConsumer
+	case ext.SpanKindInternal:
+		return ptrace.SpanKindInternal
+	default:
+		return ptrace.SpanKindUnspecified
+	}
+}
+
+// otlpSpanID encodes id as an OTLP span ID.
+func otlpSpanID(id uint64) pcommon.SpanID {
+	var sid pcommon.SpanID
+	binary.BigEndian.PutUint64(sid[:], id)
+	return sid
+}
+
+// otlpTraceID encodes the upper and lower 64 bits of a trace ID as an OTLP trace ID.
+func otlpTraceID(upper, lower uint64) pcommon.TraceID {
+	var tid pcommon.TraceID
+	binary.BigEndian.PutUint64(tid[:8], upper)
+	binary.BigEndian.PutUint64(tid[8:], lower)
+	return tid
+}
+
+// otlpSkipMeta holds the span meta keys which are not exported as attributes
+// because they are represented natively in the OTLP span.
+var otlpSkipMeta = map[string]struct{}{
+	ext.SpanKind:     {},
+	"_dd.span_links": {},
+	"events":         {},
+}
+
+// convertSpanToOTLP fills dst with the contents of s. Finished spans are not
+// modified anymore, so s does not need to be locked.
+func convertSpanToOTLP(s *Span, dst ptrace.Span) {
+	var upper uint64
+	if s.context != nil {
+		upper = s.context.traceID.Upper()
+	}
+	dst.SetTraceID(otlpTraceID(upper, s.traceID))
+	dst.SetSpanID(otlpSpanID(s.spanID))
+	if s.parentID != 0 {
+		dst.SetParentSpanID(otlpSpanID(s.parentID))
+	}
+	dst.SetName(s.name)
+	dst.SetKind(otlpSpanKind(s.meta[ext.SpanKind]))
+	dst.SetStartTimestamp(pcommon.Timestamp(s.start))
+	dst.SetEndTimestamp(pcommon.Timestamp(s.start + s.duration))
+	if p, ok := s.metrics[keySamplingPriority]; ok && p > 0 {
+		dst.SetFlags(0x01) // W3C sampled flag
+	}
+	if s.error != 0 {
+		dst.Status().SetCode(ptrace.StatusCodeError)
+		dst.Status().SetMessage(s.meta[ext.ErrorMsg])
+	}
+
+	attrs := dst.Attributes()
+	attrs.EnsureCapacity(len(s.meta) + len(s.metrics) + len(s.metaStruct) + 3)
+	attrs.PutStr("operation.name", s.name)
+	attrs.PutStr("resource.name", s.resource)
+	if s.spanType != "" {
+		attrs.PutStr("span.type", s.spanType)
+	}
+	for k, v := range s.meta {
+		if _, ok := otlpSkipMeta[k]; ok {
+			continue
+		}
+		attrs.PutStr(k, v)
+	}
+	for k, v := range s.metrics {
+		attrs.PutDouble(k, v)
+	}
+	for k, v := range s.metaStruct {
+		b, err := json.Marshal(v)
+		if err != nil {
+			log.Error("Error marshaling value %q: %v", v, err.Error())
+			continue
+		}
+		attrs.PutStr(k, string(b))
+	}
+
+	if len(s.spanLinks) > 0 {
+		links := dst.Links()
+		links.EnsureCapacity(len(s.spanLinks))
+		for _, l := range s.spanLinks {
+			ol := links.AppendEmpty()
+			ol.SetTraceID(otlpTraceID(l.TraceIDHigh, l.TraceID))
+			ol.SetSpanID(otlpSpanID(l.SpanID))
+			ol.SetFlags(l.Flags)
+			ol.TraceState().FromRaw(l.Tracestate)
+			for k, v := range l.Attributes {
+				ol.Attributes().PutStr(k, v)
+			}
+		}
+	}
+
+	if len(s.spanEvents) > 0 {
+		events := dst.Events()
+		events.EnsureCapacity(len(s.spanEvents))
+		for _, e := range s.spanEvents {
+			oe := events.AppendEmpty()
+			oe.SetName(e.Name)
+			oe.SetTimestamp(pcommon.Timestamp(e.TimeUnixNano))
+			for k, v := range e.Attributes {
+				if v == nil {
+					continue
+				}
+				putSpanEventAttribute(oe.Attributes().PutEmpty(k), v)
+			}
+		}
+	}
+}
+
+// putSpanEventAttribute stores the span event attribute a into dst.
+func putSpanEventAttribute(dst pcommon.Value, a *spanEventAttribute) {
+	switch a.Type {
+	case spanEventAttributeTypeString:
+		dst.SetStr(a.StringValue)
+	case spanEventAttributeTypeBool:
+		dst.SetBool(a.BoolValue)
+	case spanEventAttributeTypeInt:
+		dst.SetInt(a.IntValue)
+	case spanEventAttributeTypeDouble:
+		dst.SetDouble(a.DoubleValue)
+	case spanEventAttributeTypeArray:
+		s := dst.SetEmptySlice()
+		if a.ArrayValue == nil {
+			return
+		}
+		s.EnsureCapacity(len(a.ArrayValue.Values))
+		for _, v := range a.ArrayValue.Values {
+			e := s.AppendEmpty()
+			switch v.Type {
+			case spanEventArrayAttributeValueTypeString:
+				e.SetStr(v.StringValue)
+			case spanEventArrayAttributeValueTypeBool:
+				e.SetBool(v.BoolValue)
+			case spanEventArrayAttributeValueTypeInt:
+				e.SetInt(v.IntValue)
+			case spanEventArrayAttributeValueTypeDouble:
+				e.SetDouble(v.DoubleValue)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/version"
+)
+
+// otlpBatchSpanLimit specifies the maximum number of spans buffered by the
+// otlpTraceWriter before a flush is triggered.
+const otlpBatchSpanLimit = 1000
+
+// otlpTraceWriter converts finished traces to the OpenTelemetry Protocol (OTLP)
+// and sends them to an OTLP endpoint, such as an OpenTelemetry Collector,
+// instead of the Datadog Agent.
+type otlpTraceWriter struct {
+	// config holds the tracer configuration
+	config *config
+
+	// exporter sends the OTLP requests to the endpoint
+	exporter otlpExporter
+
+	// traces holds the buffered spans, grouped by service
+	traces ptrace.Traces
+
+	// scopes indexes the spans of traces by service name
+	scopes map[string]ptrace.SpanSlice
+
+	// traceCount and spanCount hold the number of traces and spans buffered in traces
+	traceCount, spanCount int
+
+	// climit limits the number of concurrent outgoing connections
+	climit chan struct{}
+
+	// wg waits for all uploads to finish
+	wg sync.WaitGroup
+
+	// statsd is used to send metrics
+	statsd globalinternal.StatsdClient
+}
+
+func newOTLPTraceWriter(c *config, exporter otlpExporter, statsdClient globalinternal.StatsdClient) *otlpTraceWriter {
+	w := &otlpTraceWriter{
+		config:   c,
+		exporter: exporter,
+		climit:   make(chan struct{}, concurrentConnectionLimit),
+		statsd:   statsdClient,
+	}
+	w.reset()
+	return w
+}
+
+func (h *otlpTraceWriter) reset() {
+	h.traces = ptrace.NewTraces()
+	h.scopes = make(map[string]ptrace.SpanSlice)
+	h.traceCount = 0
+	h.spanCount = 0
+}
+
+// spanSlice returns the span slice holding the spans of the given service.
+func (h *otlpTraceWriter) spanSlice(service string) ptrace.SpanSlice {
+	if ss, ok := h.scopes[service]; ok {
+		return ss
+	}
+	rs := h.traces.ResourceSpans().AppendEmpty()
+	otlpResourceAttributes(h.config, service, rs.Resource().Attributes())
+	scope := rs.ScopeSpans().AppendEmpty()
+	scope.Scope().SetName(otlpScopeName)
+	scope.Scope().SetVersion(version.Tag)
+	ss := scope.Spans()
+	h.scopes[service] = ss
+	return ss
+}
+
+func (h *otlpTraceWriter) add(trace []*Span) {
+	if len(trace) == 0 {
+		return
+	}
+	// There is no Datadog Agent to apply the sampling decision, so rejected
+	// traces are dropped here.
+	if p, ok := trace[0].context.SamplingPriority(); ok && p <= 0 {
+		h.statsd.Incr("datadog.tracer.traces_dropped", []string{"reason:sampling_decision"}, 1)
+		return
+	}
+	for _, s := range trace {
+		convertSpanToOTLP(s, h.spanSlice(s.service).AppendEmpty())
+	}
+	h.traceCount++
+	h.spanCount += len(trace)
+	if h.spanCount >= otlpBatchSpanLimit {
+		h.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:size"}, 1)
+		h.flush()
+	}
+}
+
+func (h *otlpTraceWriter) stop() {
+	h.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:shutdown"}, 1)
+	h.flush()
+	h.wg.Wait()
+	h.exporter.close()
+}
+
+// flush will push any currently buffered traces to the OTLP endpoint.
+func (h *otlpTraceWriter) flush() {
+	if h.spanCount == 0 {
+		return
+	}
+	h.wg.Add(1)
+	h.climit <- struct{}{}
+	req := ptraceotlp.NewExportRequestFromTraces(h.traces)
+	count, spans := h.traceCount, h.spanCount
+	h.reset()
+	go func() {
+		defer func(start time.Time) {
+			<-h.climit
+			h.statsd.Timing("datadog.tracer.flush_duration", time.Since(start), nil, 1)
+			h.wg.Done()
+		}(time.Now())
+
+		var err error
+		for attempt := 0; attempt <= h.config.sendRetries; attempt++ {
+			log.Debug("Attempt to send OTLP payload: spans: %d traces: %d\n", spans, count)
+			var resp ptraceotlp.ExportResponse
+			ctx, cancel := context.WithTimeout(context.Background(), h.config.httpClientTimeout)
+			resp, err = h.exporter.export(ctx, req)
+			cancel()
+			if err == nil {
+				log.Debug("sent traces to %s after %d attempts", h.exporter.endpoint(), attempt+1)
+				h.statsd.Count("datadog.tracer.flush_traces", int64(count), nil, 1)
+				if ps := resp.PartialSuccess(); ps.RejectedSpans() > 0 {
+					h.statsd.Count("datadog.tracer.otlp.rejected_spans", ps.RejectedSpans(), nil, 1)
+					log.Warn("OTLP endpoint rejected %d spans: %s", ps.RejectedSpans(), ps.ErrorMessage())
+				}
+				return
+			}
+			time.Sleep(h.config.retryInterval)
+		}
+		h.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:send_failed"}, 1)
+		log.Error("lost %d traces: %v", count, err.Error())
+	}()
+}
@@ -0,0 +1,264 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/otlptest"
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+func TestOTLPImplementsTraceWriter(t *testing.T) {
+	assert.Implements(t, (*traceWriter)(nil), &otlpTraceWriter{})
+}
+
+func TestConvertSpanToOTLP(t *testing.T) {
+	assert := assert.New(t)
+	s := newSpan("http.request", "web", "GET /users", 2, 1, 3)
+	s.context.traceID.SetUpper(0x0102030405060708)
+	s.spanType = ext.SpanTypeWeb
+	s.duration = int64(time.Second)
+	s.error = 1
+	s.meta[ext.SpanKind] = ext.SpanKindServer
+	s.meta[ext.ErrorMsg] = "boom"
+	s.meta["http.method"] = "GET"
+	s.metrics[keySamplingPriority] = ext.PriorityAutoKeep
+	s.metrics["http.status_code"] = 500
+	s.spanLinks = []SpanLink{{
+		TraceID:     10,
+		TraceIDHigh: 11,
+		SpanID:      12,
+		Attributes:  map[string]string{"link.name": "producer"},
+		Tracestate:  "dd=s:1",
+		Flags:       1,
+	}}
+	s.spanEvents = []spanEvent{{
+		Name:         "exception",
+		TimeUnixNano: 42,
+		Attributes: toSpanEventAttributeMsg(map[string]any{
+			"message": "oops",
+			"count":   2,
+			"values":  []float64{1.5, 2.5},
+		}),
+	}}
+
+	dst := ptrace.NewSpan()
+	convertSpanToOTLP(s, dst)
+
+	assert.Equal("0102030405060708"+"0000000000000001", dst.TraceID().String())
+	assert.Equal("0000000000000002", dst.SpanID().String())
+	assert.Equal("0000000000000003", dst.ParentSpanID().String())
+	assert.Equal("http.request", dst.Name())
+	assert.Equal(ptrace.SpanKindServer, dst.Kind())
+	assert.Equal(pcommon.Timestamp(s.start), dst.StartTimestamp())
+	assert.Equal(pcommon.Timestamp(s.start+int64(time.Second)), dst.EndTimestamp())
+	assert.Equal(uint32(1), dst.Flags())
+	assert.Equal(ptrace.StatusCodeError, dst.Status().Code())
+	assert.Equal("boom", dst.Status().Message())
+
+	attrs := dst.Attributes().AsRaw()
+	assert.Equal("GET /users", attrs["resource.name"])
+	assert.Equal("http.request", attrs["operation.name"])
+	assert.Equal(ext.SpanTypeWeb, attrs["span.type"])
+	assert.Equal("GET", attrs["http.method"])
+	assert.Equal(500.0, attrs["http.status_code"])
+	assert.NotContains(attrs, ext.SpanKind)
+
+	require.Equal(t, 1, dst.Links().Len())
+	l := dst.Links().At(0)
+	assert.Equal("000000000000000b"+"000000000000000a", l.TraceID().String())
+	assert.Equal("000000000000000c", l.SpanID().String())
+	assert.Equal("dd=s:1", l.TraceState().AsRaw())
+	assert.Equal(uint32(1), l.Flags())
+	assert.Equal(map[string]any{"link.name": "producer"}, l.Attributes().AsRaw())
+
+	require.Equal(t, 1, dst.Events().Len())
+	e := dst.Events().At(0)
+	assert.Equal("exception", e.Name())
+	assert.Equal(pcommon.Timestamp(42), e.Timestamp())
+	assert.Equal(map[string]any{
+		"message": "oops",
+		"count":   int64(2),
+		"values":  []any{1.5, 2.5},
+	}, e.Attributes().AsRaw())
+}
+
+func TestOTLPTraceWriter(t *testing.T) {
+	for name, endpoint := range map[string]func(*otlptest.Receiver) string{
+		"http": (*otlptest.Receiver).HTTPEndpoint,
+		"grpc": (*otlptest.Receiver).GRPCEndpoint,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			recv := otlptest.NewReceiver(t)
+			tick := make(chan time.Time)
+			trc, err := newTracer(
+				WithOTLPExporter(endpoint(recv)),
+				WithService("otlp-service"),
+				WithEnv("test"),
+				WithServiceVersion("1.2.3"),
+				withTickChan(tick),
+				withNoopStats(),
+			)
+			require.NoError(t, err)
+			setGlobalTracer(trc)
+			defer func() {
+				setGlobalTracer(&NoopTracer{})
+				trc.Stop()
+				globalconfig.SetServiceName("")
+			}()
+			assert.IsType(&otlpTraceWriter{}, trc.traceWriter)
+
+			root := trc.StartSpan("parent", ResourceName("/home"))
+			child := trc.StartSpan("child", ChildOf(root.Context()), ServiceName("db"))
+			child.AddLink(SpanLink{TraceID: 1, SpanID: 2})
+			child.AddEvent("retry", WithSpanEventAttributes(map[string]any{"attempt": 1}))
+			child.Finish()
+			root.Finish()
+
+			// the finished trace may reach the worker after a flush is
+			// triggered, so keep flushing until it gets exported.
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				for {
+					select {
+					case tick <- time.Now():
+					case <-done:
+						return
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+			spans := recv.WaitForSpans(t, 2, 5*time.Second)
+			require.Len(t, spans, 2)
+			byName := map[string]ptrace.Span{}
+			for _, s := range spans {
+				byName[s.Name()] = s
+			}
+			parent, c := byName["parent"], byName["child"]
+			assert.Equal(parent.TraceID(), c.TraceID())
+			assert.Equal(parent.SpanID(), c.ParentSpanID())
+			assert.Equal(root.Context().TraceIDBytes(), [16]byte(parent.TraceID()))
+			assert.Equal(1, c.Links().Len())
+			assert.Equal(1, c.Events().Len())
+			assert.Equal(map[string]any{"attempt": int64(1)}, c.Events().At(0).Attributes().AsRaw())
+
+			services := map[string]map[string]any{}
+			for _, td := range recv.Traces() {
+				for i := 0; i < td.ResourceSpans().Len(); i++ {
+					attrs := td.ResourceSpans().At(i).Resource().Attributes().AsRaw()
+					services[attrs["service.name"].(string)] = attrs
+				}
+			}
+			require.Contains(t, services, "otlp-service")
+			require.Contains(t, services, "db")
+			assert.Equal("test", services["otlp-service"]["deployment.environment"])
+			assert.Equal("1.2.3", services["otlp-service"]["service.version"])
+			assert.NotContains(services["db"], "service.version")
+			assert.Equal("go", services["db"]["telemetry.sdk.language"])
+		})
+	}
+}
+
+func TestOTLPTraceWriterDropsRejectedTraces(t *testing.T) {
+	recv := otlptest.NewReceiver(t)
+	cfg, err := newTestConfig(WithOTLPExporter(recv.HTTPEndpoint()))
+	require.NoError(t, err)
+	exporter, err := newOTLPExporter(cfg.otlpEndpoint, time.Second)
+	require.NoError(t, err)
+	var tg statsdtest.TestStatsdClient
+	h := newOTLPTraceWriter(cfg, exporter, &tg)
+
+	rejected := newBasicSpan("rejected")
+	rejected.context.setSamplingPriority(ext.PriorityAutoReject, 0)
+	kept := newBasicSpan("kept")
+	kept.context.setSamplingPriority(ext.PriorityAutoKeep, 0)
+	h.add([]*Span{rejected})
+	h.add([]*Span{kept})
+	h.stop()
+
+	spans := recv.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "kept", spans[0].Name())
+	assert.Contains(t, tg.CallNames(), "datadog.tracer.traces_dropped")
+}
+
+func TestOTLPTraceWriterSendFailure(t *testing.T) {
+	recv := otlptest.NewReceiver(t)
+	recv.SetStatusCode(http.StatusServiceUnavailable)
+	cfg, err := newTestConfig(WithOTLPExporter(recv.HTTPEndpoint()), WithSendRetries(2))
+	require.NoError(t, err)
+	exporter, err := newOTLPExporter(cfg.otlpEndpoint, time.Second)
+	require.NoError(t, err)
+	var tg statsdtest.TestStatsdClient
+	h := newOTLPTraceWriter(cfg, &countingOTLPExporter{otlpExporter: exporter}, &tg)
+
+	h.add([]*Span{newBasicSpan("lost")})
+	h.stop()
+
+	assert.Equal(t, 3, h.exporter.(*countingOTLPExporter).calls)
+	assert.Empty(t, recv.Spans())
+	counts := tg.Counts()
+	assert.Equal(t, int64(1), counts["datadog.tracer.traces_dropped"])
+}
+
+func TestWithOTLPExporter(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint string
+		valid    bool
+	}{
+		{"http://localhost:4318", true},
+		{"https://collector.example.com/custom/traces", true},
+		{"grpc://localhost:4317", true},
+		{"grpcs://collector.example.com:4317", true},
+		{"ftp://localhost:4317", false},
+		{"localhost:4317", false},
+	} {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			c, err := newTestConfig(WithOTLPExporter(tc.endpoint))
+			require.NoError(t, err)
+			if !tc.valid {
+				assert.Nil(t, c.otlpEndpoint)
+				return
+			}
+			require.NotNil(t, c.otlpEndpoint)
+			assert.Equal(t, tc.endpoint, c.otlpEndpoint.String())
+		})
+	}
+
+	t.Run("default-path", func(t *testing.T) {
+		c, err := newTestConfig(WithOTLPExporter("http://localhost:4318"))
+		require.NoError(t, err)
+		e, err := newOTLPExporter(c.otlpEndpoint, time.Second)
+		require.NoError(t, err)
+		defer e.close()
+		assert.Equal(t, "http://localhost:4318/v1/traces", e.endpoint())
+	})
+}
+
+// countingOTLPExporter counts the number of export attempts.
+type countingOTLPExporter struct {
+	otlpExporter
+	calls int
+}
+
+func (e *countingOTLPExporter) export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	e.calls++
+	return e.otlpExporter.export(ctx, req)
+}
@@ -108,6 +108,37 @@ func (p *payload) clear() {
 	p.reader = nil
 }
 
+// split splits the payload into two payloads holding the first and the second
+// half of its items. It returns false if the payload holds less than two
+// items.
+func (p *payload) split() (*payload, *payload, bool) {
+	n := p.itemCount()
+	if n < 2 {
+		return nil, nil, false
+	}
+	// The items are encoded one after the other: skip the first half of
+	// them to find where the second half starts.
+	b := p.buf.Bytes()
+	rest := b
+	for range n / 2 {
+		var err error
+		if rest, err = msgp.Skip(rest); err != nil {
+			return nil, nil, false
+		}
+	}
+	mid := len(b) - len(rest)
+	return newPayloadOf(b[:mid], n/2), newPayloadOf(b[mid:], n-n/2), true
+}
+
+// newPayloadOf returns a payload of the count msgpack-encoded items of b.
+func newPayloadOf(b []byte, count int) *payload {
+	p := newPayload()
+	p.buf.Write(b)
+	p.count = uint32(count)
+	p.updateHeader()
+	return p
+}
+
 // https://github.com/msgpack/msgpack/blob/master/spec.md#array-format-family
 const (
 	msgpackArrayFix byte = 144  // up to 15 items
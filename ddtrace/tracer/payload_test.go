@@ -74,6 +74,42 @@ func TestPayloadDecode(t *testing.T) {
 	}
 }
 
+// TestPayloadSplit ensures that the halves of a split payload hold its items.
+func TestPayloadSplit(t *testing.T) {
+	for _, n := range []int{2, 17, 1 << 10} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			assert := assert.New(t)
+			p := newPayload()
+			lists := make(spanLists, n)
+			for i := 0; i < n; i++ {
+				lists[i] = newSpanList(i%5 + 1)
+				p.push(lists[i])
+			}
+			first, second, ok := p.split()
+			assert.True(ok)
+			assert.Equal(n/2, first.itemCount())
+			assert.Equal(n-n/2, second.itemCount())
+			for i, half := range []*payload{first, second} {
+				var got spanLists
+				assert.NoError(msgp.Decode(half, &got))
+				want := lists[:n/2]
+				if i == 1 {
+					want = lists[n/2:]
+				}
+				assert.Len(got, len(want))
+				for j := range got {
+					assert.Equal(len(want[j]), len(got[j]))
+				}
+			}
+		})
+	}
+
+	p := newPayload()
+	p.push(newSpanList(1))
+	_, _, ok := p.split()
+	assert.False(t, ok)
+}
+
 func BenchmarkPayloadThroughput(b *testing.B) {
 	b.Run("10K", benchmarkPayloadThroughput(1))
 	b.Run("100K", benchmarkPayloadThroughput(10))
@@ -135,6 +135,7 @@ type Span struct {
 	goExecTraced   bool         `msg:"-"`
 	noDebugStack   bool         `msg:"-"` // disables debug stack traces
 	finished       bool         `msg:"-"` // true if the span has been submitted to a tracer. Can only be read/modified if the trace is locked.
+	processing     bool         `msg:"-"` // true if the finished span is being modified by span processors.
 	context        *SpanContext `msg:"-"` // span propagation context
 	integration    string       `msg:"-"` // where the span was started from, such as a specific contrib or "manual"
 	supportsEvents bool         `msg:"-"` // whether the span supports native span events or not
@@ -188,7 +189,7 @@ func (s *Span) SetTag(key string, value interface{}) {
 	// We don't lock spans when flushing, so we could have a data race when
 	// modifying a span as it's being flushed. This protects us against that
 	// race, since spans are marked `finished` before we flush them.
-	if s.finished {
+	if s.finished && !s.processing {
 		return
 	}
 	switch key {
@@ -417,7 +418,7 @@ func (s *Span) setTagError(value interface{}, cfg errorConfig) {
 	// We don't lock spans when flushing, so we could have a data race when
 	// modifying a span as it's being flushed. This protects us against that
 	// race, since spans are marked `finished` before we flush them.
-	if s.finished {
+	if s.finished && !s.processing {
 		return
 	}
 	switch v := value.(type) {
@@ -700,7 +701,7 @@ func (s *Span) SetOperationName(operationName string) {
 	// We don't lock spans when flushing, so we could have a data race when
 	// modifying a span as it's being flushed. This protects us against that
 	// race, since spans are marked `finished` before we flush them.
-	if s.finished {
+	if s.finished && !s.processing {
 		// already finished
 		return
 	}
@@ -731,6 +732,16 @@ func (s *Span) finish(finishTime int64) {
 	if s.taskEnd != nil {
 		s.taskEnd()
 	}
+	if threshold, report := traceprof.SlowSpanHandler(); report != nil && time.Duration(s.duration) > threshold {
+		// the profiler captures the execution trace of the slow spans
+		report(traceprof.SlowSpan{
+			Operation: s.name,
+			Resource:  s.resource,
+			TraceID:   s.context.TraceID(),
+			SpanID:    s.spanID,
+			Duration:  time.Duration(s.duration),
+		})
+	}
 
 	keep := true
 	tracer, hasTracer := getGlobalTracer().(*tracer)
@@ -759,8 +770,9 @@ func (s *Span) finish(finishTime int64) {
 	}
 	s.context.finish()
 
-	// compute stats after finishing the span. This ensures any normalization or tag propagation has been applied
-	if hasTracer {
+	// compute stats after finishing the span. This ensures any normalization or tag propagation has been applied.
+	// With span processors, stats are computed once the processors ran on the finished trace.
+	if hasTracer && len(tracer.config.spanProcessors) == 0 {
 		tracer.submit(s)
 	}
 
@@ -798,6 +810,7 @@ func obfuscatedResource(o *obfuscate.Obfuscator, typ, resource string) string {
 
 // shouldKeep reports whether the trace should be kept.
 // a single span being kept implies the whole trace being kept.
+// It's called as the span finishes, before the span processors run.
 func shouldKeep(s *Span) bool {
 	if p, ok := s.context.SamplingPriority(); ok && p > 0 {
 		// positive sampling priorities stay
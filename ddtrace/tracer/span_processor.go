@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+// SpanProcessor is notified when spans start and when traces finish, and can
+// modify or filter the finished spans before they are sent. It is registered
+// with WithSpanProcessors, for instance to drop health check spans, add tags
+// to all spans, or rename resources in a single place.
+type SpanProcessor interface {
+	// OnStart is called when a span starts, synchronously, before the span is
+	// returned to the caller.
+	OnStart(s *Span)
+
+	// OnFinish is called with the finished spans of a trace, or of a portion
+	// of a trace when it is partially flushed, and returns the spans to send.
+	// Spans can be modified through their methods, such as SetTag or
+	// SetOperationName, until OnFinish returns. The spans which are not
+	// returned are dropped, and not accounted for in the trace metrics
+	// computed by the tracer.
+	//
+	// Whether a trace is dropped by the tracer when the agent drops the
+	// unsampled traces is decided as its spans finish, before OnFinish is
+	// called: the spans it modifies can't change that decision.
+	//
+	// It is called from a single goroutine, which must not be blocked.
+	OnFinish(trace []*Span) []*Span
+}
+
+// processChunk runs the span processors on the spans of c, then submits the
+// resulting spans to the stats concentrator, so that the computed stats match
+// the spans which are sent.
+func (t *tracer) processChunk(c *chunk) {
+	if len(t.config.spanProcessors) == 0 || len(c.spans) == 0 {
+		return
+	}
+	spans := c.spans
+	first := spans[0]
+	setProcessing(spans, true)
+	for _, p := range t.config.spanProcessors {
+		spans = p.OnFinish(spans)
+		if len(spans) == 0 {
+			break
+		}
+	}
+	setProcessing(c.spans, false)
+	if len(spans) > 0 && spans[0] != first {
+		// the first span of a chunk holds the trace level tags
+		copyTraceTags(first, spans[0])
+	}
+	c.spans = spans
+	for _, s := range spans {
+		t.submit(s)
+	}
+}
+
+// setProcessing allows or forbids modifying the finished spans.
+func setProcessing(spans []*Span, processing bool) {
+	for _, s := range spans {
+		s.mu.Lock()
+		s.processing = processing
+		s.mu.Unlock()
+	}
+}
+
+// copyTraceTags sets the trace level tags and sampling priority held by the
+// span from, which was the first span of a chunk, on the span to.
+func copyTraceTags(from, to *Span) {
+	from.mu.RLock()
+	p, ok := from.metrics[keySamplingPriority]
+	from.mu.RUnlock()
+
+	t := from.context.trace
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	to.mu.Lock()
+	defer to.mu.Unlock()
+	t.setTraceTags(to)
+	if ok {
+		to.setMetric(keySamplingPriority, p)
+	}
+}
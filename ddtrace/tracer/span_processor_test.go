@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// testSpanProcessor tags the started spans with a tenant, drops the health check
+// traces and removes the user IDs from the resources.
+type testSpanProcessor struct{}
+
+var userIDRegexp = regexp.MustCompile(`/users/\d+`)
+
+func (testSpanProcessor) OnStart(s *Span) {
+	s.SetTag("tenant", "acme")
+}
+
+func (testSpanProcessor) OnFinish(trace []*Span) []*Span {
+	kept := trace[:0]
+	for _, s := range trace {
+		if s.resource == "GET /health" {
+			return nil
+		}
+		s.SetTag(ext.ResourceName, userIDRegexp.ReplaceAllString(s.resource, "/users/?"))
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// dropRootProcessor drops the local root spans.
+type dropRootProcessor struct{}
+
+func (dropRootProcessor) OnStart(_ *Span) {}
+
+func (dropRootProcessor) OnFinish(trace []*Span) []*Span {
+	var kept []*Span
+	for _, s := range trace {
+		if s.parentID != 0 {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+func TestSpanProcessors(t *testing.T) {
+	tracer, transport, flush, stop, err := startTestTracer(t, WithSpanProcessors(testSpanProcessor{}))
+	require.NoError(t, err)
+	defer stop()
+
+	c := newConcentrator(tracer.config, (10 * time.Second).Nanoseconds(), &statsd.NoOpClientDirect{})
+	c.Start()
+	tracer.stats.Stop()
+	tracer.stats = c
+
+	tracer.StartSpan("http.request", ResourceName("GET /health")).Finish()
+	root := tracer.StartSpan("http.request", ResourceName("GET /users/42"))
+	child := tracer.StartSpan("db.query", ChildOf(root.Context()), ResourceName("SELECT"))
+	child.Finish()
+	root.Finish()
+	flush(1)
+
+	// the tags can't be modified after the processors ran
+	root.SetTag(ext.ResourceName, "GET /users/42")
+
+	traces := transport.Traces()
+	require.Len(t, traces, 1)
+	require.Len(t, traces[0], 2)
+	assert.Equal(t, "GET /users/?", traces[0][0].resource)
+	assert.Equal(t, "SELECT", traces[0][1].resource)
+	for _, s := range traces[0] {
+		assert.Equal(t, "acme", s.meta["tenant"])
+	}
+
+	// the stats are computed from the processed spans
+	c.Stop()
+	var resources []string
+	for _, p := range transport.Stats() {
+		for _, b := range p.Stats {
+			for _, s := range b.Stats {
+				resources = append(resources, s.Resource)
+			}
+		}
+	}
+	assert.Equal(t, []string{"GET /users/?"}, resources)
+}
+
+func TestSpanProcessorsDropFirstSpan(t *testing.T) {
+	tracer, transport, flush, stop, err := startTestTracer(t, WithSpanProcessors(dropRootProcessor{}))
+	require.NoError(t, err)
+	defer stop()
+
+	root := tracer.StartSpan("root")
+	tracer.StartSpan("child", ChildOf(root.Context())).Finish()
+	root.SetTag(ext.ManualKeep, true)
+	root.Finish()
+	flush(1)
+
+	traces := transport.Traces()
+	require.Len(t, traces, 1)
+	require.Len(t, traces[0], 1)
+	s := traces[0][0]
+	assert.Equal(t, "child", s.name)
+	assert.Equal(t, float64(ext.PriorityUserKeep), s.metrics[keySamplingPriority])
+	assert.Equal(t, "-4", s.meta[keyDecisionMaker])
+}
+
+func TestSpanProcessorsQueueFull(t *testing.T) {
+	tracer, err := newUnstartedTracer(WithSpanProcessors(dropRootProcessor{}), WithStatsComputation(true))
+	require.NoError(t, err)
+	defer tracer.Stop()
+	tracer.config.agent.Stats = true
+	tracer.config.agent.DropP0s = true
+
+	for range payloadQueueSize {
+		tracer.pushChunk(&chunk{})
+	}
+	s := newSpan("name", "service", "resource", 0, 0, 0)
+	s.setMetric(keyTopLevel, 1)
+	tracer.pushChunk(&chunk{spans: []*Span{s}})
+
+	// the processors won't run on the dropped chunk, its stats are computed
+	assert.Equal(t, uint32(1), tracer.totalTracesDropped)
+	assert.Len(t, tracer.stats.In, 1)
+}
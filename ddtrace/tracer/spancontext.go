@@ -103,6 +103,10 @@ type SpanContext struct {
 	reparentID string
 	isRemote   bool
 
+	// baggageTags holds the span tags of the extracted baggage items promoted
+	// to tags of the spans started from this span context.
+	baggageTags map[string]string
+
 	// the below group should propagate cross-process
 
 	traceID traceID
@@ -0,0 +1,381 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+const (
+	// spillFileExt is the extension of the files holding spilled payloads.
+	spillFileExt = ".payload"
+
+	// spillTmpExt is the extension of spill files which are still being written.
+	spillTmpExt = ".tmp"
+
+	// spillPendingLimit is the maximum number of payloads waiting to be
+	// written to disk.
+	spillPendingLimit = 16
+)
+
+// spillReplayInterval is the interval at which the spilled payloads are
+// replayed, in addition to when the agent is reachable again. It is a variable
+// so that it can be shortened in tests.
+var spillReplayInterval = 10 * time.Second
+
+// errSpillDropped is returned by the function replaying a spilled payload
+// which must be dropped rather than replayed again later.
+var errSpillDropped = errors.New("spilled payload dropped")
+
+// spillFile describes a payload stored in the spill directory.
+type spillFile struct {
+	seq   uint64 // sequence number, used to replay payloads in order
+	count int    // number of traces in the payload
+	size  int64  // size of the file in bytes
+}
+
+// name returns the file name of f. The name encodes everything needed to
+// replay the payload, so that the directory can be indexed without reading
+// the files.
+func (f spillFile) name() string {
+	return fmt.Sprintf("%020d-%d%s", f.seq, f.count, spillFileExt)
+}
+
+// parseSpillFileName parses a file name created by spillFile.name.
+func parseSpillFileName(name string) (spillFile, bool) {
+	base, ok := strings.CutSuffix(name, spillFileExt)
+	if !ok {
+		return spillFile{}, false
+	}
+	seq, count, ok := strings.Cut(base, "-")
+	if !ok {
+		return spillFile{}, false
+	}
+	s, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return spillFile{}, false
+	}
+	c, err := strconv.Atoi(count)
+	if err != nil || c < 0 {
+		return spillFile{}, false
+	}
+	return spillFile{seq: s, count: c}, true
+}
+
+// spillQueue is a bounded, disk-backed FIFO of encoded trace payloads. Payloads
+// which could not be sent to the agent are written to it and replayed once the
+// agent is reachable again. When the queue is full, the oldest payloads are
+// evicted to make room for the new ones.
+//
+// The payloads are written and replayed by a goroutine started with start, so
+// that the disk I/O doesn't delay the callers. Payloads are kept across
+// restarts, so the directory must not be shared between processes.
+type spillQueue struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex  // guards below fields
+	files []spillFile // files in the queue, oldest first
+	size  int64       // total size of files in bytes
+	seq   uint64      // sequence number of the last file
+
+	// replaying is set while a replay is in progress.
+	replaying atomic.Bool
+
+	// stopped is set when the queue must not start replaying any more payloads.
+	stopped atomic.Bool
+
+	pending chan spilledPayload // payloads waiting to be written by the goroutine
+	kick    chan struct{}       // wakes the goroutine up to replay the payloads
+	done    chan struct{}       // closed to stop the goroutine
+	wg      sync.WaitGroup      // waits for the goroutine to exit
+
+	// statsd is used to send metrics
+	statsd globalinternal.StatsdClient
+}
+
+// newSpillQueue returns a spill queue storing at most maxBytes of payloads in
+// dir. Payloads left in dir by a previous run are queued for replay.
+func newSpillQueue(dir string, maxBytes int64, statsdClient globalinternal.StatsdClient) (*spillQueue, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("invalid spill size limit %d", maxBytes)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	q := &spillQueue{
+		dir:      dir,
+		maxBytes: maxBytes,
+		statsd:   statsdClient,
+		pending:  make(chan spilledPayload, spillPendingLimit),
+		kick:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(e.Name(), spillTmpExt) {
+			// leftover of an interrupted write
+			os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		f, ok := parseSpillFileName(e.Name())
+		if !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		f.size = info.Size()
+		q.files = append(q.files, f)
+		q.size += f.size
+		q.seq = max(q.seq, f.seq)
+	}
+	sort.Slice(q.files, func(i, j int) bool { return q.files[i].seq < q.files[j].seq })
+	q.mu.Lock()
+	q.evictLocked(0)
+	q.mu.Unlock()
+	if n := len(q.files); n > 0 {
+		log.Info("Found %d trace payloads (%d bytes) to replay in %s", n, q.size, dir)
+	}
+	return q, nil
+}
+
+// len returns the number of payloads in the queue.
+func (q *spillQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.files)
+}
+
+// bytes returns the total size of the payloads in the queue.
+func (q *spillQueue) bytes() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// spilledPayload is an encoded payload waiting to be written to disk.
+type spilledPayload struct {
+	data  []byte
+	count int
+}
+
+// start starts the goroutine writing the enqueued payloads to disk, and
+// replaying the spilled payloads with send: right away for the payloads left
+// by a previous run, then every spillReplayInterval and when kicked.
+func (q *spillQueue) start(send func(p *payload) error) {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		tick := time.NewTicker(spillReplayInterval)
+		defer tick.Stop()
+		q.replay(send)
+		for {
+			select {
+			case sp := <-q.pending:
+				q.write(sp.data, sp.count)
+			case <-tick.C:
+				q.replay(send)
+			case <-q.kick:
+				q.replay(send)
+			case <-q.done:
+				// write the payloads enqueued before stopping
+				for {
+					select {
+					case sp := <-q.pending:
+						q.write(sp.data, sp.count)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// enqueue copies the payload p to be written to disk by the goroutine. The
+// traces in p are reported as dropped if too many payloads are waiting.
+func (q *spillQueue) enqueue(p *payload) {
+	sp := spilledPayload{data: append([]byte(nil), p.buf.Bytes()...), count: p.itemCount()}
+	select {
+	case q.pending <- sp:
+	default:
+		q.statsd.Count("datadog.tracer.traces_dropped", int64(sp.count), []string{"reason:spill_queue_full"}, 1)
+		log.Error("lost %d traces: too many payloads are waiting to be spilled", sp.count)
+	}
+}
+
+// replaySoon wakes the goroutine up to replay the spilled payloads, for
+// instance because the agent is reachable again.
+func (q *spillQueue) replaySoon() {
+	select {
+	case q.kick <- struct{}{}:
+	default:
+	}
+}
+
+// push writes the payload p to the queue, evicting the oldest payloads if
+// needed. The traces in p are reported as dropped if they cannot be stored.
+func (q *spillQueue) push(p *payload) {
+	q.write(p.buf.Bytes(), p.itemCount())
+}
+
+// write writes the encoded payload data holding count traces to the queue,
+// evicting the oldest payloads if needed.
+func (q *spillQueue) write(data []byte, count int) {
+	if int64(len(data)) > q.maxBytes {
+		q.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:spill_too_large"}, 1)
+		log.Error("lost %d traces: payload of %d bytes exceeds the spill size limit", count, len(data))
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.evictLocked(int64(len(data)))
+	q.seq++
+	f := spillFile{seq: q.seq, count: count, size: int64(len(data))}
+	if err := q.writeFile(f, data); err != nil {
+		q.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:spill_failed"}, 1)
+		log.Error("lost %d traces: unable to spill payload: %s", count, err.Error())
+		return
+	}
+	q.files = append(q.files, f)
+	q.size += f.size
+	q.statsd.Count("datadog.tracer.spill.traces_written", int64(count), nil, 1)
+	log.Debug("spilled %d traces (%d bytes) to %s", count, f.size, q.dir)
+}
+
+// writeFile atomically writes data to the file described by f.
+func (q *spillQueue) writeFile(f spillFile, data []byte) error {
+	path := filepath.Join(q.dir, f.name())
+	tmp := path + spillTmpExt
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// evictLocked removes the oldest payloads until n more bytes fit in the queue.
+// q.mu must be held.
+func (q *spillQueue) evictLocked(n int64) {
+	for len(q.files) > 0 && q.size+n > q.maxBytes {
+		f := q.files[0]
+		q.files = q.files[1:]
+		q.size -= f.size
+		os.Remove(filepath.Join(q.dir, f.name()))
+		q.statsd.Count("datadog.tracer.traces_dropped", int64(f.count), []string{"reason:spill_evicted"}, 1)
+		log.Warn("lost %d traces: spill directory %s is full", f.count, q.dir)
+	}
+}
+
+// peek returns the oldest payload in the queue, if any.
+func (q *spillQueue) peek() (spillFile, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.files) == 0 {
+		return spillFile{}, false
+	}
+	return q.files[0], true
+}
+
+// remove deletes the payload f from the queue. It reports whether f was still
+// in the queue, as it may have been evicted concurrently.
+func (q *spillQueue) remove(f spillFile) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, g := range q.files {
+		if g.seq != f.seq {
+			continue
+		}
+		q.files = append(q.files[:i], q.files[i+1:]...)
+		q.size -= g.size
+		os.Remove(filepath.Join(q.dir, g.name()))
+		return true
+	}
+	return false
+}
+
+// load reads the payload f from disk.
+func (q *spillQueue) load(f spillFile) (*payload, error) {
+	data, err := os.ReadFile(filepath.Join(q.dir, f.name()))
+	if err != nil {
+		return nil, err
+	}
+	p := newPayload()
+	p.buf.Write(data)
+	p.count = uint32(f.count)
+	p.updateHeader()
+	return p, nil
+}
+
+// replay sends the queued payloads using send, oldest first. It stops at the
+// first failure, leaving the remaining payloads in the queue, except for the
+// payloads for which send returns errSpillDropped, which are removed. Only one
+// replay runs at a time; replay returns immediately if another one is in
+// progress.
+func (q *spillQueue) replay(send func(p *payload) error) {
+	if !q.replaying.CompareAndSwap(false, true) {
+		return
+	}
+	defer q.replaying.Store(false)
+	for !q.stopped.Load() {
+		f, ok := q.peek()
+		if !ok {
+			return
+		}
+		p, err := q.load(f)
+		if err != nil {
+			if q.remove(f) && !errors.Is(err, os.ErrNotExist) {
+				q.statsd.Count("datadog.tracer.traces_dropped", int64(f.count), []string{"reason:spill_failed"}, 1)
+				log.Error("lost %d traces: unable to read spilled payload: %s", f.count, err.Error())
+			}
+			continue
+		}
+		err = send(p)
+		p.clear()
+		if errors.Is(err, errSpillDropped) {
+			q.remove(f)
+			continue
+		}
+		if err != nil {
+			log.Debug("failed to replay spilled payload: %s", err.Error())
+			return
+		}
+		if q.remove(f) {
+			q.statsd.Count("datadog.tracer.spill.traces_replayed", int64(f.count), nil, 1)
+		}
+	}
+}
+
+// stop prevents the queue from replaying more payloads, and waits for the
+// enqueued payloads to be written if the goroutine was started. Queued payloads
+// remain on disk and are replayed by the next tracer using the same directory.
+func (q *spillQueue) stop() {
+	if q.stopped.Swap(true) {
+		return
+	}
+	close(q.done)
+	q.wg.Wait()
+}
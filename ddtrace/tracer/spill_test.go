@@ -0,0 +1,271 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+// spillTestPayload returns a payload holding one trace per given operation name.
+func spillTestPayload(t *testing.T, names ...string) *payload {
+	p := newPayload()
+	for _, name := range names {
+		require.NoError(t, p.push([]*Span{newBasicSpan(name)}))
+	}
+	return p
+}
+
+// decodeNames returns the names of the root spans of the traces in p.
+func decodeNames(t *testing.T, p *payload) []string {
+	traces, err := decode(p)
+	require.NoError(t, err)
+	var names []string
+	for _, trace := range traces {
+		names = append(names, trace[0].name)
+	}
+	return names
+}
+
+func TestSpillFileName(t *testing.T) {
+	f := spillFile{seq: 42, count: 3}
+	assert.Equal(t, "00000000000000000042-3.payload", f.name())
+	g, ok := parseSpillFileName(f.name())
+	assert.True(t, ok)
+	assert.Equal(t, f, g)
+
+	for _, name := range []string{"", "42-3", "42.payload", "a-3.payload", "42-b.payload", "42-3.payload.tmp"} {
+		_, ok := parseSpillFileName(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestSpillQueue(t *testing.T) {
+	t.Run("replay", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		q, err := newSpillQueue(t.TempDir(), 1<<20, &tg)
+		require.NoError(t, err)
+		q.push(spillTestPayload(t, "a"))
+		q.push(spillTestPayload(t, "b", "c"))
+		assert.Equal(t, 2, q.len())
+
+		var sent []string
+		q.replay(func(p *payload) error {
+			sent = append(sent, decodeNames(t, p)...)
+			return nil
+		})
+		assert.Equal(t, []string{"a", "b", "c"}, sent)
+		assert.Equal(t, 0, q.len())
+		assert.Equal(t, int64(0), q.bytes())
+		counts := tg.Counts()
+		assert.Equal(t, int64(3), counts["datadog.tracer.spill.traces_written"])
+		assert.Equal(t, int64(3), counts["datadog.tracer.spill.traces_replayed"])
+	})
+
+	t.Run("replay-failure", func(t *testing.T) {
+		q, err := newSpillQueue(t.TempDir(), 1<<20, &statsdtest.TestStatsdClient{})
+		require.NoError(t, err)
+		q.push(spillTestPayload(t, "a"))
+		q.push(spillTestPayload(t, "b"))
+
+		var calls int
+		q.replay(func(*payload) error {
+			calls++
+			return errors.New("agent down")
+		})
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, q.len())
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		p := spillTestPayload(t, "a")
+		size := int64(p.buf.Len())
+		q, err := newSpillQueue(t.TempDir(), 2*size, &tg)
+		require.NoError(t, err)
+		q.push(spillTestPayload(t, "a"))
+		q.push(spillTestPayload(t, "b"))
+		q.push(spillTestPayload(t, "c"))
+		assert.Equal(t, 2, q.len())
+		assert.Equal(t, 2*size, q.bytes())
+
+		var sent []string
+		q.replay(func(p *payload) error {
+			sent = append(sent, decodeNames(t, p)...)
+			return nil
+		})
+		assert.Equal(t, []string{"b", "c"}, sent)
+		assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.traces_dropped"])
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		q, err := newSpillQueue(t.TempDir(), 10, &tg)
+		require.NoError(t, err)
+		q.push(spillTestPayload(t, "a", "b"))
+		assert.Equal(t, 0, q.len())
+		assert.Equal(t, int64(2), tg.Counts()["datadog.tracer.traces_dropped"])
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		dir := t.TempDir()
+		q, err := newSpillQueue(dir, 1<<20, &statsdtest.TestStatsdClient{})
+		require.NoError(t, err)
+		q.push(spillTestPayload(t, "a"))
+		q.push(spillTestPayload(t, "b"))
+		q.stop()
+		// leftovers of an interrupted write and unrelated files
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000003-1.payload.tmp"), []byte("x"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("x"), 0o600))
+
+		q, err = newSpillQueue(dir, 1<<20, &statsdtest.TestStatsdClient{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, q.len())
+		q.push(spillTestPayload(t, "c"))
+
+		var sent []string
+		q.replay(func(p *payload) error {
+			sent = append(sent, decodeNames(t, p)...)
+			return nil
+		})
+		assert.Equal(t, []string{"a", "b", "c"}, sent)
+		_, err = os.Stat(filepath.Join(dir, "00000000000000000003-1.payload.tmp"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := newSpillQueue(t.TempDir(), 0, &statsdtest.TestStatsdClient{})
+		assert.Error(t, err)
+	})
+}
+
+// toggleTransport fails to send payloads while down is set.
+type toggleTransport struct {
+	dummyTransport
+	mu   sync.Mutex
+	down bool
+	sent []string
+	t    *testing.T
+}
+
+func (tt *toggleTransport) setDown(down bool) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.down = down
+}
+
+func (tt *toggleTransport) send(p *payload) (io.ReadCloser, error) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	if tt.down {
+		return nil, errors.New("agent down")
+	}
+	tt.sent = append(tt.sent, decodeNames(tt.t, p)...)
+	return io.NopCloser(strings.NewReader("OK")), nil
+}
+
+func (tt *toggleTransport) sentNames() []string {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	return append([]string(nil), tt.sent...)
+}
+
+func TestTraceWriterSpill(t *testing.T) {
+	tr := &toggleTransport{down: true, t: t}
+	dir := t.TempDir()
+	c, err := newTestConfig(WithPayloadSpillDir(dir, 1<<20), func(c *config) {
+		c.transport = tr
+		c.sendRetries = 0
+		c.retryInterval = 0
+	})
+	require.NoError(t, err)
+	var tg statsdtest.TestStatsdClient
+	h := newAgentTraceWriter(c, nil, &tg)
+	require.NotNil(t, h.spill)
+
+	h.add([]*Span{newBasicSpan("a")})
+	h.flush()
+	h.wg.Wait()
+	// the payload is written to disk in the background
+	require.Eventually(t, func() bool { return h.spill.len() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.spill.traces_written"])
+	assert.NotContains(t, tg.Counts(), "datadog.tracer.traces_dropped")
+
+	tr.setDown(false)
+	h.add([]*Span{newBasicSpan("b")})
+	h.flush()
+	h.wg.Wait()
+	require.Eventually(t, func() bool { return h.spill.len() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"b", "a"}, tr.sentNames())
+	assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.spill.traces_replayed"])
+
+	// payloads which are still spilled when the tracer stops are replayed by
+	// the next one as soon as it starts
+	tr.setDown(true)
+	h.add([]*Span{newBasicSpan("c")})
+	h.stop()
+	tr.setDown(false)
+	h = newAgentTraceWriter(c, nil, &tg)
+	require.Eventually(t, func() bool { return h.spill.len() == 0 }, time.Second, time.Millisecond)
+	h.stop()
+	assert.Equal(t, []string{"b", "a", "c"}, tr.sentNames())
+}
+
+func TestTraceWriterSpillReplayTimer(t *testing.T) {
+	defer func(d time.Duration) { spillReplayInterval = d }(spillReplayInterval)
+	spillReplayInterval = 10 * time.Millisecond
+	defer func(d time.Duration) { breakerMinBackoff = d }(breakerMinBackoff)
+	breakerMinBackoff = time.Millisecond
+
+	tr := &toggleTransport{down: true, t: t}
+	c, err := newTestConfig(WithPayloadSpillDir(t.TempDir(), 1<<20), func(c *config) {
+		c.transport = tr
+		c.sendRetries = 0
+		c.retryInterval = 0
+	})
+	require.NoError(t, err)
+	h := newAgentTraceWriter(c, nil, &statsdtest.TestStatsdClient{})
+	defer h.stop()
+
+	// open the circuit, the next payloads being spilled without being sent
+	for i := range breakerFailureThreshold + 1 {
+		h.add([]*Span{newBasicSpan(strconv.Itoa(i))})
+		h.flush()
+		h.wg.Wait()
+	}
+	require.Eventually(t, func() bool { return h.spill.len() == breakerFailureThreshold+1 }, time.Second, time.Millisecond)
+
+	// the spilled payloads are replayed without any new traffic, the replays
+	// probing the agent to close the circuit
+	tr.setDown(false)
+	require.Eventually(t, func() bool { return h.spill.len() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, breakerClosed, h.breaker.currentState())
+	assert.Len(t, tr.sentNames(), breakerFailureThreshold+1)
+}
+
+func TestWithPayloadSpillDir(t *testing.T) {
+	c, err := newTestConfig(WithPayloadSpillDir("/tmp/spill", 1024))
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/spill", c.spillDir)
+	assert.Equal(t, int64(1024), c.spillMaxBytes)
+
+	c, err = newTestConfig(func(c *config) { c.transport = &dummyTransport{} }, WithPayloadSpillDir(t.TempDir(), -1))
+	require.NoError(t, err)
+	h := newAgentTraceWriter(c, nil, &statsdtest.TestStatsdClient{})
+	assert.Nil(t, h.spill)
+}
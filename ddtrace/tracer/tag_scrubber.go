@@ -0,0 +1,287 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// TagScrubAction specifies what a TagScrubRule does to the tags it matches.
+type TagScrubAction string
+
+const (
+	// TagScrubDrop removes the matching tags from the span.
+	TagScrubDrop TagScrubAction = "drop"
+
+	// TagScrubHash replaces the value of the matching tags with the
+	// hexadecimal HMAC-SHA256 of the value, keyed with the key set with
+	// WithTagScrubbingHashKey, or a random key generated when the tracer
+	// starts.
+	TagScrubHash TagScrubAction = "hash"
+
+	// TagScrubMask replaces the parts of the value of the matching tags which
+	// match the rule's Value with "?", or the whole value if the rule has no Value.
+	TagScrubMask TagScrubAction = "mask"
+)
+
+// tagScrubMask replaces the masked parts of the scrubbed tags.
+const tagScrubMask = "?"
+
+// TagScrubRule is a rule scrubbing span tags before they are sent. It can be
+// passed to WithTagScrubber, or set as a JSON array in the
+// DD_TRACE_TAG_SCRUBBING_RULES environment variable, for instance:
+//
+//	[{"key": "http.request.headers.*", "action": "drop"},
+//	 {"key": "usr.email", "value": "^[^@]+", "action": "mask"}]
+type TagScrubRule struct {
+	// Key is a glob pattern matched against the tag keys. It supports the
+	// '*' and '?' wildcards, as in sampling rules.
+	Key string `json:"key"`
+
+	// Value is an optional regular expression matched against the tag values.
+	// When set, only the tags with a matching value are scrubbed.
+	Value string `json:"value,omitempty"`
+
+	// Action is what is done to the matching tags.
+	Action TagScrubAction `json:"action"`
+}
+
+// tagScrubber scrubs span tags according to a list of rules.
+type tagScrubber struct {
+	rules   []tagScrubRule
+	hashKey []byte // key of the HMAC of the hashed values
+}
+
+// tagScrubRule is a compiled TagScrubRule.
+type tagScrubRule struct {
+	key    *regexp.Regexp // key is nil when the rule matches any key
+	value  *regexp.Regexp // value is nil when the rule matches any value
+	action TagScrubAction
+}
+
+// newTagScrubber compiles rules into a tagScrubber hashing values with
+// hashKey, or a random key if it is empty. Invalid rules are skipped and
+// reported in the returned error.
+func newTagScrubber(rules []TagScrubRule, hashKey []byte) (*tagScrubber, error) {
+	var (
+		ts   = tagScrubber{hashKey: hashKey}
+		errs []error
+	)
+	if len(ts.hashKey) == 0 {
+		ts.hashKey = make([]byte, sha256.Size)
+		rand.Read(ts.hashKey)
+	}
+	for i, r := range rules {
+		switch r.Action {
+		case TagScrubDrop, TagScrubHash, TagScrubMask:
+		default:
+			errs = append(errs, fmt.Errorf("rule %d: unknown action %q", i, r.Action))
+			continue
+		}
+		if r.Key == "" {
+			errs = append(errs, fmt.Errorf("rule %d: missing key", i))
+			continue
+		}
+		rule := tagScrubRule{key: globMatch(r.Key), action: r.Action}
+		if r.Value != "" {
+			re, err := regexp.Compile(r.Value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %d: invalid value: %w", i, err))
+				continue
+			}
+			rule.value = re
+		}
+		ts.rules = append(ts.rules, rule)
+	}
+	return &ts, errors.Join(errs...)
+}
+
+// tagScrubRulesFromEnv parses the tag scrubbing rules set in the
+// DD_TRACE_TAG_SCRUBBING_RULES environment variable, or stable config.
+func tagScrubRulesFromEnv(v string) ([]TagScrubRule, error) {
+	if v == "" {
+		return nil, nil
+	}
+	var rules []TagScrubRule
+	if err := json.Unmarshal([]byte(v), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// scrub applies the rules to the tags of spans, and to the attributes of their
+// span links and span events. Tags reserved by the tracer, whose key starts
+// with '_', are left untouched. Numeric tags and attributes, as well as the
+// structured tags set in meta_struct, are only dropped, as they cannot hold
+// hashed or masked values.
+func (ts *tagScrubber) scrub(spans []*Span) {
+	for _, s := range spans {
+		s.mu.Lock()
+		for k, v := range s.meta {
+			if v, ok := ts.scrubString(k, v); ok {
+				s.meta[k] = v
+			} else {
+				delete(s.meta, k)
+			}
+		}
+		for k := range s.metrics {
+			if r, ok := ts.match(k, nil); ok && r.action == TagScrubDrop {
+				delete(s.metrics, k)
+			}
+		}
+		for k := range s.metaStruct {
+			if r, ok := ts.match(k, nil); ok && r.action == TagScrubDrop {
+				delete(s.metaStruct, k)
+			}
+		}
+		ts.scrubSpanLinks(s)
+		ts.scrubSpanEvents(s)
+		s.mu.Unlock()
+	}
+}
+
+// scrubString applies the first rule matching the tag or attribute k to its
+// value v. It returns the scrubbed value, or false if it is dropped.
+func (ts *tagScrubber) scrubString(k, v string) (string, bool) {
+	r, ok := ts.match(k, &v)
+	if !ok {
+		return v, true
+	}
+	switch r.action {
+	case TagScrubHash:
+		mac := hmac.New(sha256.New, ts.hashKey)
+		mac.Write([]byte(v))
+		return hex.EncodeToString(mac.Sum(nil)), true
+	case TagScrubMask:
+		if r.value != nil {
+			return r.value.ReplaceAllLiteralString(v, tagScrubMask), true
+		}
+		return tagScrubMask, true
+	default:
+		return "", false
+	}
+}
+
+// scrubAttributes scrubs the attributes of a span link or span event, copying
+// them before they are modified as they may be shared with the caller which
+// set them. str returns the value of the string attributes, and fromStr makes
+// an attribute of a scrubbed value. The other attributes are only dropped. It
+// reports whether any attribute was scrubbed.
+func scrubAttributes[V any](ts *tagScrubber, attrs map[string]V, str func(V) (string, bool), fromStr func(string) V) (map[string]V, bool) {
+	out, changed := attrs, false
+	for k, v := range attrs {
+		var (
+			scrubbed string
+			keep     bool
+		)
+		if sv, ok := str(v); ok {
+			if scrubbed, keep = ts.scrubString(k, sv); keep && scrubbed == sv {
+				continue
+			}
+		} else if r, ok := ts.match(k, nil); !ok || r.action != TagScrubDrop {
+			continue
+		}
+		if !changed {
+			out, changed = maps.Clone(attrs), true
+		}
+		if keep {
+			out[k] = fromStr(scrubbed)
+		} else {
+			delete(out, k)
+		}
+	}
+	return out, changed
+}
+
+// scrubSpanLinks scrubs the attributes of the span links of s, which were
+// serialized in its meta when it finished. s.mu must be held.
+func (ts *tagScrubber) scrubSpanLinks(s *Span) {
+	changed := false
+	for i, l := range s.spanLinks {
+		attrs, ok := scrubAttributes(ts, l.Attributes,
+			func(v string) (string, bool) { return v, true },
+			func(v string) string { return v })
+		if ok {
+			s.spanLinks[i].Attributes = attrs
+			changed = true
+		}
+	}
+	if _, ok := s.meta["_dd.span_links"]; ok && changed {
+		s.serializeSpanLinksInMeta()
+	}
+}
+
+// scrubSpanEvents scrubs the attributes of the span events of s, which are
+// either sent natively or were serialized in its meta when it finished.
+// s.mu must be held.
+func (ts *tagScrubber) scrubSpanEvents(s *Span) {
+	for i, e := range s.spanEvents {
+		attrs, ok := scrubAttributes(ts, e.Attributes,
+			func(v *spanEventAttribute) (string, bool) {
+				return v.StringValue, v.Type == spanEventAttributeTypeString
+			},
+			func(v string) *spanEventAttribute {
+				return &spanEventAttribute{Type: spanEventAttributeTypeString, StringValue: v}
+			})
+		if ok {
+			s.spanEvents[i].Attributes = attrs
+		}
+	}
+	raw, ok := s.meta["events"]
+	if !ok {
+		return
+	}
+	var events []spanEvent
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
+		log.Debug("Unable to scrub span events: %s", err.Error())
+		return
+	}
+	changed := false
+	for i, e := range events {
+		attrs, ok := scrubAttributes(ts, e.RawAttributes,
+			func(v any) (string, bool) { sv, ok := v.(string); return sv, ok },
+			func(v string) any { return v })
+		if ok {
+			events[i].RawAttributes = attrs
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if b, err := json.Marshal(events); err == nil {
+		s.meta["events"] = string(b)
+	}
+}
+
+// match returns the first rule matching the tag k with the value v. When v is
+// nil, only the rules matching any value are considered.
+func (ts *tagScrubber) match(k string, v *string) (tagScrubRule, bool) {
+	if strings.HasPrefix(k, "_") {
+		return tagScrubRule{}, false
+	}
+	for _, r := range ts.rules {
+		if r.key != nil && !r.key.MatchString(k) {
+			continue
+		}
+		if r.value != nil && (v == nil || !r.value.MatchString(*v)) {
+			continue
+		}
+		return r, true
+	}
+	return tagScrubRule{}, false
+}
@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+func TestNewTagScrubber(t *testing.T) {
+	ts, err := newTagScrubber([]TagScrubRule{
+		{Key: "a", Action: TagScrubDrop},
+		{Key: "b", Action: "erase"},
+		{Action: TagScrubDrop},
+		{Key: "c", Value: "(", Action: TagScrubMask},
+	}, nil)
+	assert.ErrorContains(t, err, `rule 1: unknown action "erase"`)
+	assert.ErrorContains(t, err, "rule 2: missing key")
+	assert.ErrorContains(t, err, "rule 3: invalid value")
+	assert.Len(t, ts.rules, 1)
+	// a random hash key is generated
+	assert.Len(t, ts.hashKey, sha256.Size)
+}
+
+// testHMAC returns the value of v hashed with key by the TagScrubHash rules.
+func testHMAC(key, v string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(v))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestTagScrubRulesFromEnv(t *testing.T) {
+	rules, err := tagScrubRulesFromEnv(`[{"key":"http.request.headers.*","action":"drop"},{"key":"usr.email","value":"^[^@]+","action":"mask"}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []TagScrubRule{
+		{Key: "http.request.headers.*", Action: TagScrubDrop},
+		{Key: "usr.email", Value: "^[^@]+", Action: TagScrubMask},
+	}, rules)
+
+	_, err = tagScrubRulesFromEnv(`{`)
+	assert.Error(t, err)
+}
+
+func TestTagScrubber(t *testing.T) {
+	ts, err := newTagScrubber([]TagScrubRule{
+		{Key: "http.request.headers.*", Action: TagScrubDrop},
+		{Key: "usr.email", Value: "^[^@]+", Action: TagScrubMask},
+		{Key: "usr.id", Action: TagScrubHash},
+		{Key: "db.statement", Action: TagScrubMask},
+		{Key: "card.number", Action: TagScrubDrop},
+		{Key: "_dd.*", Action: TagScrubDrop},
+		{Key: "token", Value: "^secret", Action: TagScrubDrop},
+	}, []byte("key"))
+	require.NoError(t, err)
+
+	s := newBasicSpan("op")
+	s.SetTag("http.request.headers.authorization", "Bearer xyz")
+	s.SetTag("http.request.headers.accept", "*/*")
+	s.SetTag("usr.email", "jane.doe@example.com")
+	s.SetTag("usr.id", "1234")
+	s.SetTag("db.statement", "SELECT * FROM users WHERE password = 'hunter2'")
+	s.SetTag("card.number", 4242424242424242)
+	s.SetTag("token", "public")
+	s.SetTag("http.method", "GET")
+	s.SetTag(ext.ManualKeep, true)
+	s.meta[keyDecisionMaker] = "-4"
+	ts.scrub([]*Span{s})
+
+	assert.NotContains(t, s.meta, "http.request.headers.authorization")
+	assert.NotContains(t, s.meta, "http.request.headers.accept")
+	assert.Equal(t, "?@example.com", s.meta["usr.email"])
+	assert.Equal(t, testHMAC("key", "1234"), s.meta["usr.id"])
+	assert.Equal(t, "?", s.meta["db.statement"])
+	assert.NotContains(t, s.metrics, "card.number")
+	assert.Equal(t, "public", s.meta["token"])
+	assert.Equal(t, "GET", s.meta["http.method"])
+	assert.Contains(t, s.metrics, keySamplingPriority)
+	assert.Contains(t, s.meta, keyDecisionMaker)
+
+	t.Run("links-events", func(t *testing.T) {
+		attrs := map[string]string{"usr.id": "1234", "card.number": "4242", "kind": "retry"}
+		for _, native := range []bool{true, false} {
+			s := newBasicSpan("op")
+			s.supportsEvents = native
+			s.AddLink(SpanLink{TraceID: 1, SpanID: 2, Attributes: attrs})
+			s.AddEvent("login", WithSpanEventAttributes(map[string]any{"usr.email": "jane.doe@example.com", "card.number": 4242, "ok": true}))
+			s.SetTag("card.number", "4242")
+			s.metaStruct = metaStructMap{"card.number": map[string]any{"value": "4242"}, "appsec": "data"}
+			s.Finish()
+			ts.scrub([]*Span{s})
+
+			assert.Equal(t, map[string]string{"usr.id": testHMAC("key", "1234"), "kind": "retry"}, s.spanLinks[0].Attributes)
+			assert.Len(t, attrs, 3, "the attributes set by the caller are left untouched")
+			var links []SpanLink
+			require.NoError(t, json.Unmarshal([]byte(s.meta["_dd.span_links"]), &links))
+			assert.Equal(t, s.spanLinks[0].Attributes, links[0].Attributes)
+			assert.Equal(t, metaStructMap{"appsec": "data"}, s.metaStruct)
+
+			if native {
+				eventAttrs := s.spanEvents[0].Attributes
+				assert.Equal(t, "?@example.com", eventAttrs["usr.email"].StringValue)
+				assert.NotContains(t, eventAttrs, "card.number")
+				assert.Contains(t, eventAttrs, "ok")
+				continue
+			}
+			var events []spanEvent
+			require.NoError(t, json.Unmarshal([]byte(s.meta["events"]), &events))
+			assert.Equal(t, map[string]any{"usr.email": "?@example.com", "ok": true}, events[0].RawAttributes)
+		}
+	})
+}
+
+func TestTracerTagScrubber(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("option", func(t *testing.T) {
+		tracer, transport, flush, stop, err := startTestTracer(t,
+			WithTagScrubber(TagScrubRule{Key: "http.request.headers.*", Action: TagScrubDrop}),
+		)
+		require.NoError(t, err)
+		defer stop()
+
+		root := tracer.StartSpan("root", Tag("http.request.headers.cookie", "session=1"))
+		child := tracer.StartSpan("child", ChildOf(root.Context()), Tag("http.request.headers.x-api-key", "abc"), Tag("component", "net/http"))
+		child.Finish()
+		root.Finish()
+		flush(1)
+
+		traces := transport.Traces()
+		require.Len(t, traces, 1)
+		require.Len(t, traces[0], 2)
+		for _, s := range traces[0] {
+			assert.NotContains(s.meta, "http.request.headers.cookie")
+			assert.NotContains(s.meta, "http.request.headers.x-api-key")
+		}
+		assert.Equal("net/http", traces[0][1].meta["component"])
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_TAG_SCRUBBING_RULES", `[{"key":"usr.id","action":"hash"}]`)
+		t.Setenv("DD_TRACE_TAG_SCRUBBING_HASH_KEY", "key")
+		tracer, transport, flush, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+
+		tracer.StartSpan("root", Tag("usr.id", "1234")).Finish()
+		flush(1)
+
+		assert.Equal(testHMAC("key", "1234"), transport.Traces()[0][0].meta["usr.id"])
+	})
+
+	t.Run("override", func(t *testing.T) {
+		t.Setenv("DD_TRACE_TAG_SCRUBBING_RULES", `[{"key":"usr.id","action":"hash"}]`)
+		c, err := newTestConfig(WithTagScrubber(TagScrubRule{Key: "usr.id", Action: TagScrubDrop}))
+		require.NoError(t, err)
+		assert.Equal([]TagScrubRule{{Key: "usr.id", Action: TagScrubDrop}}, c.tagScrubRules)
+
+		t.Setenv("DD_TRACE_TAG_SCRUBBING_HASH_KEY", "key")
+		c, err = newTestConfig(WithTagScrubbingHashKey([]byte("other")))
+		require.NoError(t, err)
+		assert.Equal([]byte("other"), c.tagScrubHashKey)
+	})
+}
@@ -0,0 +1,255 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/samplernames"
+)
+
+// tailSamplingMaxSpans is the maximum number of spans held by the tail sampler.
+// When it is reached, the oldest traces are released before their window ends.
+const tailSamplingMaxSpans = 10000
+
+// TailSamplingRule is a rule which decides whether to keep a trace after its
+// spans have finished, regardless of the sampling decision taken when it
+// started. It is created with TailSampleErrors, TailSampleSlowerThan or
+// TailSampleTag and applied with WithTailSampling.
+type TailSamplingRule struct {
+	name  string
+	match func(s *Span) bool
+}
+
+// String returns a description of the rule.
+func (r TailSamplingRule) String() string {
+	return r.name
+}
+
+// TailSampleErrors returns a rule keeping the traces in which any span has an error.
+func TailSampleErrors() TailSamplingRule {
+	return TailSamplingRule{
+		name: "error",
+		match: func(s *Span) bool {
+			return s.error != 0
+		},
+	}
+}
+
+// TailSampleSlowerThan returns a rule keeping the traces whose local root span
+// lasted longer than d.
+func TailSampleSlowerThan(d time.Duration) TailSamplingRule {
+	return TailSamplingRule{
+		name: "duration>" + d.String(),
+		match: func(s *Span) bool {
+			return s.context.trace.root == s && s.duration > int64(d)
+		},
+	}
+}
+
+// TailSampleTag returns a rule keeping the traces in which any span has the tag
+// key with a value matching the glob pattern value. The pattern supports the
+// '*' and '?' wildcards, as in sampling rules. Numeric tags are matched when
+// they are integers.
+func TailSampleTag(key, value string) TailSamplingRule {
+	regex := globMatch(value)
+	return TailSamplingRule{
+		name:  "tag:" + key + ":" + value,
+		match: func(s *Span) bool { return spanTagMatches(s, key, regex) },
+	}
+}
+
+// spanTagMatches reports whether s has the tag key, with a value matching regex
+// if it is not nil.
+func spanTagMatches(s *Span, key string, regex *regexp.Regexp) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v, ok := s.meta[key]; ok {
+		return regex == nil || regex.MatchString(v)
+	}
+	if v, ok := s.metrics[key]; ok {
+		// as in sampling rules, matching floating point numbers is not supported
+		return regex == nil || (math.Floor(v) == v && regex.MatchString(strconv.FormatFloat(v, 'g', -1, 64)))
+	}
+	return false
+}
+
+// tailSampler holds the chunks of the traces which were dropped when their root
+// span started for a window of time, and keeps them if any of its rules matches
+// one of their spans. Holding the chunks allows keeping the earlier chunks of a
+// trace which is partially flushed when a later one matches.
+//
+// It is only used by the tracer's worker goroutine, and is not safe for concurrent use.
+type tailSampler struct {
+	window time.Duration
+	rules  []TailSamplingRule
+
+	traces map[traceID]*tailTrace // traces being held, by trace ID
+	queue  []*tailTrace           // traces being held, oldest first
+	spans  int                    // number of spans held
+
+	// limiter limits the number of traces kept per second, along with the
+	// traces kept by the sampling rules. It may be nil.
+	limiter *rateLimiter
+
+	statsd globalinternal.StatsdClient
+}
+
+// tailTrace holds the chunks of a trace.
+type tailTrace struct {
+	id       traceID
+	deadline time.Time
+	chunks   []*chunk
+	kept     bool // kept reports whether a rule matched the trace
+	limited  bool // limited reports whether a rule matched the trace beyond the rate limit
+}
+
+func newTailSampler(window time.Duration, rules []TailSamplingRule, limiter *rateLimiter, statsd globalinternal.StatsdClient) *tailSampler {
+	return &tailSampler{
+		window:  window,
+		rules:   rules,
+		traces:  make(map[traceID]*tailTrace),
+		limiter: limiter,
+		statsd:  statsd,
+	}
+}
+
+// push adds the chunk c to the sampler, and returns the chunks which are ready
+// to be written.
+func (ts *tailSampler) push(c *chunk, now time.Time) []*chunk {
+	ready := ts.expire(now)
+	if len(c.spans) == 0 {
+		return append(ready, c)
+	}
+	id := c.spans[0].context.traceID
+	tt, held := ts.traces[id]
+	if held && tt.kept {
+		// an earlier chunk of the trace matched
+		keepChunk(c)
+		return append(ready, c)
+	}
+	if !held {
+		if p, ok := c.spans[0].context.SamplingPriority(); ok && p > 0 {
+			// the trace is already kept
+			return append(ready, c)
+		}
+		tt = &tailTrace{id: id, deadline: now.Add(ts.window)}
+		ts.traces[id] = tt
+		ts.queue = append(ts.queue, tt)
+	}
+	if rule, ok := ts.match(c); ok && !tt.limited && ts.allow(tt, rule, now) {
+		log.Debug("Trace %s kept by tail sampling rule %s", c.spans[0].context.TraceID(), rule)
+		ts.statsd.Incr("datadog.tracer.tail_sampling.kept", []string{"rule:" + rule.name}, 1)
+		tt.kept = true
+		c.spans[0].context.trace.keepFinished(ext.PriorityUserKeep, samplernames.TailSampling)
+		for _, hc := range tt.chunks {
+			keepChunk(hc)
+			ts.spans -= len(hc.spans)
+		}
+		keepChunk(c)
+		ready = append(ready, tt.chunks...)
+		tt.chunks = nil
+		return append(ready, c)
+	}
+	tt.chunks = append(tt.chunks, c)
+	ts.spans += len(c.spans)
+	for ts.spans > tailSamplingMaxSpans && len(ts.queue) > 0 {
+		ready = append(ready, ts.release()...)
+	}
+	return ready
+}
+
+// allow reports whether the trace tt matched by rule can be kept within the
+// rate limit. The traces beyond the limit stay dropped.
+func (ts *tailSampler) allow(tt *tailTrace, rule TailSamplingRule, now time.Time) bool {
+	if ts.limiter == nil {
+		return true
+	}
+	if ok, _ := ts.limiter.allowOne(now); ok {
+		return true
+	}
+	tt.limited = true
+	ts.statsd.Incr("datadog.tracer.tail_sampling.rate_limited", []string{"rule:" + rule.name}, 1)
+	return false
+}
+
+// match returns the first rule matching any span in c.
+func (ts *tailSampler) match(c *chunk) (TailSamplingRule, bool) {
+	for _, s := range c.spans {
+		for _, r := range ts.rules {
+			if r.match(s) {
+				return r, true
+			}
+		}
+	}
+	return TailSamplingRule{}, false
+}
+
+// expire returns the chunks of the traces whose window ended at now.
+func (ts *tailSampler) expire(now time.Time) []*chunk {
+	var ready []*chunk
+	for len(ts.queue) > 0 && !now.Before(ts.queue[0].deadline) {
+		ready = append(ready, ts.release()...)
+	}
+	return ready
+}
+
+// flush returns the chunks of all the held traces.
+func (ts *tailSampler) flush() []*chunk {
+	var ready []*chunk
+	for len(ts.queue) > 0 {
+		ready = append(ready, ts.release()...)
+	}
+	return ready
+}
+
+// release stops holding the oldest trace, and returns its chunks.
+func (ts *tailSampler) release() []*chunk {
+	tt := ts.queue[0]
+	ts.queue[0] = nil
+	ts.queue = ts.queue[1:]
+	delete(ts.traces, tt.id)
+	for _, c := range tt.chunks {
+		ts.spans -= len(c.spans)
+	}
+	return tt.chunks
+}
+
+// keepChunk updates the sampling priority and decision maker of the spans of c,
+// which already finished, to keep them.
+func keepChunk(c *chunk) {
+	c.willSend = true
+	dm := samplerToDM(samplernames.TailSampling)
+	for i, s := range c.spans {
+		s.mu.Lock()
+		if _, ok := s.metrics[keySamplingPriority]; ok || i == 0 {
+			s.setMetric(keySamplingPriority, ext.PriorityUserKeep)
+		}
+		if i == 0 {
+			s.setMeta(keyDecisionMaker, dm)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// keepFinished sets the sampling priority of a trace whose root span already
+// finished, and whose sampling priority is thus locked.
+func (t *trace) keepFinished(p int, sampler samplernames.SamplerName) {
+	atomic.StoreUint32((*uint32)(&t.samplingDecision), uint32(decisionKeep))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	locked := t.locked
+	t.locked = false
+	t.setSamplingPriorityLocked(p, sampler)
+	t.locked = locked
+}
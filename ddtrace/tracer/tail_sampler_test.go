@@ -0,0 +1,164 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/samplernames"
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+func TestTailSamplingRules(t *testing.T) {
+	s := newBasicSpan("op")
+	assert.False(t, TailSampleErrors().match(s))
+	s.error = 1
+	assert.True(t, TailSampleErrors().match(s))
+
+	s.duration = int64(2 * time.Second)
+	assert.True(t, TailSampleSlowerThan(time.Second).match(s))
+	assert.False(t, TailSampleSlowerThan(3*time.Second).match(s))
+	child := newSpan("child", "", "", 2, s.traceID, s.spanID)
+	child.context.trace = s.context.trace
+	child.duration = s.duration
+	assert.False(t, TailSampleSlowerThan(time.Second).match(child), "only the local root is considered")
+
+	s.meta["http.route"] = "/users/:id"
+	s.metrics["http.status_code"] = 503
+	assert.True(t, TailSampleTag("http.route", "/users/*").match(s))
+	assert.False(t, TailSampleTag("http.route", "/orders/*").match(s))
+	assert.True(t, TailSampleTag("http.status_code", "5??").match(s))
+	assert.True(t, TailSampleTag("http.status_code", "*").match(s))
+	assert.False(t, TailSampleTag("missing", "*").match(s))
+}
+
+func TestTailSampler(t *testing.T) {
+	// newDroppedChunk returns a chunk holding a new trace dropped when it started
+	var id uint64
+	newDroppedChunk := func(name string) *chunk {
+		id++
+		s := newSpan(name, "", "", id, id, 0)
+		s.context.trace.setSamplingPriority(ext.PriorityAutoReject, samplernames.RuleRate)
+		return &chunk{spans: []*Span{s}}
+	}
+	now := time.Now()
+
+	t.Run("expire", func(t *testing.T) {
+		ts := newTailSampler(time.Second, []TailSamplingRule{TailSampleErrors()}, nil, &statsdtest.TestStatsdClient{})
+		c := newDroppedChunk("dropped")
+		assert.Empty(t, ts.push(c, now))
+		assert.Empty(t, ts.expire(now.Add(500*time.Millisecond)))
+		assert.Equal(t, []*chunk{c}, ts.expire(now.Add(time.Second)))
+		assert.Empty(t, ts.traces)
+		assert.Zero(t, ts.spans)
+		p, _ := c.spans[0].context.SamplingPriority()
+		assert.Equal(t, ext.PriorityAutoReject, p)
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		ts := newTailSampler(time.Second, []TailSamplingRule{TailSampleErrors()}, nil, &tg)
+		first := newDroppedChunk("first")
+		second := &chunk{spans: []*Span{newSpan("second", "", "", 2, first.spans[0].traceID, first.spans[0].spanID)}}
+		second.spans[0].context = newSpanContext(second.spans[0], first.spans[0].context)
+		second.spans[0].error = 1
+		assert.Empty(t, ts.push(first, now))
+		// a partial flush of the same trace matches: both chunks are kept
+		assert.Equal(t, []*chunk{first, second}, ts.push(second, now))
+		for _, c := range []*chunk{first, second} {
+			assert.True(t, c.willSend)
+			assert.Equal(t, float64(ext.PriorityUserKeep), c.spans[0].metrics[keySamplingPriority])
+			assert.Equal(t, "-13", c.spans[0].meta[keyDecisionMaker])
+		}
+		p, _ := first.spans[0].context.SamplingPriority()
+		assert.Equal(t, ext.PriorityUserKeep, p)
+		assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.tail_sampling.kept"])
+
+		// later chunks of a kept trace are kept too
+		third := &chunk{spans: []*Span{newSpan("third", "", "", 3, first.spans[0].traceID, first.spans[0].spanID)}}
+		third.spans[0].context = newSpanContext(third.spans[0], first.spans[0].context)
+		assert.Equal(t, []*chunk{third}, ts.push(third, now))
+		assert.True(t, third.willSend)
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		ts := newTailSampler(time.Second, []TailSamplingRule{TailSampleErrors()}, nil, &statsdtest.TestStatsdClient{})
+		s := newBasicSpan("kept")
+		s.context.trace.setSamplingPriority(ext.PriorityAutoKeep, samplernames.AgentRate)
+		c := &chunk{spans: []*Span{s}}
+		assert.Equal(t, []*chunk{c}, ts.push(c, now))
+		assert.Empty(t, ts.traces)
+	})
+
+	t.Run("rate-limit", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		ts := newTailSampler(time.Second, []TailSamplingRule{TailSampleErrors()}, newRateLimiter(1), &tg)
+		first, second := newDroppedChunk("first"), newDroppedChunk("second")
+		first.spans[0].error, second.spans[0].error = 1, 1
+		assert.Equal(t, []*chunk{first}, ts.push(first, now))
+		// the second trace matches beyond the rate limit: it stays dropped
+		assert.Empty(t, ts.push(second, now))
+		assert.False(t, second.willSend)
+		p, _ := second.spans[0].context.SamplingPriority()
+		assert.Equal(t, ext.PriorityAutoReject, p)
+		assert.Equal(t, int64(1), tg.Counts()["datadog.tracer.tail_sampling.rate_limited"])
+		assert.Equal(t, []*chunk{second}, ts.expire(now.Add(time.Second)))
+	})
+
+	t.Run("max-spans", func(t *testing.T) {
+		ts := newTailSampler(time.Hour, []TailSamplingRule{TailSampleErrors()}, nil, &statsdtest.TestStatsdClient{})
+		var released int
+		for range tailSamplingMaxSpans + 10 {
+			released += len(ts.push(newDroppedChunk("dropped"), now))
+		}
+		assert.Equal(t, 10, released)
+		assert.Equal(t, tailSamplingMaxSpans, ts.spans)
+		assert.Len(t, ts.flush(), tailSamplingMaxSpans)
+		assert.Zero(t, ts.spans)
+	})
+}
+
+func TestTracerTailSampling(t *testing.T) {
+	tracer, transport, flush, stop, err := startTestTracer(t,
+		WithSamplingRules(TraceSamplingRules(Rule{Rate: 0})),
+		WithTailSampling(time.Hour, TailSampleErrors(), TailSampleSlowerThan(time.Minute), TailSampleTag("customer.tier", "gold")),
+	)
+	require.NoError(t, err)
+	defer stop()
+
+	root := tracer.StartSpan("failing")
+	child := tracer.StartSpan("child", ChildOf(root.Context()))
+	child.Finish(WithError(errors.New("boom")))
+	root.Finish()
+
+	tracer.StartSpan("slow", StartTime(time.Now().Add(-2*time.Minute))).Finish()
+	tracer.StartSpan("gold", Tag("customer.tier", "gold")).Finish()
+	tracer.StartSpan("fast").Finish()
+	flush(3)
+
+	traces := transport.Traces()
+	require.Len(t, traces, 3)
+	names := make([]string, 0, len(traces))
+	for _, trace := range traces {
+		var root *Span
+		for _, s := range trace {
+			if s.parentID == 0 {
+				root = s
+			}
+		}
+		require.NotNil(t, root)
+		names = append(names, root.name)
+		assert.Equal(t, float64(ext.PriorityUserKeep), root.metrics[keySamplingPriority], root.name)
+		assert.Equal(t, "-13", trace[0].meta[keyDecisionMaker], root.name)
+	}
+	assert.ElementsMatch(t, []string{"failing", "slow", "gold"}, names)
+}
@@ -50,6 +50,7 @@ func startTelemetry(c *config) telemetry.Client {
 		{Name: "trace_agent_url", Value: c.agentURL.String()},
 		{Name: "agent_hostname", Value: c.hostname},
 		{Name: "runtime_metrics_v2_enabled", Value: c.runtimeMetricsV2},
+		{Name: "runtime_metrics_histograms_enabled", Value: c.runtimeMetricsHistograms},
 		{Name: "dogstatsd_addr", Value: c.dogstatsdAddr},
 		{Name: "debug_stack_enabled", Value: !c.noDebugStack},
 		{Name: "profiling_hotspots_enabled", Value: c.profilerHotspots},
@@ -135,6 +135,50 @@ type PropagatorConfig struct {
 	// BaggageHeader specifies the map key that will be used to store the baggage key-value pairs.
 	// It defaults to DefaultBaggageHeader.
 	BaggageHeader string
+
+	// BaggageMaxItems specifies the maximum number of items injected into or
+	// extracted from the baggage header, the excess items being dropped.
+	// It defaults to the value of DD_TRACE_BAGGAGE_MAX_ITEMS, or 64.
+	BaggageMaxItems int
+
+	// BaggageMaxBytes specifies the maximum size in bytes of the baggage header
+	// injected or extracted, the items exceeding it being dropped.
+	// It defaults to the value of DD_TRACE_BAGGAGE_MAX_BYTES, or 8192.
+	BaggageMaxBytes int
+
+	// XRay specifies if the AWS X-Ray X-Amzn-Trace-Id header should be added
+	// for trace propagation.
+	XRay bool
+
+	// Jaeger specifies if the Jaeger uber-trace-id and uberctx- prefixed
+	// baggage headers should be added for trace propagation.
+	Jaeger bool
+
+	// GCP specifies if the Google Cloud X-Cloud-Trace-Context header should be
+	// added for trace propagation.
+	GCP bool
+}
+
+// vendorPropagators returns the X-Ray, Jaeger and GCP propagators enabled in
+// cfg, along with their names.
+func (cfg *PropagatorConfig) vendorPropagators() ([]Propagator, []string) {
+	var (
+		ps    []Propagator
+		names []string
+	)
+	if cfg.XRay {
+		ps = append(ps, &propagatorXRay{})
+		names = append(names, "xray")
+	}
+	if cfg.Jaeger {
+		ps = append(ps, &propagatorJaeger{})
+		names = append(names, "jaeger")
+	}
+	if cfg.GCP {
+		ps = append(ps, &propagatorGCP{})
+		names = append(names, "gcp")
+	}
+	return ps, names
 }
 
 // NewPropagator returns a new propagator which uses TextMap to inject
@@ -165,7 +209,17 @@ func NewPropagator(cfg *PropagatorConfig, propagators ...Propagator) Propagator
 	if cfg.BaggageHeader == "" {
 		cfg.BaggageHeader = DefaultBaggageHeader
 	}
+	if cfg.BaggageMaxItems <= 0 || cfg.BaggageMaxBytes <= 0 {
+		maxItems, maxBytes := baggageLimits()
+		if cfg.BaggageMaxItems <= 0 {
+			cfg.BaggageMaxItems = maxItems
+		}
+		if cfg.BaggageMaxBytes <= 0 {
+			cfg.BaggageMaxBytes = maxBytes
+		}
+	}
 	cp := new(chainedPropagator)
+	cp.baggageTagger = newBaggageTagger()
 	cp.onlyExtractFirst = internal.BoolEnv("DD_TRACE_PROPAGATION_EXTRACT_FIRST", false)
 	if len(propagators) > 0 {
 		cp.injectors = propagators
@@ -187,7 +241,8 @@ type chainedPropagator struct {
 	extractors       []Propagator
 	injectorNames    string
 	extractorsNames  string
-	onlyExtractFirst bool // value of DD_TRACE_PROPAGATION_EXTRACT_FIRST
+	onlyExtractFirst bool           // value of DD_TRACE_PROPAGATION_EXTRACT_FIRST
+	baggageTagger    *baggageTagger // selects the baggage items promoted to span tags
 }
 
 // getPropagators returns a list of propagators based on ps, which is a comma seperated
@@ -196,12 +251,17 @@ type chainedPropagator struct {
 // a warning and be ignored.
 func getPropagators(cfg *PropagatorConfig, ps string) ([]Propagator, string) {
 	dd := &propagator{cfg}
-	defaultPs := []Propagator{dd, &propagatorW3c{}, &propagatorBaggage{}}
+	defaultPs := []Propagator{dd, &propagatorW3c{}, &propagatorBaggage{cfg}}
 	defaultPsName := "datadog,tracecontext,baggage"
 	if cfg.B3 {
 		defaultPs = append(defaultPs, &propagatorB3{})
 		defaultPsName += ",b3"
 	}
+	vendorPs, vendorPsNames := cfg.vendorPropagators()
+	for i, p := range vendorPs {
+		defaultPs = append(defaultPs, p)
+		defaultPsName += "," + vendorPsNames[i]
+	}
 	if ps == "" {
 		if prop := getDDorOtelConfig("propagationStyle"); prop != "" {
 			ps = prop // use the generic DD_TRACE_PROPAGATION_STYLE if set
@@ -219,6 +279,8 @@ func getPropagators(cfg *PropagatorConfig, ps string) ([]Propagator, string) {
 		list = append(list, &propagatorB3{})
 		listNames = append(listNames, "b3")
 	}
+	list = append(list, vendorPs...)
+	listNames = append(listNames, vendorPsNames...)
 	for _, v := range strings.Split(ps, ",") {
 		switch v := strings.ToLower(v); v {
 		case "datadog":
@@ -228,7 +290,7 @@ func getPropagators(cfg *PropagatorConfig, ps string) ([]Propagator, string) {
 			list = append(list, &propagatorW3c{})
 			listNames = append(listNames, v)
 		case "baggage":
-			list = append(list, &propagatorBaggage{})
+			list = append(list, &propagatorBaggage{cfg})
 			listNames = append(listNames, v)
 		case "b3", "b3multi":
 			if !cfg.B3 {
@@ -239,6 +301,21 @@ func getPropagators(cfg *PropagatorConfig, ps string) ([]Propagator, string) {
 		case "b3 single header":
 			list = append(list, &propagatorB3SingleHeader{})
 			listNames = append(listNames, v)
+		case "xray":
+			if !cfg.XRay {
+				list = append(list, &propagatorXRay{})
+				listNames = append(listNames, v)
+			}
+		case "jaeger":
+			if !cfg.Jaeger {
+				list = append(list, &propagatorJaeger{})
+				listNames = append(listNames, v)
+			}
+		case "gcp":
+			if !cfg.GCP {
+				list = append(list, &propagatorGCP{})
+				listNames = append(listNames, v)
+			}
 		case "none":
 			log.Warn("Propagator \"none\" has no effect when combined with other propagators. " +
 				"To disable the propagator, set to `none`")
@@ -351,6 +428,7 @@ func (p *chainedPropagator) Extract(carrier interface{}) (*SpanContext, error) {
 			}
 			maps.Copy(ctx.baggage, pendingBaggage)
 			atomic.StoreUint32(&ctx.hasBaggage, 1)
+			ctx.baggageTags = p.baggageTags(pendingBaggage)
 			return ctx, nil
 		}
 		// 0 successful extractions
@@ -364,6 +442,7 @@ func (p *chainedPropagator) Extract(carrier interface{}) (*SpanContext, error) {
 			ctx.baggage[k] = v
 		}
 		atomic.StoreUint32(&ctx.hasBaggage, 1)
+		ctx.baggageTags = p.baggageTags(pendingBaggage)
 	}
 
 	if len(links) > 0 {
@@ -373,6 +452,15 @@ func (p *chainedPropagator) Extract(carrier interface{}) (*SpanContext, error) {
 	return ctx, nil
 }
 
+// baggageTags returns the span tags of the items of the extracted baggage
+// promoted to tags of the spans started from the extracted span context.
+func (p *chainedPropagator) baggageTags(baggage map[string]string) map[string]string {
+	if p.baggageTagger == nil {
+		return nil
+	}
+	return p.baggageTagger.tags(baggage)
+}
+
 func getPropagatorName(p Propagator) string {
 	switch p.(type) {
 	case *propagator:
@@ -385,6 +473,12 @@ func getPropagatorName(p Propagator) string {
 		return "tracecontext"
 	case *propagatorBaggage:
 		return "baggage"
+	case *propagatorXRay:
+		return "xray"
+	case *propagatorJaeger:
+		return "jaeger"
+	case *propagatorGCP:
+		return "gcp"
 	default:
 		return ""
 	}
@@ -821,6 +915,284 @@ func (*propagatorB3SingleHeader) extractTextMap(reader TextMapReader) (*SpanCont
 	return &ctx, nil
 }
 
+const xrayTraceIDHeader = "x-amzn-trace-id"
+
+// propagatorXRay implements Propagator and injects/extracts span contexts
+// using the AWS X-Ray X-Amzn-Trace-Id header, of the form
+// "Root=1-<epoch>-<unique>;Parent=<span id>;Sampled=<0|1>". The 8 hex digits
+// of the epoch and the 24 hex digits of the unique ID form the 128-bit trace ID.
+// Only TextMap carriers are supported.
+type propagatorXRay struct{}
+
+func (p *propagatorXRay) Inject(spanCtx *SpanContext, carrier interface{}) error {
+	if spanCtx == nil {
+		return ErrInvalidSpanContext
+	}
+	switch c := carrier.(type) {
+	case TextMapWriter:
+		return p.injectTextMap(spanCtx, c)
+	default:
+		return ErrInvalidCarrier
+	}
+}
+
+func (*propagatorXRay) injectTextMap(spanCtx *SpanContext, writer TextMapWriter) error {
+	ctx := spanCtx
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return ErrInvalidSpanContext
+	}
+	tid := ctx.TraceID()
+	v := fmt.Sprintf("Root=1-%s-%s;Parent=%016x", tid[:8], tid[8:], ctx.spanID)
+	if p, ok := ctx.SamplingPriority(); ok {
+		if p >= ext.PriorityAutoKeep {
+			v += ";Sampled=1"
+		} else {
+			v += ";Sampled=0"
+		}
+	}
+	writer.Set(xrayTraceIDHeader, v)
+	return nil
+}
+
+func (p *propagatorXRay) Extract(carrier interface{}) (*SpanContext, error) {
+	switch c := carrier.(type) {
+	case TextMapReader:
+		return p.extractTextMap(c)
+	default:
+		return nil, ErrInvalidCarrier
+	}
+}
+
+func (*propagatorXRay) extractTextMap(reader TextMapReader) (*SpanContext, error) {
+	var ctx SpanContext
+	err := reader.ForeachKey(func(k, v string) error {
+		if strings.ToLower(k) != xrayTraceIDHeader {
+			return nil
+		}
+		for _, part := range strings.Split(v, ";") {
+			key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch key {
+			case "Root":
+				// Root=1-<8 hex digits>-<24 hex digits>
+				version, rest, _ := strings.Cut(val, "-")
+				epoch, unique, ok := strings.Cut(rest, "-")
+				if version != "1" || !ok || len(epoch) != 8 || len(unique) != 24 || !isValidID(epoch+unique) {
+					return ErrSpanContextCorrupted
+				}
+				if err := extractTraceID128(&ctx, epoch+unique); err != nil {
+					return err
+				}
+			case "Parent":
+				id, err := strconv.ParseUint(val, 16, 64)
+				if err != nil {
+					return ErrSpanContextCorrupted
+				}
+				ctx.spanID = id
+			case "Sampled":
+				switch val {
+				case "1":
+					ctx.setSamplingPriority(ext.PriorityAutoKeep, samplernames.Unknown)
+				case "0":
+					ctx.setSamplingPriority(ext.PriorityAutoReject, samplernames.Unknown)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return nil, ErrSpanContextNotFound
+	}
+	return &ctx, nil
+}
+
+const (
+	jaegerTraceIDHeader       = "uber-trace-id"
+	jaegerBaggageHeaderPrefix = "uberctx-"
+)
+
+// propagatorJaeger implements Propagator and injects/extracts span contexts
+// using the Jaeger uber-trace-id header, of the form
+// "<trace id>:<span id>:<parent span id>:<flags>", and baggage using the
+// uberctx- prefixed headers. Only TextMap carriers are supported.
+type propagatorJaeger struct{}
+
+func (p *propagatorJaeger) Inject(spanCtx *SpanContext, carrier interface{}) error {
+	if spanCtx == nil {
+		return ErrInvalidSpanContext
+	}
+	switch c := carrier.(type) {
+	case TextMapWriter:
+		return p.injectTextMap(spanCtx, c)
+	default:
+		return ErrInvalidCarrier
+	}
+}
+
+func (*propagatorJaeger) injectTextMap(spanCtx *SpanContext, writer TextMapWriter) error {
+	ctx := spanCtx
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return ErrInvalidSpanContext
+	}
+	var traceID string
+	if !ctx.traceID.HasUpper() { // 64-bit trace id
+		traceID = fmt.Sprintf("%016x", ctx.traceID.Lower())
+	} else { // 128-bit trace id
+		traceID = ctx.TraceID()
+	}
+	flags := 0
+	if p, ok := ctx.SamplingPriority(); ok && p >= ext.PriorityAutoKeep {
+		flags = 1
+	}
+	// the parent span ID is deprecated, and always set to 0
+	writer.Set(jaegerTraceIDHeader, fmt.Sprintf("%s:%016x:0:%d", traceID, ctx.spanID, flags))
+	ctx.ForeachBaggageItem(func(k, v string) bool {
+		writer.Set(jaegerBaggageHeaderPrefix+k, url.QueryEscape(v))
+		return true
+	})
+	return nil
+}
+
+func (p *propagatorJaeger) Extract(carrier interface{}) (*SpanContext, error) {
+	switch c := carrier.(type) {
+	case TextMapReader:
+		return p.extractTextMap(c)
+	default:
+		return nil, ErrInvalidCarrier
+	}
+}
+
+func (*propagatorJaeger) extractTextMap(reader TextMapReader) (*SpanContext, error) {
+	var ctx SpanContext
+	err := reader.ForeachKey(func(k, v string) error {
+		key := strings.ToLower(k)
+		switch {
+		case key == jaegerTraceIDHeader:
+			if u, err := url.QueryUnescape(v); err == nil {
+				v = u
+			}
+			parts := strings.Split(v, ":")
+			if len(parts) != 4 || !isValidID(parts[0]) {
+				return ErrSpanContextCorrupted
+			}
+			if err := extractTraceID128(&ctx, parts[0]); err != nil {
+				return err
+			}
+			id, err := strconv.ParseUint(parts[1], 16, 64)
+			if err != nil {
+				return ErrSpanContextCorrupted
+			}
+			ctx.spanID = id
+			flags, err := strconv.ParseUint(parts[3], 16, 8)
+			if err != nil {
+				return ErrSpanContextCorrupted
+			}
+			if flags&0x3 != 0 { // sampled or debug
+				ctx.setSamplingPriority(ext.PriorityAutoKeep, samplernames.Unknown)
+			} else {
+				ctx.setSamplingPriority(ext.PriorityAutoReject, samplernames.Unknown)
+			}
+		case strings.HasPrefix(key, jaegerBaggageHeaderPrefix):
+			if u, err := url.QueryUnescape(v); err == nil {
+				v = u
+			}
+			ctx.setBaggageItem(strings.TrimPrefix(key, jaegerBaggageHeaderPrefix), v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return nil, ErrSpanContextNotFound
+	}
+	return &ctx, nil
+}
+
+const gcpTraceContextHeader = "x-cloud-trace-context"
+
+// propagatorGCP implements Propagator and injects/extracts span contexts
+// using the Google Cloud X-Cloud-Trace-Context header, of the form
+// "<32 hex digits trace id>/<decimal span id>;o=<0|1>". Only TextMap carriers
+// are supported.
+type propagatorGCP struct{}
+
+func (p *propagatorGCP) Inject(spanCtx *SpanContext, carrier interface{}) error {
+	if spanCtx == nil {
+		return ErrInvalidSpanContext
+	}
+	switch c := carrier.(type) {
+	case TextMapWriter:
+		return p.injectTextMap(spanCtx, c)
+	default:
+		return ErrInvalidCarrier
+	}
+}
+
+func (*propagatorGCP) injectTextMap(spanCtx *SpanContext, writer TextMapWriter) error {
+	ctx := spanCtx
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return ErrInvalidSpanContext
+	}
+	v := ctx.TraceID() + "/" + strconv.FormatUint(ctx.spanID, 10)
+	if p, ok := ctx.SamplingPriority(); ok {
+		if p >= ext.PriorityAutoKeep {
+			v += ";o=1"
+		} else {
+			v += ";o=0"
+		}
+	}
+	writer.Set(gcpTraceContextHeader, v)
+	return nil
+}
+
+func (p *propagatorGCP) Extract(carrier interface{}) (*SpanContext, error) {
+	switch c := carrier.(type) {
+	case TextMapReader:
+		return p.extractTextMap(c)
+	default:
+		return nil, ErrInvalidCarrier
+	}
+}
+
+func (*propagatorGCP) extractTextMap(reader TextMapReader) (*SpanContext, error) {
+	var ctx SpanContext
+	err := reader.ForeachKey(func(k, v string) error {
+		if strings.ToLower(k) != gcpTraceContextHeader {
+			return nil
+		}
+		ids, options, _ := strings.Cut(v, ";")
+		tid, sid, ok := strings.Cut(ids, "/")
+		if !ok || len(tid) != 32 || !isValidID(tid) {
+			return ErrSpanContextCorrupted
+		}
+		if err := extractTraceID128(&ctx, tid); err != nil {
+			return err
+		}
+		id, err := strconv.ParseUint(sid, 10, 64)
+		if err != nil {
+			return ErrSpanContextCorrupted
+		}
+		ctx.spanID = id
+		switch options {
+		case "o=1":
+			ctx.setSamplingPriority(ext.PriorityAutoKeep, samplernames.Unknown)
+		case "o=0":
+			ctx.setSamplingPriority(ext.PriorityAutoReject, samplernames.Unknown)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ctx.traceID.Empty() || ctx.spanID == 0 {
+		return nil, ErrSpanContextNotFound
+	}
+	return &ctx, nil
+}
+
 const (
 	traceparentHeader = "traceparent"
 	tracestateHeader  = "tracestate"
@@ -1332,6 +1704,8 @@ func extractTraceID128(ctx *SpanContext, v string) error {
 }
 
 const (
+	// baggageMaxItems and baggageMaxBytes are the default limits of the
+	// baggage header, see PropagatorConfig.
 	baggageMaxItems     = 64
 	baggageMaxBytes     = 8192
 	safeCharactersKey   = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789!#$%&'*+-.^_`|~"
@@ -1363,7 +1737,22 @@ func urlEncode(input string, safeCharacters string) string {
 
 // propagatorBaggage implements Propagator and injects/extracts span contexts
 // using baggage headers.
-type propagatorBaggage struct{}
+type propagatorBaggage struct {
+	cfg *PropagatorConfig
+}
+
+// limits returns the maximum number of items and size in bytes of the baggage
+// header.
+func (p *propagatorBaggage) limits() (maxItems, maxBytes int) {
+	maxItems, maxBytes = baggageMaxItems, baggageMaxBytes
+	if p.cfg != nil && p.cfg.BaggageMaxItems > 0 {
+		maxItems = p.cfg.BaggageMaxItems
+	}
+	if p.cfg != nil && p.cfg.BaggageMaxBytes > 0 {
+		maxBytes = p.cfg.BaggageMaxBytes
+	}
+	return maxItems, maxBytes
+}
 
 func (p *propagatorBaggage) Inject(spanCtx *SpanContext, carrier interface{}) error {
 	switch c := carrier.(type) {
@@ -1377,22 +1766,24 @@ func (p *propagatorBaggage) Inject(spanCtx *SpanContext, carrier interface{}) er
 // injectTextMap propagates baggage items from the span context into the writer,
 // in the format of a single HTTP "baggage" header. Baggage consists of key=value pairs,
 // separated by commas. This function enforces a maximum number of baggage items and a maximum overall size.
-// If either limit is exceeded, excess items or bytes are dropped, and a warning is logged.
+// If either limit is exceeded, excess items are dropped, and the truncation is reported to telemetry.
 //
 // Example of a single "baggage" header:
 // baggage: foo=bar,baz=qux
 //
 // Each key and value pair is encoded and added to the existing baggage header in <key>=<value> format,
 // joined together by commas,
-func (*propagatorBaggage) injectTextMap(ctx *SpanContext, writer TextMapWriter) error {
+func (p *propagatorBaggage) injectTextMap(ctx *SpanContext, writer TextMapWriter) error {
 	if ctx == nil {
 		return nil
 	}
 
+	maxItems, maxBytes := p.limits()
 	ctr := 0
 	var baggageBuilder strings.Builder
 	ctx.ForeachBaggageItem(func(k, v string) bool {
-		if ctr >= baggageMaxItems {
+		if ctr >= maxItems {
+			reportBaggageTruncated("baggage_item_count_exceeded")
 			return false
 		}
 
@@ -1404,7 +1795,8 @@ func (*propagatorBaggage) injectTextMap(ctx *SpanContext, writer TextMapWriter)
 		itemBuilder.WriteString(encodeKey(k))
 		itemBuilder.WriteRune('=')
 		itemBuilder.WriteString(encodeValue(v))
-		if itemBuilder.Len()+baggageBuilder.Len() > baggageMaxBytes {
+		if itemBuilder.Len()+baggageBuilder.Len() > maxBytes {
+			reportBaggageTruncated("baggage_byte_count_exceeded")
 			return false
 		}
 		baggageBuilder.WriteString(itemBuilder.String())
@@ -1426,7 +1818,11 @@ func (p *propagatorBaggage) Extract(carrier interface{}) (*SpanContext, error) {
 	}
 }
 
-func (*propagatorBaggage) extractTextMap(reader TextMapReader) (*SpanContext, error) {
+// extractTextMap extracts the baggage items from the "baggage" header of the
+// reader. It enforces the same limits as injectTextMap: the items beyond the
+// maximum number of items or overall size are dropped, and the truncation is
+// reported to telemetry.
+func (p *propagatorBaggage) extractTextMap(reader TextMapReader) (*SpanContext, error) {
 	var baggageHeader string
 	var ctx SpanContext
 	err := reader.ForeachKey(func(k, v string) error {
@@ -1460,8 +1856,21 @@ func (*propagatorBaggage) extractTextMap(reader TextMapReader) (*SpanContext, er
 		parts[i] = trimmedK + "=" + trimmedV
 	}
 
-	// 2) safe to URL-decode & apply
-	for _, kv := range parts {
+	// 2) safe to URL-decode & apply, within the limits
+	maxItems, maxBytes := p.limits()
+	size := 0
+	for i, kv := range parts {
+		if i >= maxItems {
+			reportBaggageTruncated("baggage_item_count_exceeded")
+			break
+		}
+		if i > 0 {
+			size++ // the separating comma
+		}
+		if size += len(kv); size > maxBytes {
+			reportBaggageTruncated("baggage_byte_count_exceeded")
+			break
+		}
 		rawK, rawV, _ := strings.Cut(kv, "=")
 		key, _ := url.QueryUnescape(rawK)
 		val, _ := url.QueryUnescape(rawV)
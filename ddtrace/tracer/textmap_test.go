@@ -3003,3 +3003,201 @@ func TestSpanContextDebugLoggingSecurity(t *testing.T) {
 	// This test ensures that the SafeDebugString() method is used instead of %#v
 	// to prevent sensitive baggage data from being exposed in debug logs.
 }
+
+func TestVendorPropagators(t *testing.T) {
+	tid128 := traceIDFrom128Bits(0x5759e988bd862e3f, 0xe1be46a994272793)
+	tid64 := traceIDFrom64Bits(1)
+	const sid = 0x53995c3f42cd8ad8
+
+	tests := []struct {
+		name       string
+		propagator Propagator
+		header     string
+		tid        traceID
+		priority   int
+		out        string
+	}{
+		{"xray-128", &propagatorXRay{}, xrayTraceIDHeader, tid128, ext.PriorityAutoKeep,
+			"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"},
+		{"xray-64", &propagatorXRay{}, xrayTraceIDHeader, tid64, ext.PriorityAutoReject,
+			"Root=1-00000000-000000000000000000000001;Parent=53995c3f42cd8ad8;Sampled=0"},
+		{"jaeger-128", &propagatorJaeger{}, jaegerTraceIDHeader, tid128, ext.PriorityUserKeep,
+			"5759e988bd862e3fe1be46a994272793:53995c3f42cd8ad8:0:1"},
+		{"jaeger-64", &propagatorJaeger{}, jaegerTraceIDHeader, tid64, ext.PriorityAutoReject,
+			"0000000000000001:53995c3f42cd8ad8:0:0"},
+		{"gcp-128", &propagatorGCP{}, gcpTraceContextHeader, tid128, ext.PriorityAutoKeep,
+			"5759e988bd862e3fe1be46a994272793/6023947403358210776;o=1"},
+		{"gcp-64", &propagatorGCP{}, gcpTraceContextHeader, tid64, ext.PriorityUserReject,
+			"00000000000000000000000000000001/6023947403358210776;o=0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := &SpanContext{traceID: test.tid, spanID: sid}
+			ctx.setSamplingPriority(test.priority, samplernames.Manual)
+			carrier := TextMapCarrier{}
+			require.NoError(t, test.propagator.Inject(ctx, carrier))
+			assert.Equal(test.out, carrier[test.header])
+
+			// the context survives a round trip, the priority becomes an
+			// automatic sampling decision
+			sctx, err := test.propagator.Extract(carrier)
+			require.NoError(t, err)
+			assert.Equal(test.tid, sctx.traceID)
+			assert.Equal(uint64(sid), sctx.spanID)
+			p, ok := sctx.SamplingPriority()
+			assert.True(ok)
+			if test.priority > 0 {
+				assert.Equal(ext.PriorityAutoKeep, p)
+			} else {
+				assert.Equal(ext.PriorityAutoReject, p)
+			}
+		})
+	}
+}
+
+func TestVendorPropagatorsExtract(t *testing.T) {
+	tests := []struct {
+		name       string
+		propagator Propagator
+		in         TextMapCarrier
+		tid        traceID
+		sid        uint64
+		priority   int
+		sampled    bool // whether a sampling priority is extracted
+		err        error
+	}{
+		{
+			name:       "xray",
+			propagator: &propagatorXRay{},
+			in:         TextMapCarrier{"X-Amzn-Trace-Id": "Self=1-67891234-12456789abcdef012345678;Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=?;Lineage=a87bd80c:1"},
+			tid:        traceIDFrom128Bits(0x5759e988bd862e3f, 0xe1be46a994272793),
+			sid:        0x53995c3f42cd8ad8,
+		},
+		{
+			name:       "xray-no-parent",
+			propagator: &propagatorXRay{},
+			in:         TextMapCarrier{"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1"},
+			err:        ErrSpanContextNotFound,
+		},
+		{
+			name:       "xray-malformed-root",
+			propagator: &propagatorXRay{},
+			in:         TextMapCarrier{"X-Amzn-Trace-Id": "Root=1-5759e988bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8"},
+			err:        ErrSpanContextCorrupted,
+		},
+		{
+			name:       "jaeger-short-ids",
+			propagator: &propagatorJaeger{},
+			in:         TextMapCarrier{"Uber-Trace-Id": "1:2:0:3"},
+			tid:        traceIDFrom64Bits(1),
+			sid:        2,
+			priority:   ext.PriorityAutoKeep,
+			sampled:    true,
+		},
+		{
+			name:       "jaeger-url-encoded",
+			propagator: &propagatorJaeger{},
+			in:         TextMapCarrier{"uber-trace-id": "1%3A2%3A0%3A0"},
+			tid:        traceIDFrom64Bits(1),
+			sid:        2,
+			priority:   ext.PriorityAutoReject,
+			sampled:    true,
+		},
+		{
+			name:       "jaeger-malformed",
+			propagator: &propagatorJaeger{},
+			in:         TextMapCarrier{"uber-trace-id": "1:2:0"},
+			err:        ErrSpanContextCorrupted,
+		},
+		{
+			name:       "gcp-no-options",
+			propagator: &propagatorGCP{},
+			in:         TextMapCarrier{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/1"},
+			tid:        traceIDFrom128Bits(0x105445aa7843bc8b, 0xf206b12000100000),
+			sid:        1,
+		},
+		{
+			name:       "gcp-malformed",
+			propagator: &propagatorGCP{},
+			in:         TextMapCarrier{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/abc;o=1"},
+			err:        ErrSpanContextCorrupted,
+		},
+		{
+			name:       "no-headers",
+			propagator: &propagatorGCP{},
+			in:         TextMapCarrier{},
+			err:        ErrSpanContextNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx, err := test.propagator.Extract(test.in)
+			if test.err != nil {
+				assert.Equal(test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(test.tid, ctx.traceID)
+			assert.Equal(test.sid, ctx.spanID)
+			p, ok := ctx.SamplingPriority()
+			assert.Equal(test.sampled, ok)
+			assert.Equal(test.priority, p)
+		})
+	}
+}
+
+func TestJaegerPropagatorBaggage(t *testing.T) {
+	assert := assert.New(t)
+	ctx := &SpanContext{traceID: traceIDFrom64Bits(1), spanID: 2}
+	ctx.setBaggageItem("user", "jane doe")
+	carrier := TextMapCarrier{}
+	p := &propagatorJaeger{}
+	require.NoError(t, p.Inject(ctx, carrier))
+	assert.Equal("jane+doe", carrier["uberctx-user"])
+
+	sctx, err := p.Extract(carrier)
+	require.NoError(t, err)
+	assert.Equal(map[string]string{"user": "jane doe"}, sctx.baggage)
+}
+
+func TestVendorPropagatorsConfig(t *testing.T) {
+	t.Run("env", func(t *testing.T) {
+		t.Setenv(headerPropagationStyle, "gcp,xray,jaeger")
+		cp := NewPropagator(nil).(*chainedPropagator)
+		assert.Equal(t, "gcp,xray,jaeger", cp.injectorNames)
+		assert.Equal(t, "gcp,xray,jaeger", cp.extractorsNames)
+
+		root := newSpan("web.request", "", "", 2, 1, 0)
+		root.context.setSamplingPriority(ext.PriorityAutoKeep, samplernames.AgentRate)
+		headers := http.Header{}
+		require.NoError(t, cp.Inject(root.Context(), HTTPHeadersCarrier(headers)))
+		assert.NotEmpty(t, headers.Get(xrayTraceIDHeader))
+		assert.NotEmpty(t, headers.Get(jaegerTraceIDHeader))
+		assert.NotEmpty(t, headers.Get(gcpTraceContextHeader))
+
+		// only the X-Ray header is left: its context is extracted
+		headers.Del(gcpTraceContextHeader)
+		headers.Del(jaegerTraceIDHeader)
+		ctx, err := cp.Extract(HTTPHeadersCarrier(headers))
+		require.NoError(t, err)
+		assert.Equal(t, root.Context().TraceID(), ctx.TraceID())
+		assert.Equal(t, root.Context().SpanID(), ctx.SpanID())
+	})
+
+	t.Run("otel", func(t *testing.T) {
+		t.Setenv(otelHeaderPropagationStyle, "xray,jaeger")
+		cp := NewPropagator(nil).(*chainedPropagator)
+		assert.Equal(t, "xray,jaeger", cp.injectorNames)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cp := NewPropagator(&PropagatorConfig{XRay: true, GCP: true}).(*chainedPropagator)
+		assert.Equal(t, "datadog,tracecontext,baggage,xray,gcp", cp.injectorNames)
+
+		t.Setenv(headerPropagationStyle, "datadog,gcp")
+		cp = NewPropagator(&PropagatorConfig{Jaeger: true, GCP: true}).(*chainedPropagator)
+		assert.Equal(t, "jaeger,gcp,datadog", cp.injectorNames)
+	})
+}
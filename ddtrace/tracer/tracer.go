@@ -163,6 +163,14 @@ type tracer struct {
 
 	// telemetry is the telemetry client for the tracer.
 	telemetry telemetry.Client
+
+	// tailSampler holds finished traces which were dropped to apply the tail
+	// sampling rules. It is nil when tail sampling is disabled.
+	tailSampler *tailSampler
+
+	// tagScrubber scrubs the tags of the spans before they are written. It is
+	// nil when no tag scrubbing rules are set.
+	tagScrubber *tagScrubber
 }
 
 const (
@@ -353,6 +361,12 @@ func newUnstartedTracer(opts ...StartOption) (t *tracer, err error) {
 	var writer traceWriter
 	if c.ciVisibilityEnabled {
 		writer = newCiVisibilityTraceWriter(c)
+	} else if c.otlpEndpoint != nil {
+		exporter, err := newOTLPExporter(c.otlpEndpoint, c.httpClientTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize OTLP exporter: %s", err.Error())
+		}
+		writer = newOTLPTraceWriter(c, exporter, statsd)
 	} else if c.logToStdout {
 		writer = newLogTraceWriter(c, statsd)
 	} else {
@@ -418,6 +432,16 @@ func newUnstartedTracer(opts ...StartOption) (t *tracer, err error) {
 		dataStreams: dataStreamsProcessor,
 		logFile:     logFile,
 	}
+	if len(c.tailSamplingRules) > 0 {
+		t.tailSampler = newTailSampler(c.tailSamplingWindow, c.tailSamplingRules, rulesSampler.traces.limiter, statsd)
+	}
+	if len(c.tagScrubRules) > 0 {
+		scrubber, err := newTagScrubber(c.tagScrubRules, c.tagScrubHashKey)
+		if err != nil {
+			log.Warn("DIAGNOSTICS Error(s) parsing tag scrubbing rules, ignoring invalid rules: %s", err.Error())
+		}
+		t.tagScrubber = scrubber
+	}
 	return t, nil
 }
 
@@ -441,6 +465,13 @@ func newTracer(opts ...StartOption) (*tracer, error) {
 			t.reportRuntimeMetrics(defaultMetricsReportInterval)
 		}()
 	}
+	if c.runtimeMetricsHistograms {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.reportRuntimeMetricsHistograms(defaultMetricsReportInterval)
+		}()
+	}
 	if c.runtimeMetricsV2 {
 		l := slog.New(slogHandler{})
 		opts := &runtimemetrics.Options{Logger: l}
@@ -507,15 +538,18 @@ func (t *tracer) worker(tick <-chan time.Time) {
 	for {
 		select {
 		case trace := <-t.out:
-			t.sampleChunk(trace)
-			if len(trace.spans) > 0 {
-				t.traceWriter.add(trace.spans)
-			}
+			t.handleChunk(trace)
 		case <-tick:
+			if t.tailSampler != nil {
+				t.writeChunks(t.tailSampler.expire(time.Now()))
+			}
 			t.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:scheduled"}, 1)
 			t.traceWriter.flush()
 
 		case done := <-t.flush:
+			if t.tailSampler != nil {
+				t.writeChunks(t.tailSampler.flush())
+			}
 			t.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:invoked"}, 1)
 			t.traceWriter.flush()
 			t.statsd.Flush()
@@ -534,19 +568,55 @@ func (t *tracer) worker(tick <-chan time.Time) {
 			for {
 				select {
 				case trace := <-t.out:
-					t.sampleChunk(trace)
-					if len(trace.spans) > 0 {
-						t.traceWriter.add(trace.spans)
-					}
+					t.handleChunk(trace)
 				default:
 					break loop
 				}
 			}
+			if t.tailSampler != nil {
+				t.writeChunks(t.tailSampler.flush())
+			}
 			return
 		}
 	}
 }
 
+// handleChunk runs the span processors on the chunk c, received from the out
+// channel, and writes it, unless it's held by the tail sampler.
+func (t *tracer) handleChunk(c *chunk) {
+	t.processChunk(c)
+	if len(c.spans) == 0 {
+		// the span processors dropped the chunk
+		return
+	}
+	if t.tailSampler != nil {
+		t.writeChunks(t.tailSampler.push(c, time.Now()))
+	} else {
+		t.writeChunk(c)
+	}
+}
+
+// writeChunk samples the chunk c, scrubs its tags and adds it to the trace writer.
+func (t *tracer) writeChunk(c *chunk) {
+	t.sampleChunk(c)
+	if len(c.spans) > 0 {
+		if t.tagScrubber != nil {
+			t.tagScrubber.scrub(c.spans)
+		}
+		if r := debugTraces.Load(); r != nil {
+			r.push(c.spans)
+		}
+		t.traceWriter.add(c.spans)
+	}
+}
+
+// writeChunks calls writeChunk for each of the given chunks.
+func (t *tracer) writeChunks(chunks []*chunk) {
+	for _, c := range chunks {
+		t.writeChunk(c)
+	}
+}
+
 // chunk holds information about a trace chunk to be flushed, including its spans.
 // The chunk may be a fully finished local trace chunk, or only a portion of the local trace chunk in the case of
 // partial flushing.
@@ -599,6 +669,13 @@ func (t *tracer) pushChunk(trace *chunk) {
 	default:
 		log.Debug("payload queue full, trace dropped %d spans", len(trace.spans))
 		atomic.AddUint32(&t.totalTracesDropped, 1)
+		if len(t.config.spanProcessors) > 0 {
+			// the stats of the spans are computed once the processors ran,
+			// which they won't: compute them as the spans finished.
+			for _, s := range trace.spans {
+				t.submit(s)
+			}
+		}
 	}
 	select {
 	case <-t.logDroppedTraces.C:
@@ -690,6 +767,12 @@ func spanStart(operationName string, options ...StartSpanOption) *Span {
 		}
 
 	}
+	if context != nil && context.span == nil {
+		// extracted parent, promote its baggage
+		for k, v := range context.baggageTags {
+			span.setMeta(k, v)
+		}
+	}
 	span.context = newSpanContext(span, context)
 	span.setMeta("language", "go")
 	// add tags from options
@@ -724,7 +807,8 @@ func (t *tracer) StartSpan(operationName string, options ...StartSpanOption) *Sp
 	if t.config.hostname != "" {
 		span.setMeta(keyHostname, t.config.hostname)
 	}
-	span.supportsEvents = t.config.agent.spanEventsAvailable
+	// span events are natively supported by the agent or by OTLP
+	span.supportsEvents = t.config.agent.spanEventsAvailable || t.config.otlpEndpoint != nil
 
 	// add global tags
 	for k, v := range t.config.globalTags.get() {
@@ -776,6 +860,9 @@ func (t *tracer) StartSpan(operationName string, options ...StartSpanOption) *Sp
 	}
 	span.setMetric(ext.Pid, float64(t.pid))
 	t.spansStarted.Inc(span.integration)
+	for _, p := range t.config.spanProcessors {
+		p.OnStart(span)
+	}
 
 	return span
 }
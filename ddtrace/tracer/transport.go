@@ -198,13 +198,23 @@ func (t *httpTransport) send(p *payload) (body io.ReadCloser, err error) {
 		response.Body.Close()
 		txt := http.StatusText(code)
 		if n > 0 {
-			return nil, fmt.Errorf("%s (Status: %s)", msg[:n], txt)
+			txt = fmt.Sprintf("%s (Status: %s)", msg[:n], txt)
 		}
-		return nil, fmt.Errorf("%s", txt)
+		return nil, &agentAPIError{statusCode: code, msg: txt}
 	}
 	return response.Body, nil
 }
 
+// agentAPIError is returned when the agent responds with an error status code.
+type agentAPIError struct {
+	statusCode int
+	msg        string
+}
+
+func (e *agentAPIError) Error() string {
+	return e.msg
+}
+
 func reportAPIErrorsMetric(response *http.Response, err error) {
 	if t, ok := getGlobalTracer().(*tracer); ok {
 		var reason string
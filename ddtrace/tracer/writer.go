@@ -52,17 +52,36 @@ type agentTraceWriter struct {
 	// statsd is used to send metrics
 	statsd globalinternal.StatsdClient
 
+	// spill holds the payloads which could not be sent to the agent, when
+	// enabled with WithPayloadSpillDir. It may be nil.
+	spill *spillQueue
+
+	// breaker tracks the health of the agent to adapt the payload size, and
+	// drops the payloads without sending them while the agent keeps failing.
+	breaker *circuitBreaker
+
 	tracesQueued uint32
 }
 
 func newAgentTraceWriter(c *config, s *prioritySampler, statsdClient globalinternal.StatsdClient) *agentTraceWriter {
-	return &agentTraceWriter{
+	w := &agentTraceWriter{
 		config:           c,
 		payload:          newPayload(),
 		climit:           make(chan struct{}, concurrentConnectionLimit),
 		prioritySampling: s,
 		statsd:           statsdClient,
+		breaker:          newCircuitBreaker(),
+	}
+	if c.spillDir != "" {
+		q, err := newSpillQueue(c.spillDir, c.spillMaxBytes, statsdClient)
+		if err != nil {
+			log.Warn("Unable to spill trace payloads to %s, payloads which cannot be sent will be dropped: %s", c.spillDir, err.Error())
+		} else {
+			w.spill = q
+			q.start(w.replaySend)
+		}
 	}
+	return w
 }
 
 func (h *agentTraceWriter) add(trace []*Span) {
@@ -71,7 +90,7 @@ func (h *agentTraceWriter) add(trace []*Span) {
 		log.Error("Error encoding msgpack: %s", err.Error())
 	}
 	atomic.AddUint32(&h.tracesQueued, 1) // TODO: This does not differentiate between complete traces and partial chunks
-	if h.payload.size() > payloadSizeLimit {
+	if h.payload.size() > h.breaker.payloadSizeLimit() {
 		h.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:size"}, 1)
 		h.flush()
 	}
@@ -81,6 +100,10 @@ func (h *agentTraceWriter) stop() {
 	h.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:shutdown"}, 1)
 	h.flush()
 	h.wg.Wait()
+	if h.spill != nil {
+		// payloads which are not replayed yet are kept for the next run
+		h.spill.stop()
+	}
 }
 
 // flush will push any currently buffered traces to the server.
@@ -88,6 +111,13 @@ func (h *agentTraceWriter) flush() {
 	if h.payload.itemCount() == 0 {
 		return
 	}
+	if !h.breaker.allow() {
+		// the agent keeps failing: don't start an upload which would pile up
+		oldp := h.payload
+		h.payload = newPayload()
+		h.dropOpenCircuit(oldp)
+		return
+	}
 	h.wg.Add(1)
 	h.climit <- struct{}{}
 	oldp := h.payload
@@ -106,32 +136,122 @@ func (h *agentTraceWriter) flush() {
 			h.wg.Done()
 		}(time.Now())
 
-		var count, size int
-		var err error
-		for attempt := 0; attempt <= h.config.sendRetries; attempt++ {
-			size, count = p.size(), p.itemCount()
-			log.Debug("Attempt to send payload: size: %d traces: %d\n", size, count)
-			var rc io.ReadCloser
-			rc, err = h.config.transport.send(p)
-			if err == nil {
-				log.Debug("sent traces after %d attempts", attempt+1)
-				h.statsd.Count("datadog.tracer.flush_bytes", int64(size), nil, 1)
-				h.statsd.Count("datadog.tracer.flush_traces", int64(count), nil, 1)
-				if err := h.prioritySampling.readRatesJSON(rc); err != nil {
-					h.statsd.Incr("datadog.tracer.decode_error", nil, 1)
-				}
-				return
-			}
+		h.send(p)
+	}(oldp)
+}
 
-			if attempt+1%5 == 0 {
-				log.Error("failure sending traces (attempt %d of %d): %v", attempt+1, h.config.sendRetries+1, err.Error())
+// send sends the payload p to the agent, retrying on failures. Payloads which
+// are too large for the agent are split in two, and the halves sent in turn.
+// The payloads which can't be sent are spilled to disk if enabled, or dropped.
+func (h *agentTraceWriter) send(p *payload) {
+	var count, size int
+	var err error
+	var failure sendFailure
+	for attempt := 0; attempt <= h.config.sendRetries; attempt++ {
+		size, count = p.size(), p.itemCount()
+		log.Debug("Attempt to send payload: size: %d traces: %d\n", size, count)
+		var rc io.ReadCloser
+		sendStart := time.Now()
+		rc, err = h.config.transport.send(p)
+		if err == nil {
+			h.breaker.success(time.Since(sendStart))
+			log.Debug("sent traces after %d attempts", attempt+1)
+			h.statsd.Count("datadog.tracer.flush_bytes", int64(size), nil, 1)
+			h.statsd.Count("datadog.tracer.flush_traces", int64(count), nil, 1)
+			if err := h.prioritySampling.readRatesJSON(rc); err != nil {
+				h.statsd.Incr("datadog.tracer.decode_error", nil, 1)
 			}
-			p.reset()
-			time.Sleep(h.config.retryInterval)
+			if h.spill != nil && h.spill.len() > 0 {
+				// the agent is reachable again
+				h.spill.replaySoon()
+			}
+			return
 		}
-		h.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:send_failed"}, 1)
-		log.Error("lost %d traces: %v", count, err.Error())
-	}(oldp)
+
+		if (attempt+1)%5 == 0 {
+			log.Error("failure sending traces (attempt %d of %d): %v", attempt+1, h.config.sendRetries+1, err.Error())
+		}
+		failure = classifySendError(err)
+		if h.breaker.failure(failure) {
+			h.statsd.Incr("datadog.tracer.circuit_breaker.opened", nil, 1)
+			log.Warn("The agent keeps failing, dropping traces until it recovers: %v", err.Error())
+		}
+		if failure == failureTooLarge || h.breaker.currentState() == breakerOpen {
+			// retrying would fail again, or pile up
+			break
+		}
+		p.reset()
+		retryInterval := h.config.retryInterval
+		if failure == failureOverloaded {
+			// back off exponentially to let the agent recover
+			retryInterval = overloadBackoff(retryInterval, attempt)
+		}
+		time.Sleep(retryInterval)
+	}
+	if failure == failureTooLarge {
+		if first, second, ok := p.split(); ok {
+			log.Debug("payload of %d bytes is too large for the agent, splitting its %d traces", size, count)
+			h.statsd.Incr("datadog.tracer.payload_split", nil, 1)
+			h.send(first)
+			h.send(second)
+			return
+		}
+		h.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:payload_too_large"}, 1)
+		log.Error("lost %d traces: payload of %d bytes is too large for the agent", count, size)
+		return
+	}
+	p.reset()
+	if h.spill != nil {
+		log.Warn("failed to send %d traces, spilling them to disk: %v", count, err.Error())
+		h.spill.enqueue(p)
+		return
+	}
+	h.statsd.Count("datadog.tracer.traces_dropped", int64(count), []string{"reason:send_failed"}, 1)
+	log.Error("lost %d traces: %v", count, err.Error())
+}
+
+// dropOpenCircuit drops the payload p, or spills it to disk if enabled, as the
+// circuit is open.
+func (h *agentTraceWriter) dropOpenCircuit(p *payload) {
+	h.statsd.Count("datadog.tracer.queue.enqueued.traces", int64(atomic.SwapUint32(&h.tracesQueued, 0)), nil, 1)
+	if h.spill != nil {
+		h.spill.enqueue(p)
+	} else {
+		h.statsd.Count("datadog.tracer.traces_dropped", int64(p.itemCount()), []string{"reason:circuit_open"}, 1)
+		log.Debug("Circuit open, dropped %d traces", p.itemCount())
+	}
+	p.clear()
+}
+
+// replaySend sends a payload replayed from the spill queue. Like the other
+// payloads, it isn't sent while the circuit is open, and its outcome is
+// recorded by the circuit breaker, so that the replays probe the agent even if
+// no new traces are sent.
+func (h *agentTraceWriter) replaySend(p *payload) error {
+	if !h.breaker.allow() {
+		return errors.New("circuit open")
+	}
+	start := time.Now()
+	rc, err := h.config.transport.send(p)
+	if err != nil {
+		failure := classifySendError(err)
+		if h.breaker.failure(failure) {
+			h.statsd.Incr("datadog.tracer.circuit_breaker.opened", nil, 1)
+		}
+		if failure == failureTooLarge {
+			h.statsd.Count("datadog.tracer.traces_dropped", int64(p.itemCount()), []string{"reason:payload_too_large"}, 1)
+			log.Error("lost %d spilled traces: payload of %d bytes is too large for the agent", p.itemCount(), p.size())
+			return errSpillDropped
+		}
+		return err
+	}
+	h.breaker.success(time.Since(start))
+	h.statsd.Count("datadog.tracer.flush_bytes", int64(p.size()), nil, 1)
+	h.statsd.Count("datadog.tracer.flush_traces", int64(p.itemCount()), nil, 1)
+	if err := h.prioritySampling.readRatesJSON(rc); err != nil {
+		h.statsd.Incr("datadog.tracer.decode_error", nil, 1)
+	}
+	return nil
 }
 
 // logWriter specifies the output target of the logTraceWriter; replaced in tests.
@@ -469,3 +469,31 @@ func BenchmarkJsonEncodeFloat(b *testing.B) {
 		encodeFloat(bs, float64(1e-9))
 	}
 }
+
+func TestTraceWriterLogSendFailures(t *testing.T) {
+	tp := new(log.RecordLogger)
+	defer log.UseLogger(tp)()
+	p := &failingTransport{failCount: 10, assert: assert.New(t)}
+	c, err := newTestConfig(func(c *config) {
+		c.transport = p
+		c.sendRetries = 4
+		c.retryInterval = time.Millisecond
+	})
+	require.NoError(t, err)
+	h := newAgentTraceWriter(c, nil, &statsdtest.TestStatsdClient{})
+	h.add([]*Span{makeSpan(0)})
+	h.flush()
+	h.wg.Wait()
+	log.Flush()
+
+	// every fifth failed attempt is logged
+	assert.Equal(t, 5, p.sendAttempts)
+	var logged []string
+	for _, l := range tp.Logs() {
+		if strings.Contains(l, "failure sending traces") {
+			logged = append(logged, l)
+		}
+	}
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "attempt 5 of 5")
+}
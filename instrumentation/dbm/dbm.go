@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package dbm provides the building blocks to correlate the commands of the
+// database clients whose commands can't be rewritten by the tracer with
+// Database Monitoring: the comment to set on the commands, and the options of
+// the spans of the commands holding it.
+//
+// It's used by the contrib/** integrations, like the MongoDB ones.
+package dbm
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// KeyTraceInjected is the tag set on the spans whose ID was injected in the
+// comment of their command.
+const KeyTraceInjected = "_dd.dbm_trace_injected"
+
+// Comment returns the comment of the commands run with ctx in mode, holding
+// the tags of service and, in tracer.DBMPropagationModeFull, the trace
+// context. The key-value pairs are encoded like the comments injected in the
+// SQL queries, without the comment delimiters. It returns an empty string when
+// the propagation is disabled.
+func Comment(ctx context.Context, mode tracer.DBMPropagationMode, service string) string {
+	var spanCtx *tracer.SpanContext
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		spanCtx = span.Context()
+	}
+	carrier := tracer.SQLCommentCarrier{Mode: mode, DBServiceName: service}
+	if err := carrier.Inject(spanCtx); err != nil {
+		// this should never happen
+		log.Warn("dbm: failed to inject the command comment: %s", err.Error())
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(carrier.Query, "/*"), "*/")
+}
+
+// Extract returns the span context injected in a comment returned by Comment
+// in tracer.DBMPropagationModeFull. It returns tracer.ErrSpanContextNotFound if
+// the comment holds no trace context.
+func Extract(comment string) (*tracer.SpanContext, error) {
+	carrier := tracer.SQLCommentCarrier{Query: "/*" + comment + "*/"}
+	return carrier.Extract()
+}
+
+// SpanOptions returns the options of the span of a command with comment,
+// using the span ID injected in the comment, if any.
+func SpanOptions(comment string) []tracer.StartSpanOption {
+	if comment == "" {
+		return nil
+	}
+	sctx, err := Extract(comment)
+	if err != nil {
+		return nil
+	}
+	return []tracer.StartSpanOption{
+		tracer.WithSpanID(sctx.SpanID()),
+		tracer.Tag(KeyTraceInjected, true),
+	}
+}
+
+// injectedSpanIDsSize is the number of injected span IDs remembered by
+// InjectedSpans.
+const injectedSpanIDsSize = 1024
+
+// InjectedSpans hands out the span ID injected in a comment to the span of the
+// first command holding the comment only, as the commands following it, like
+// the retries of a failed command, may hold the same comment: their spans
+// would otherwise share its span ID. It remembers the last 1024 span IDs it
+// handed out. It's safe for concurrent use.
+type InjectedSpans struct {
+	mu   sync.Mutex
+	used map[uint64]struct{}
+	ids  [injectedSpanIDsSize]uint64 // the span IDs in used, oldest first
+	next int                         // the index of the oldest span ID in ids
+}
+
+// SpanOptions returns the options of the span of a command with comment, like
+// the function SpanOptions, unless the span ID injected in comment was
+// already handed out.
+func (s *InjectedSpans) SpanOptions(comment string) []tracer.StartSpanOption {
+	if comment == "" {
+		return nil
+	}
+	sctx, err := Extract(comment)
+	if err != nil {
+		return nil
+	}
+	id := sctx.SpanID()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.used[id]; ok {
+		return nil
+	}
+	if s.used == nil {
+		s.used = make(map[uint64]struct{}, injectedSpanIDsSize)
+	}
+	if len(s.used) == injectedSpanIDsSize {
+		delete(s.used, s.ids[s.next])
+	}
+	s.used[id] = struct{}{}
+	s.ids[s.next] = id
+	s.next = (s.next + 1) % injectedSpanIDsSize
+	return []tracer.StartSpanOption{
+		tracer.WithSpanID(id),
+		tracer.Tag(KeyTraceInjected, true),
+	}
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package dbm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func TestComment(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	defer span.Finish()
+
+	t.Run("full", func(t *testing.T) {
+		comment := Comment(ctx, tracer.DBMPropagationModeFull, "mongo-db")
+		assert.Regexp(t, `^dddbs='mongo-db',.*traceparent='00-[0-9a-f]{32}-[0-9a-f]{16}-0[01]'$`, comment)
+
+		sctx, err := Extract(comment)
+		require.NoError(t, err)
+		assert.Equal(t, span.Context().TraceIDLower(), sctx.TraceIDLower())
+		assert.NotZero(t, sctx.SpanID())
+
+		child := tracer.StartSpan("command", SpanOptions(comment)...)
+		assert.Equal(t, sctx.SpanID(), child.Context().SpanID())
+		child.Finish()
+	})
+
+	t.Run("service", func(t *testing.T) {
+		comment := Comment(ctx, tracer.DBMPropagationModeService, "mongo-db")
+		assert.Regexp(t, `^dddbs='mongo-db'`, comment)
+		assert.NotContains(t, comment, "traceparent")
+
+		_, err := Extract(comment)
+		assert.Equal(t, tracer.ErrSpanContextNotFound, err)
+		assert.Empty(t, SpanOptions(comment))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Empty(t, Comment(ctx, tracer.DBMPropagationModeDisabled, "mongo-db"))
+		assert.Empty(t, SpanOptions(""))
+	})
+}
+
+func TestInjectedSpans(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	defer span.Finish()
+
+	var s InjectedSpans
+	comment := Comment(ctx, tracer.DBMPropagationModeFull, "mongo-db")
+	assert.Len(t, s.SpanOptions(comment), 2)
+	// the retries hold the same comment
+	assert.Empty(t, s.SpanOptions(comment))
+	assert.Len(t, s.SpanOptions(Comment(ctx, tracer.DBMPropagationModeFull, "mongo-db")), 2)
+	assert.Empty(t, s.SpanOptions(Comment(ctx, tracer.DBMPropagationModeService, "mongo-db")))
+
+	// the oldest span IDs are forgotten
+	for range injectedSpanIDsSize {
+		s.SpanOptions(Comment(ctx, tracer.DBMPropagationModeFull, "mongo-db"))
+	}
+	assert.Len(t, s.used, injectedSpanIDsSize)
+	assert.Len(t, s.SpanOptions(comment), 2)
+}
@@ -89,6 +89,12 @@ func (i *Instrumentation) OperationName(component Component, opCtx OperationCont
 	}
 }
 
+// NamingSchemaV1 reports whether the spans follow the naming schema v1, for the
+// integrations whose span tags depend on the naming schema.
+func (i *Instrumentation) NamingSchemaV1() bool {
+	return namingschema.GetVersion() == namingschema.SchemaV1
+}
+
 func (i *Instrumentation) Logger() Logger {
 	return i.logger
 }
@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messaging
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+// BatchLimits bounds the batches traced by a ConsumeBatch. A zero or negative
+// limit is no limit.
+type BatchLimits struct {
+	// MaxLinks is the maximum number of links of the span of a batch.
+	MaxLinks int
+	// MaxMessages is the maximum number of messages of a batch. The batch is
+	// finished when it's reached, the next message starting a new batch.
+	MaxMessages int
+	// MaxDuration is the maximum duration of the span of a batch. The batch is
+	// finished when it's reached, even if no message is received.
+	MaxDuration time.Duration
+}
+
+// ConsumeBatch traces the messages received one at a time but processed as a
+// batch, like the messages polled until the offsets are committed, with a
+// single span started like StartBatchConsumeSpan when the first message of the
+// batch is added. The span is named after the consume spans, with a ".batch"
+// suffix, like "kafka.consume.batch". The batch is finished by Finish, or when
+// it reaches its limits. It's safe for concurrent use.
+type ConsumeBatch struct {
+	cfg    Config
+	limits BatchLimits
+	opts   []tracer.StartSpanOption
+
+	mu           sync.Mutex
+	span         *tracer.Span
+	size         int
+	links        int
+	destinations map[string]struct{}
+	timer        *time.Timer
+}
+
+// NewConsumeBatch returns a ConsumeBatch tracing the batches received with cfg
+// within limits, opts being applied to their spans.
+func NewConsumeBatch(cfg Config, limits BatchLimits, opts ...tracer.StartSpanOption) *ConsumeBatch {
+	return &ConsumeBatch{cfg: cfg, limits: limits, opts: opts}
+}
+
+// Add traces msg received with ctx as part of the current batch, starting it
+// from ctx if there's none. The span of the batch is linked to the span which
+// produced msg, unless it already has the maximum number of links.
+func (b *ConsumeBatch) Add(ctx context.Context, msg Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.span == nil {
+		b.start(ctx)
+	}
+	b.size++
+	if msg.Destination != "" {
+		b.destinations[msg.Destination] = struct{}{}
+	}
+	if b.limits.MaxLinks <= 0 || b.links < b.limits.MaxLinks {
+		if sctx, ok := ExtractContext(msg); ok {
+			b.span.AddLink(SpanLink(sctx))
+			b.links++
+		}
+	}
+	if b.limits.MaxMessages > 0 && b.size >= b.limits.MaxMessages {
+		b.finish(nil)
+	}
+}
+
+// Finish finishes the span of the current batch, if any, with err, like the
+// error of the commit which ends it.
+func (b *ConsumeBatch) Finish(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.finish(err)
+}
+
+func (b *ConsumeBatch) start(ctx context.Context) {
+	// the destinations are only known once the batch is finished
+	b.span, _ = startBatchConsumeSpan(ctx, b.cfg, b.cfg.spanName(instrumentation.ComponentConsumer)+".batch", "", nil, b.opts...)
+	b.destinations = make(map[string]struct{})
+	if b.limits.MaxDuration > 0 {
+		span := b.span
+		b.timer = time.AfterFunc(b.limits.MaxDuration, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.span == span {
+				b.finish(nil)
+			}
+		})
+	}
+}
+
+func (b *ConsumeBatch) finish(err error) {
+	if b.span == nil {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	dests := make([]string, 0, len(b.destinations))
+	for d := range b.destinations {
+		dests = append(dests, d)
+	}
+	sort.Strings(dests)
+	switch len(dests) {
+	case 0:
+	case 1:
+		b.span.SetTag(ext.ResourceName, dests[0])
+		b.span.SetTag(ext.MessagingDestinationName, dests[0])
+	default:
+		// the messages of a consumer subscribed to several destinations
+		b.span.SetTag(ext.ResourceName, strings.Join(dests, ","))
+	}
+	b.span.SetTag(ext.MessagingBatchMessageCount, b.size)
+	b.span.Finish(tracer.WithError(err))
+	b.span, b.size, b.links, b.destinations, b.timer = nil, 0, 0, nil, nil
+}
@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func TestConsumeBatch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cfg := Config{System: "kafka", Component: "test"}
+	t.Run("links", func(t *testing.T) {
+		defer mt.Reset()
+		b := NewConsumeBatch(cfg, BatchLimits{MaxLinks: 2}, tracer.Tag("custom", "tag"))
+		var producers []*tracer.Span
+		for range 3 {
+			msg := Message{Destination: "topic", Carrier: tracer.TextMapCarrier{}}
+			span, _ := StartProduceSpan(context.Background(), cfg, msg)
+			span.Finish()
+			producers = append(producers, span)
+			b.Add(context.Background(), msg)
+		}
+		b.Finish(errors.New("commit failed"))
+		// no batch in progress
+		b.Finish(nil)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 4)
+		batch := spans[3]
+		assert.Equal(t, "kafka.consume.batch", batch.OperationName())
+		assert.Equal(t, "topic", batch.Tag(ext.ResourceName))
+		assert.Equal(t, "topic", batch.Tag(ext.MessagingDestinationName))
+		assert.Equal(t, "tag", batch.Tag("custom"))
+		assert.Equal(t, float64(3), batch.Tag(ext.MessagingBatchMessageCount))
+		assert.Equal(t, "commit failed", batch.Tag(ext.ErrorMsg))
+		links := batch.Links()
+		require.Len(t, links, 2)
+		for i, l := range links {
+			assert.Equal(t, producers[i].Context().SpanID(), l.SpanID)
+		}
+	})
+
+	t.Run("destinations", func(t *testing.T) {
+		defer mt.Reset()
+		b := NewConsumeBatch(cfg, BatchLimits{})
+		b.Add(context.Background(), Message{Destination: "b"})
+		b.Add(context.Background(), Message{Destination: "a"})
+		b.Add(context.Background(), Message{Destination: "b"})
+		b.Finish(nil)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "a,b", spans[0].Tag(ext.ResourceName))
+		assert.Nil(t, spans[0].Tag(ext.MessagingDestinationName))
+	})
+
+	t.Run("max-messages", func(t *testing.T) {
+		defer mt.Reset()
+		b := NewConsumeBatch(cfg, BatchLimits{MaxMessages: 2})
+		for range 3 {
+			b.Add(context.Background(), Message{Destination: "topic"})
+		}
+		require.Len(t, mt.FinishedSpans(), 1)
+		b.Finish(nil)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, float64(2), spans[0].Tag(ext.MessagingBatchMessageCount))
+		assert.Equal(t, float64(1), spans[1].Tag(ext.MessagingBatchMessageCount))
+	})
+
+	t.Run("max-duration", func(t *testing.T) {
+		defer mt.Reset()
+		b := NewConsumeBatch(cfg, BatchLimits{MaxDuration: time.Millisecond})
+		b.Add(context.Background(), Message{Destination: "topic"})
+		require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 1 }, time.Second, time.Millisecond)
+		// the next message starts a new batch
+		b.Add(context.Background(), Message{Destination: "topic"})
+		require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, time.Second, time.Millisecond)
+		b.Finish(nil)
+		assert.Len(t, mt.FinishedSpans(), 2)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		defer mt.Reset()
+		b := NewConsumeBatch(cfg, BatchLimits{MaxLinks: 10, MaxMessages: 5})
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 10 {
+					b.Add(context.Background(), Message{Destination: "topic"})
+					b.Finish(nil)
+				}
+			}()
+		}
+		wg.Wait()
+		var n float64
+		for _, s := range mt.FinishedSpans() {
+			n += s.Tag(ext.MessagingBatchMessageCount).(float64)
+		}
+		assert.Equal(t, float64(40), n)
+	})
+}
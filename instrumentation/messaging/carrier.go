@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messaging
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// Carrier gives access to the headers of a message, to propagate the trace
+// context and the Data Streams Monitoring pathway along with it.
+// tracer.TextMapCarrier is a Carrier for the messaging systems whose headers
+// are single-valued strings.
+type Carrier interface {
+	tracer.TextMapReader
+	tracer.TextMapWriter
+}
+
+var (
+	_ Carrier = tracer.TextMapCarrier(nil)
+	_ Carrier = HeadersCarrier(nil)
+	_ Carrier = (*funcCarrier)(nil)
+)
+
+// NewCarrier returns a Carrier reading the headers of a message with forEach
+// and writing them with set. set must replace the previous values of key.
+func NewCarrier(forEach func(handler func(key, val string) error) error, set func(key, val string)) Carrier {
+	return &funcCarrier{forEach: forEach, set: set}
+}
+
+type funcCarrier struct {
+	forEach func(handler func(key, val string) error) error
+	set     func(key, val string)
+}
+
+// ForeachKey implements tracer.TextMapReader.
+func (c *funcCarrier) ForeachKey(handler func(key, val string) error) error {
+	return c.forEach(handler)
+}
+
+// Set implements tracer.TextMapWriter.
+func (c *funcCarrier) Set(key, val string) {
+	c.set(key, val)
+}
+
+// HeadersCarrier is a Carrier for multi-valued and case sensitive headers, like
+// those of NATS messages. Unlike tracer.HTTPHeadersCarrier, it doesn't
+// canonicalize the keys.
+type HeadersCarrier map[string][]string
+
+// ForeachKey implements tracer.TextMapReader. It calls handler with the first
+// value of each key.
+func (c HeadersCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c {
+		if len(vs) == 0 {
+			continue
+		}
+		if err := handler(k, vs[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set implements tracer.TextMapWriter.
+func (c HeadersCarrier) Set(key, val string) {
+	c[key] = []string{val}
+}
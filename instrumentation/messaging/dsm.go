@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messaging
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// SetProduceCheckpoint sets a Data Streams Monitoring checkpoint for msg sent
+// to a destination of the messaging system typ, like "kafka" or "sqs", and
+// injects the resulting pathway into the message carrier. edgeTags are added
+// to the direction, topic and type edge tags.
+//
+// It returns the context holding the pathway, or ctx if Data Streams
+// Monitoring is disabled.
+func SetProduceCheckpoint(ctx context.Context, typ string, msg Message, edgeTags ...string) context.Context {
+	edges := append([]string{"direction:out", "topic:" + msg.Destination, "type:" + typ}, edgeTags...)
+	outCtx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: msg.PayloadSize}, edges...)
+	if !ok {
+		return ctx
+	}
+	if msg.Carrier != nil {
+		datastreams.InjectToBase64Carrier(outCtx, msg.Carrier)
+	}
+	return outCtx
+}
+
+// SetConsumeCheckpoint sets a Data Streams Monitoring checkpoint for msg
+// received from a destination of the messaging system typ, continuing the
+// pathway injected into the message carrier by the producer. edgeTags are
+// added to the direction, topic and type edge tags, for example the consumer
+// group.
+//
+// It returns the context holding the pathway, or ctx if Data Streams
+// Monitoring is disabled.
+func SetConsumeCheckpoint(ctx context.Context, typ string, msg Message, edgeTags ...string) context.Context {
+	if msg.Carrier != nil {
+		ctx = datastreams.ExtractFromBase64Carrier(ctx, msg.Carrier)
+	}
+	edges := append([]string{"direction:in", "topic:" + msg.Destination, "type:" + typ}, edgeTags...)
+	outCtx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: msg.PayloadSize}, edges...)
+	if !ok {
+		return ctx
+	}
+	return outCtx
+}
@@ -0,0 +1,225 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package messaging provides the building blocks to trace the clients of
+// messaging systems, like message brokers and queues: propagating the trace
+// context in the message headers, starting the produce and consume spans
+// following the naming schema, linking the spans of the messages received in
+// batches, and setting the Data Streams Monitoring checkpoints.
+//
+// It's used by the NATS, RabbitMQ and AWS SDK v2 (SQS, SNS and Kinesis)
+// integrations, by the gRPC integration to link the spans of the stream
+// messages, and by the confluent-kafka-go and segmentio/kafka-go integrations
+// to trace the batches of consumed messages, whose other spans are still
+// started by the integrations themselves, like the ones of the IBM/sarama and
+// Pub/Sub integrations. It can be used to trace the clients of messaging
+// systems that aren't supported by the contrib/** integrations.
+package messaging
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/namingschema"
+)
+
+// Config holds the configuration of the spans and checkpoints of a messaging
+// system client.
+type Config struct {
+	// System is the messaging system, like "kafka" or "rabbitmq". It's set as
+	// the messaging.system tag and as the type of the Data Streams Monitoring
+	// checkpoints, and the span names are derived from it.
+	System string
+	// Component is the name of the integration, set as the component tag.
+	Component string
+	// Instr is the instrumentation of a contrib/** integration, which names
+	// the spans and their service. If nil, or if it doesn't name them, they're
+	// named from System following the naming schema.
+	Instr *instrumentation.Instrumentation
+	// ServiceName overrides the service name of the spans.
+	ServiceName string
+	// DataStreams enables the Data Streams Monitoring checkpoints.
+	DataStreams bool
+	// EdgeTags are added to the edge tags of the Data Streams Monitoring
+	// checkpoints, for example the consumer group.
+	EdgeTags []string
+}
+
+// Message describes a message sent or received.
+type Message struct {
+	// Destination is the name of the topic, queue or subject of the message.
+	Destination string
+	// Carrier gives access to the headers of the message. It's nil if the
+	// messaging system doesn't support headers.
+	Carrier Carrier
+	// PayloadSize is the size of the message reported in the Data Streams
+	// Monitoring checkpoints.
+	PayloadSize int64
+}
+
+// spanName returns the name of the spans of the given component, either
+// instrumentation.ComponentProducer or instrumentation.ComponentConsumer.
+func (cfg *Config) spanName(component instrumentation.Component) string {
+	if cfg.Instr != nil {
+		if name := cfg.Instr.OperationName(component, nil); name != "" {
+			return name
+		}
+	}
+	v1 := namingschema.GetVersion() == namingschema.SchemaV1
+	switch {
+	case component == instrumentation.ComponentProducer && v1:
+		return cfg.System + ".send"
+	case component == instrumentation.ComponentProducer:
+		return cfg.System + ".produce"
+	case v1:
+		return cfg.System + ".process"
+	default:
+		return cfg.System + ".consume"
+	}
+}
+
+// serviceName returns the service name of the spans of the given component.
+// Following the naming schema v0, the producers are named after the messaging
+// system while the consumers are part of the service.
+func (cfg *Config) serviceName(component instrumentation.Component) string {
+	if cfg.ServiceName != "" {
+		return cfg.ServiceName
+	}
+	if cfg.Instr != nil {
+		return cfg.Instr.ServiceName(component, nil)
+	}
+	nc := namingschema.GetConfig()
+	if component == instrumentation.ComponentConsumer || nc.NamingSchemaVersion == namingschema.SchemaV1 || nc.RemoveIntegrationServiceNames {
+		return nc.DDService
+	}
+	return cfg.System
+}
+
+func (cfg *Config) spanOptions(component instrumentation.Component, msg Message) []tracer.StartSpanOption {
+	spanType, spanKind := ext.SpanTypeMessageProducer, ext.SpanKindProducer
+	if component == instrumentation.ComponentConsumer {
+		spanType, spanKind = ext.SpanTypeMessageConsumer, ext.SpanKindConsumer
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.serviceName(component)),
+		tracer.SpanType(spanType),
+		tracer.Tag(ext.Component, cfg.Component),
+		tracer.Tag(ext.SpanKind, spanKind),
+		tracer.Tag(ext.MessagingSystem, cfg.System),
+		tracer.Measured(),
+	}
+	if msg.Destination != "" {
+		opts = append(opts,
+			tracer.ResourceName(msg.Destination),
+			tracer.Tag(ext.MessagingDestinationName, msg.Destination),
+		)
+	}
+	return opts
+}
+
+// StartProduceSpan starts the span of msg sent with ctx, and injects its
+// context in the message carrier, along with the Data Streams Monitoring
+// pathway if enabled. The resource name of the span is the destination of the
+// message, opts being applied last to override it or to add tags.
+//
+// It returns the span and the context holding it, which the caller finishes
+// once the message is sent.
+func StartProduceSpan(ctx context.Context, cfg Config, msg Message, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	opts = append(cfg.spanOptions(instrumentation.ComponentProducer, msg), opts...)
+	span, ctx := tracer.StartSpanFromContext(ctx, cfg.spanName(instrumentation.ComponentProducer), opts...)
+	if msg.Carrier == nil {
+		return span, ctx
+	}
+	if err := tracer.Inject(span.Context(), msg.Carrier); err != nil {
+		log.Debug("messaging: failed to inject the span context of %s: %s", cfg.System, err.Error())
+	}
+	if cfg.DataStreams {
+		ctx = SetProduceCheckpoint(ctx, cfg.System, msg, cfg.EdgeTags...)
+	}
+	return span, ctx
+}
+
+// StartConsumeSpan starts the span of msg received with ctx. The span is a
+// child of the span which produced the message if its context is found in the
+// message carrier, and of the span of ctx otherwise. It sets the Data Streams
+// Monitoring checkpoint of the message if enabled. The resource name of the
+// span is the destination of the message, opts being applied last to override
+// it or to add tags.
+//
+// It returns the span and the context holding it, which the caller finishes
+// once the message is processed.
+func StartConsumeSpan(ctx context.Context, cfg Config, msg Message, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	opts = append(cfg.spanOptions(instrumentation.ComponentConsumer, msg), opts...)
+	name := cfg.spanName(instrumentation.ComponentConsumer)
+	var span *tracer.Span
+	if sctx, ok := ExtractContext(msg); ok {
+		span = tracer.StartSpan(name, append(opts, tracer.ChildOf(sctx))...)
+		ctx = tracer.ContextWithSpan(ctx, span)
+	} else {
+		span, ctx = tracer.StartSpanFromContext(ctx, name, opts...)
+	}
+	if cfg.DataStreams {
+		ctx = SetConsumeCheckpoint(ctx, cfg.System, msg, cfg.EdgeTags...)
+	}
+	return span, ctx
+}
+
+// StartBatchConsumeSpan starts the span of msgs received at once with ctx, like
+// the messages returned by a single poll or receive call. The span is a child of
+// the span of ctx, and is linked to the spans which produced the messages whose
+// context is found in their carrier. It sets the Data Streams Monitoring
+// checkpoints of the messages if enabled.
+//
+// The resource name of the span is destination, opts being applied last to
+// override it or to add tags. It returns the span and the context holding it,
+// which the caller finishes once the messages are processed.
+func StartBatchConsumeSpan(ctx context.Context, cfg Config, destination string, msgs []Message, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	return startBatchConsumeSpan(ctx, cfg, cfg.spanName(instrumentation.ComponentConsumer), destination, msgs, opts...)
+}
+
+// startBatchConsumeSpan is StartBatchConsumeSpan, the span being named name.
+func startBatchConsumeSpan(ctx context.Context, cfg Config, name, destination string, msgs []Message, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	opts = append(cfg.spanOptions(instrumentation.ComponentConsumer, Message{Destination: destination}), opts...)
+	span, ctx := tracer.StartSpanFromContext(ctx, name, opts...)
+	for _, msg := range msgs {
+		if sctx, ok := ExtractContext(msg); ok {
+			span.AddLink(SpanLink(sctx))
+		}
+		if cfg.DataStreams {
+			SetConsumeCheckpoint(ctx, cfg.System, msg, cfg.EdgeTags...)
+		}
+	}
+	return span, ctx
+}
+
+// ExtractContext returns the context of the span which produced msg, if it was
+// injected in the message carrier.
+func ExtractContext(msg Message) (*tracer.SpanContext, bool) {
+	if msg.Carrier == nil {
+		return nil, false
+	}
+	sctx, err := tracer.Extract(msg.Carrier)
+	if err != nil {
+		return nil, false
+	}
+	return sctx, true
+}
+
+// SpanLink returns a link to the span of the given context, like the span
+// which produced a message received in a batch.
+func SpanLink(sctx *tracer.SpanContext) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     sctx.TraceIDLower(),
+		TraceIDHigh: sctx.TraceIDUpper(),
+		SpanID:      sctx.SpanID(),
+	}
+	if p, ok := sctx.SamplingPriority(); ok && p > ext.PriorityAutoReject {
+		link.Flags = 1
+	}
+	return link
+}
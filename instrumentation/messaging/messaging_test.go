@@ -0,0 +1,218 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+type header struct {
+	key   string
+	value []byte
+}
+
+// headersCarrier adapts a slice of binary headers, like those of Kafka
+// messages, with NewCarrier.
+func headersCarrier(headers *[]header) Carrier {
+	return NewCarrier(
+		func(handler func(key, val string) error) error {
+			for _, h := range *headers {
+				if err := handler(h.key, string(h.value)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		func(key, val string) {
+			for i, h := range *headers {
+				if h.key == key {
+					(*headers)[i].value = []byte(val)
+					return
+				}
+			}
+			*headers = append(*headers, header{key: key, value: []byte(val)})
+		},
+	)
+}
+
+func TestCarriers(t *testing.T) {
+	var headers []header
+	for name, carrier := range map[string]Carrier{
+		"func":    headersCarrier(&headers),
+		"headers": HeadersCarrier{},
+		"textmap": tracer.TextMapCarrier{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			span := tracer.StartSpan("producer")
+			defer span.Finish()
+			require.NoError(t, tracer.Inject(span.Context(), carrier))
+			// injecting twice replaces the headers
+			require.NoError(t, tracer.Inject(span.Context(), carrier))
+
+			sctx, err := tracer.Extract(carrier)
+			require.NoError(t, err)
+			assert.Equal(t, span.Context().TraceID(), sctx.TraceID())
+			assert.Equal(t, span.Context().SpanID(), sctx.SpanID())
+		})
+	}
+	keys := map[string]bool{}
+	for _, h := range headers {
+		assert.False(t, keys[h.key], "duplicated header %s", h.key)
+		keys[h.key] = true
+	}
+
+	t.Run("headers-case", func(t *testing.T) {
+		c := HeadersCarrier{"X-Key": {"a", "b"}, "x-key": {"c"}, "empty": nil}
+		c.Set("x-key", "d")
+		got := map[string]string{}
+		require.NoError(t, c.ForeachKey(func(key, val string) error {
+			got[key] = val
+			return nil
+		}))
+		assert.Equal(t, map[string]string{"X-Key": "a", "x-key": "d"}, got)
+	})
+}
+
+func TestProduceConsume(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	testutils.SetGlobalServiceName(t, "my-service")
+
+	cfg := Config{System: "nats", Component: "nats-io/nats.go"}
+	carrier := HeadersCarrier{}
+	msg := Message{Destination: "orders", Carrier: carrier, PayloadSize: 42}
+
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	producer, _ := StartProduceSpan(ctx, cfg, msg, tracer.Tag("custom", "tag"))
+	producer.Finish()
+	parent.Finish()
+
+	consumer, cctx := StartConsumeSpan(context.Background(), cfg, msg, tracer.ResourceName("process orders"))
+	s, ok := tracer.SpanFromContext(cctx)
+	require.True(t, ok)
+	assert.Equal(t, consumer, s)
+	consumer.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	ps, cs := spans[0], spans[2]
+
+	assert.Equal(t, "nats.produce", ps.OperationName())
+	assert.Equal(t, "nats", ps.Tag(ext.ServiceName))
+	assert.Equal(t, "orders", ps.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeMessageProducer, ps.Tag(ext.SpanType))
+	assert.Equal(t, ext.SpanKindProducer, ps.Tag(ext.SpanKind))
+	assert.Equal(t, "nats", ps.Tag(ext.MessagingSystem))
+	assert.Equal(t, "orders", ps.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, "nats-io/nats.go", ps.Tag(ext.Component))
+	assert.Equal(t, "tag", ps.Tag("custom"))
+	assert.Equal(t, spans[1].SpanID(), ps.ParentID())
+
+	assert.Equal(t, "nats.consume", cs.OperationName())
+	assert.Equal(t, "my-service", cs.Tag(ext.ServiceName))
+	assert.Equal(t, "process orders", cs.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, cs.Tag(ext.SpanKind))
+	assert.Equal(t, ps.SpanID(), cs.ParentID())
+	assert.Equal(t, ps.TraceID(), cs.TraceID())
+}
+
+func TestNamingSchemaV1(t *testing.T) {
+	t.Setenv("DD_TRACE_SPAN_ATTRIBUTE_SCHEMA", "v1")
+	instrumentation.ReloadConfig()
+	defer instrumentation.ReloadConfig()
+	testutils.SetGlobalServiceName(t, "my-service")
+
+	cfg := Config{System: "rabbitmq"}
+	assert.Equal(t, "rabbitmq.send", cfg.spanName(instrumentation.ComponentProducer))
+	assert.Equal(t, "rabbitmq.process", cfg.spanName(instrumentation.ComponentConsumer))
+	assert.Equal(t, "my-service", cfg.serviceName(instrumentation.ComponentProducer))
+	assert.Equal(t, "my-service", cfg.serviceName(instrumentation.ComponentConsumer))
+
+	cfg.ServiceName = "custom"
+	assert.Equal(t, "custom", cfg.serviceName(instrumentation.ComponentProducer))
+
+	cfg.Instr = instrumentation.Load(instrumentation.PackageIBMSarama)
+	assert.Equal(t, "kafka.send", cfg.spanName(instrumentation.ComponentProducer))
+}
+
+func TestBatchConsume(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cfg := Config{System: "sqs"}
+	var msgs []Message
+	var producers []*tracer.Span
+	for range 2 {
+		msg := Message{Destination: "queue", Carrier: tracer.TextMapCarrier{}}
+		span, _ := StartProduceSpan(context.Background(), cfg, msg)
+		span.Finish()
+		producers = append(producers, span)
+		msgs = append(msgs, msg)
+	}
+	msgs = append(msgs, Message{Destination: "queue"})
+
+	span, _ := StartBatchConsumeSpan(context.Background(), cfg, "queue", msgs)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	links := spans[2].Links()
+	require.Len(t, links, 2)
+	for i, l := range links {
+		assert.Equal(t, producers[i].Context().TraceIDLower(), l.TraceID)
+		assert.Equal(t, producers[i].Context().SpanID(), l.SpanID)
+	}
+}
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cfg := Config{System: "rabbitmq", DataStreams: true, EdgeTags: []string{"exchange:orders"}}
+	msg := Message{Destination: "orders", Carrier: tracer.TextMapCarrier{}, PayloadSize: 10}
+
+	span, pctx := StartProduceSpan(context.Background(), cfg, msg)
+	span.Finish()
+	produced, ok := datastreams.PathwayFromContext(pctx)
+	require.True(t, ok)
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), msg.Carrier))
+	require.True(t, ok)
+	assert.Equal(t, produced.GetHash(), got.GetHash())
+	want, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:orders", "type:rabbitmq", "exchange:orders")
+	wantPathway, _ := datastreams.PathwayFromContext(want)
+	assert.Equal(t, wantPathway.GetHash(), got.GetHash())
+
+	span, cctx := StartConsumeSpan(context.Background(), cfg, msg)
+	span.Finish()
+	consumed, ok := datastreams.PathwayFromContext(cctx)
+	require.True(t, ok)
+	want, _ = tracer.SetDataStreamsCheckpoint(want, "direction:in", "topic:orders", "type:rabbitmq", "exchange:orders")
+	wantPathway, _ = datastreams.PathwayFromContext(want)
+	assert.Equal(t, wantPathway.GetHash(), consumed.GetHash())
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg.DataStreams = false
+		msg := Message{Destination: "orders", Carrier: tracer.TextMapCarrier{}}
+		span, ctx := StartProduceSpan(context.Background(), cfg, msg)
+		span.Finish()
+		_, ok := datastreams.PathwayFromContext(ctx)
+		assert.False(t, ok)
+	})
+}
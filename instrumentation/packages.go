@@ -75,6 +75,8 @@ const (
 	PackageEnvoyProxyGoControlPlane Package = "envoyproxy/go-control-plane"
 	PackageOS                       Package = "os"
 	PackageRedisRueidis             Package = "redis/rueidis"
+	PackageNATSIONATSGo             Package = "nats-io/nats.go"
+	PackageRabbitMQAMQP091Go        Package = "rabbitmq/amqp091-go"
 )
 
 // These packages have been removed in v2, but they are kept here for the transitional version.
@@ -631,6 +633,42 @@ var packages = map[Package]PackageInfo{
 			},
 		},
 	},
+	PackageNATSIONATSGo: {
+		TracedPackage: "github.com/nats-io/nats.go",
+		EnvVarPrefix:  "NATS",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("nats"),
+				buildOpNameV0:      staticName("nats.consume"),
+				buildOpNameV1:      staticName("nats.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("nats"),
+				buildOpNameV0:      staticName("nats.produce"),
+				buildOpNameV1:      staticName("nats.send"),
+			},
+		},
+	},
+	PackageRabbitMQAMQP091Go: {
+		TracedPackage: "github.com/rabbitmq/amqp091-go",
+		EnvVarPrefix:  "RABBITMQ",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("rabbitmq"),
+				buildOpNameV0:      staticName("rabbitmq.consume"),
+				buildOpNameV1:      staticName("rabbitmq.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("rabbitmq"),
+				buildOpNameV0:      staticName("rabbitmq.produce"),
+				buildOpNameV1:      staticName("rabbitmq.send"),
+			},
+		},
+	},
 	PackageOlivereElasticV5: {
 		TracedPackage: "gopkg.in/olivere/elastic.v5",
 		EnvVarPrefix:  "ELASTIC",
@@ -26,6 +26,10 @@ type Config struct {
 	// a DD_PROFILING_EXECUTION_TRACE_PERIOD env is set or this option is true.
 	DisableExecutionTracing bool
 
+	// ProfilerOptions are added to the default options of the profiler of the
+	// test app.
+	ProfilerOptions []profiler.Option
+
 	httpAddr net.Addr
 }
 
@@ -56,7 +60,7 @@ func (c *Config) RunHTTP(handler func() http.Handler) {
 	defer tracer.Stop()
 
 	// Start the profiler
-	if err := profiler.Start(
+	opts := []profiler.Option{
 		profiler.WithPeriod(*periodF),
 		profiler.WithProfileTypes(
 			profiler.CPUProfile,
@@ -65,7 +69,8 @@ func (c *Config) RunHTTP(handler func() http.Handler) {
 			profiler.MutexProfile,
 			profiler.GoroutineProfile,
 		),
-	); err != nil {
+	}
+	if err := profiler.Start(append(opts, c.ProfilerOptions...)...); err != nil {
 		log.Fatalf("failed to start profiler: %s", err.Error())
 	}
 	defer profiler.Stop()
@@ -10,10 +10,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"sync"
 
 	"github.com/DataDog/dd-trace-go/internal/apps/v2"
+	"github.com/DataDog/dd-trace-go/v2/profiler"
 
 	httptrace "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
 )
@@ -34,8 +36,16 @@ func init() {
 }
 
 func main() {
-	// Start app
-	app := apps.Config{}
+	// Start app, detecting the heap leaks over the last 3 heap profiles. The
+	// leak suspects are logged for the scenarios to check them.
+	app := apps.Config{
+		ProfilerOptions: []profiler.Option{
+			profiler.WithLeakDetection(profiler.LeakDetectionConfig{
+				Profiles:   3,
+				OnSuspects: logLeakSuspects,
+			}),
+		},
+	}
 	app.RunHTTP(func() http.Handler {
 		// Setup http routes
 		mux := httptrace.NewServeMux()
@@ -51,6 +61,12 @@ func main() {
 	})
 }
 
+func logLeakSuspects(suspects []profiler.LeakSuspect) {
+	for _, s := range suspects {
+		log.Printf("leak suspect: growth=%dB inuse=%dB stack=%s", s.Growth, s.InuseBytes, s.Stack)
+	}
+}
+
 func LoremHandler(w http.ResponseWriter, _ *http.Request) {
 	parseRequest()
 }
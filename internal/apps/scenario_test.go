@@ -7,6 +7,7 @@ package apps
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,10 +34,13 @@ func TestScenario(t *testing.T) {
 		scenarios := []struct {
 			name      string
 			endpoints []string
+			// leak is a function of the stacks expected to be reported as
+			// leak suspects by the profiler, if any.
+			leak string
 		}{
-			{"goroutine", []string{"/lorem", "/ipsum"}},
-			{"heap", []string{"/lorem", "/dolor"}},
-			{"goroutine-heap", []string{"/lorem", "/sit"}},
+			{"goroutine", []string{"/lorem", "/ipsum"}, ""},
+			{"heap", []string{"/lorem", "/dolor"}, "main.DolorHandler"},
+			{"goroutine-heap", []string{"/lorem", "/sit"}, ""},
 		}
 
 		for _, s := range scenarios {
@@ -44,6 +49,21 @@ func TestScenario(t *testing.T) {
 				process := lc.Launch(t)
 				defer process.Stop(t)
 				wc.HitEndpoints(t, process, s.endpoints...)
+				if s.leak == "" {
+					return
+				}
+				// The app detects the leaks over 3 heap profiles, so the
+				// workload must last more than 3 profiling periods.
+				if wc.TotalDuration <= 3*lc.ProfilePeriod {
+					t.Logf("Not checking the leak suspects, the workload is too short")
+					return
+				}
+				for _, line := range strings.Split(process.Output(), "\n") {
+					if strings.Contains(line, "leak suspect: ") && strings.Contains(line, s.leak) {
+						return
+					}
+				}
+				t.Errorf("%s is not a leak suspect", s.leak)
 			})
 		}
 	})
@@ -253,8 +273,10 @@ func (a *launchConfig) Launch(t *testing.T) (p process) {
 			break
 		}
 	}
-	// Keep draining r to avoid blocking the app
-	go io.Copy(io.Discard, r)
+	// Keep draining r to avoid blocking the app, keeping the output for the
+	// scenarios to check it.
+	p.output = new(syncBuffer)
+	go io.Copy(p.output, r)
 
 	// Check startup succeeded
 	require.True(t, listening, "app failed to start")
@@ -267,6 +289,12 @@ type process struct {
 	HostPort string
 	wait     chan error
 	proc     *exec.Cmd
+	output   *syncBuffer
+}
+
+// Output returns the output of the app after it started listening.
+func (ti *process) Output() string {
+	return ti.output.String()
 }
 
 func (ti *process) Stop(t *testing.T) {
@@ -275,6 +303,24 @@ func (ti *process) Stop(t *testing.T) {
 	require.NoError(t, <-ti.wait)
 }
 
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 func parseEnv[T any](t *testing.T, name string, dst *T, fallback T) {
 	s := os.Getenv(name)
 	if s == "" {
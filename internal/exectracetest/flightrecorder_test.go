@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package exectracetest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"testing"
+	"time"
+
+	exptrace "golang.org/x/exp/trace"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/httpmem"
+	"github.com/DataDog/dd-trace-go/v2/profiler"
+)
+
+// findFile returns the path of the first file with the given name in dir, or
+// an empty string if there is none.
+func findFile(dir, name string) string {
+	var found string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && d.Name() == name {
+			found = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+func TestFlightRecorderSlowSpan(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("execution tracing is already enabled")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("DD_PROFILING_OUTPUT_DIR", dir)
+	s, c := httpmem.ServerAndClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	tracer.Start(tracer.WithHTTPClient(c), tracer.WithLogStartup(false))
+	defer tracer.Stop()
+	err := profiler.Start(
+		profiler.WithHTTPClient(c),
+		profiler.WithLogStartup(false),
+		profiler.WithProfileTypes(),
+		profiler.WithPeriod(time.Hour),
+		profiler.WithFlightRecorder(profiler.FlightRecorderConfig{
+			Threshold:  10 * time.Millisecond,
+			Operations: []string{"slow"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("starting the profiler: %s", err)
+	}
+	defer profiler.Stop()
+
+	// The flight recorder is started asynchronously, and the spans are only
+	// annotated in the execution trace once it is running.
+	deadline := time.Now().Add(5 * time.Second)
+	for !trace.IsEnabled() {
+		if time.Now().After(deadline) {
+			t.Skip("the flight recorder is not supported")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	fast := tracer.StartSpan("fast")
+	fast.Finish()
+	slow := tracer.StartSpan("slow")
+	waste(20 * time.Millisecond)
+	slow.Finish()
+
+	var path string
+	for deadline := time.Now().Add(5 * time.Second); path == ""; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the execution trace of the slow span was not captured")
+		}
+		path = findFile(dir, "go.trace")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := exptrace.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("reading execution trace: %s", err)
+	}
+
+	// The snapshot covers the slow span, which is annotated with its ID.
+	tasks := make(map[exptrace.TaskID]string)
+	var found bool
+	for {
+		ev, err := reader.ReadEvent()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("reading event: %s", err)
+		}
+		switch ev.Kind() {
+		case exptrace.EventTaskBegin:
+			tasks[ev.Task().ID] = ev.Task().Type
+		case exptrace.EventLog:
+			log := ev.Log()
+			if tasks[log.Task] != "slow" || log.Category != "datadog.uint64_span_id" {
+				continue
+			}
+			found = binary.LittleEndian.Uint64([]byte(log.Message)) == slow.Context().SpanID()
+		}
+	}
+	if !found {
+		t.Errorf("the execution trace does not cover the slow span %d", slow.Context().SpanID())
+	}
+}
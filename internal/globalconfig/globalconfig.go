@@ -27,6 +27,8 @@ type config struct {
 	mu            sync.RWMutex
 	analyticsRate float64
 	serviceName   string
+	version       string
+	env           string
 	runtimeID     string
 	headersAsTags *internal.LockMap
 	dogstatsdAddr string
@@ -63,6 +65,34 @@ func SetServiceName(name string) {
 	cfg.serviceName = name
 }
 
+// ServiceVersion returns the version of the application, as configured in the tracer.
+func ServiceVersion() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.version
+}
+
+// SetServiceVersion sets the global version of the application.
+func SetServiceVersion(version string) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.version = version
+}
+
+// Env returns the environment of the application, as configured in the tracer.
+func Env() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.env
+}
+
+// SetEnv sets the global environment of the application.
+func SetEnv(env string) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.env = env
+}
+
 // DogstatsdAddr returns the destination for tracer and contrib statsd clients
 func DogstatsdAddr() string {
 	cfg.mu.RLock()
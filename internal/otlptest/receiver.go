@@ -0,0 +1,230 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package otlptest provides an in-process OpenTelemetry Protocol (OTLP) receiver
+// which can be used in tests to verify what is exported over OTLP/HTTP and OTLP/gRPC.
+package otlptest // import "github.com/DataDog/dd-trace-go/v2/internal/otlptest"
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+)
+
+const (
+	// TracesPath is the URL path on which the receiver accepts OTLP/HTTP trace exports.
+	TracesPath = "/v1/traces"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// Receiver is an in-process OTLP receiver. It accepts trace export requests over
+// OTLP/HTTP (protobuf and JSON encodings) and OTLP/gRPC, and records them so that
+// tests can inspect what was exported.
+type Receiver struct {
+	ptraceotlp.UnimplementedGRPCServer
+
+	mu         sync.Mutex
+	traces     []ptrace.Traces
+	headers    []http.Header
+	statusCode int
+	updated    chan struct{}
+
+	httpSrv *httptest.Server
+	grpcSrv *grpc.Server
+	grpcLis net.Listener
+}
+
+// NewReceiver starts a new Receiver listening for OTLP/HTTP and OTLP/gRPC requests
+// on random local ports. The receiver is stopped when the test finishes.
+func NewReceiver(t testing.TB) *Receiver {
+	t.Helper()
+	r := &Receiver{
+		statusCode: http.StatusOK,
+		updated:    make(chan struct{}),
+	}
+	r.httpSrv = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		r.httpSrv.Close()
+		t.Fatalf("otlptest: unable to listen for gRPC: %v", err)
+	}
+	r.grpcLis = lis
+	r.grpcSrv = grpc.NewServer()
+	ptraceotlp.RegisterGRPCServer(r.grpcSrv, r)
+	go r.grpcSrv.Serve(lis)
+
+	t.Cleanup(r.Close)
+	return r
+}
+
+// HTTPEndpoint returns the base URL of the OTLP/HTTP receiver, e.g. "http://127.0.0.1:4318".
+func (r *Receiver) HTTPEndpoint() string {
+	return r.httpSrv.URL
+}
+
+// GRPCEndpoint returns the URL of the OTLP/gRPC receiver, e.g. "grpc://127.0.0.1:4317".
+func (r *Receiver) GRPCEndpoint() string {
+	return "grpc://" + r.grpcLis.Addr().String()
+}
+
+// SetStatusCode sets the HTTP status code returned by the OTLP/HTTP receiver.
+// Requests answered with a status code other than 200 are not recorded.
+func (r *Receiver) SetStatusCode(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statusCode = code
+}
+
+// Export implements ptraceotlp.GRPCServer.
+func (r *Receiver) Export(_ context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	r.record(req.Traces(), nil)
+	return ptraceotlp.NewExportResponse(), nil
+}
+
+func (r *Receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || req.URL.Path != TracesPath {
+		http.NotFound(w, req)
+		return
+	}
+	r.mu.Lock()
+	code := r.statusCode
+	r.mu.Unlock()
+	if code != http.StatusOK {
+		w.WriteHeader(code)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ereq := ptraceotlp.NewExportRequest()
+	ct := req.Header.Get("Content-Type")
+	switch ct {
+	case contentTypeProtobuf:
+		err = ereq.UnmarshalProto(body)
+	case contentTypeJSON:
+		err = ereq.UnmarshalJSON(body)
+	default:
+		http.Error(w, "unsupported content type "+ct, http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.record(ereq.Traces(), req.Header.Clone())
+
+	var resp []byte
+	if ct == contentTypeJSON {
+		resp, err = ptraceotlp.NewExportResponse().MarshalJSON()
+	} else {
+		resp, err = ptraceotlp.NewExportResponse().MarshalProto()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Write(resp)
+}
+
+func (r *Receiver) record(td ptrace.Traces, h http.Header) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, td)
+	if h != nil {
+		r.headers = append(r.headers, h)
+	}
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// Traces returns all the trace export requests received so far.
+func (r *Receiver) Traces() []ptrace.Traces {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ptrace.Traces(nil), r.traces...)
+}
+
+// Headers returns the headers of all the OTLP/HTTP requests received so far.
+func (r *Receiver) Headers() []http.Header {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]http.Header(nil), r.headers...)
+}
+
+// Spans returns all the spans received so far, flattened across requests,
+// resources and scopes.
+func (r *Receiver) Spans() []ptrace.Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return spans(r.traces)
+}
+
+// WaitForSpans waits until at least n spans have been received or the timeout
+// expires, and returns the spans received so far. The test fails on timeout.
+func (r *Receiver) WaitForSpans(t testing.TB, n int, timeout time.Duration) []ptrace.Span {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		got := spans(r.traces)
+		updated := r.updated
+		r.mu.Unlock()
+		if len(got) >= n {
+			return got
+		}
+		select {
+		case <-updated:
+		case <-deadline:
+			t.Fatalf("otlptest: timed out waiting for %d spans, got %d", n, len(got))
+			return got
+		}
+	}
+}
+
+// Reset discards everything that was received so far.
+func (r *Receiver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = nil
+	r.headers = nil
+}
+
+// Close stops the receiver.
+func (r *Receiver) Close() {
+	r.httpSrv.Close()
+	r.grpcSrv.Stop()
+}
+
+func spans(traces []ptrace.Traces) []ptrace.Span {
+	var out []ptrace.Span
+	for _, td := range traces {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			sss := rss.At(i).ScopeSpans()
+			for j := 0; j < sss.Len(); j++ {
+				ss := sss.At(j).Spans()
+				for k := 0; k < ss.Len(); k++ {
+					out = append(out, ss.At(k))
+				}
+			}
+		}
+	}
+	return out
+}
@@ -42,4 +42,7 @@ const (
 	// RemoteDynamicRule specifies that the span was sampled by a rule configured by Datadog
 	// Dynamic Sampling.
 	RemoteDynamicRule SamplerName = 12
+	// TailSampling specifies that the trace was kept by a tail sampling rule,
+	// after all of its spans had finished.
+	TailSampling SamplerName = 13
 )
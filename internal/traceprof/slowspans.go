@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package traceprof
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SlowSpan describes a span which finished after more than the threshold of
+// the slow span handler, see SetSlowSpanHandler.
+type SlowSpan struct {
+	Operation string
+	Resource  string
+	TraceID   string // hex encoded 128-bit trace ID
+	SpanID    uint64
+	Duration  time.Duration
+}
+
+type slowSpanHandler struct {
+	threshold time.Duration
+	fn        func(SlowSpan)
+}
+
+// slowSpans is the handler shared between the profiler, which sets it, and
+// the tracer, which calls it.
+var slowSpans atomic.Pointer[slowSpanHandler]
+
+// SetSlowSpanHandler makes the tracer call fn for each span lasting longer
+// than threshold, when it finishes. fn is called with the span locked and
+// must not block. A nil fn removes the handler.
+func SetSlowSpanHandler(threshold time.Duration, fn func(SlowSpan)) {
+	if fn == nil {
+		slowSpans.Store(nil)
+		return
+	}
+	slowSpans.Store(&slowSpanHandler{threshold: threshold, fn: fn})
+}
+
+// SlowSpanHandler returns the threshold and function set with
+// SetSlowSpanHandler, or a nil function if there is none.
+func SlowSpanHandler() (time.Duration, func(SlowSpan)) {
+	h := slowSpans.Load()
+	if h == nil {
+		return 0, nil
+	}
+	return h.threshold, h.fn
+}
@@ -50,12 +50,24 @@ The code is laid out in the following files:
 	* upload.go: implements uploading a batch of profiles to our agent's
 	  backend proxy, including bundling them together in the required
 	  multi-part form layout and adding required metadata such as tags.
+	* exporter.go: implements the Exporter interface used instead of the
+	  upload when configured with WithExporter, and the exporters writing
+	  the batches to a directory or keeping them in memory.
 	* options.go: implements configuration logic, including default values
 	  and functional options which are passed to profiler.Start.
 	* telemetry.go: sends an instrumentation telemetry message containing
 	  profiler configuration when profiling is started.
 	* metrics.go: collects some runtime metrics (GC-related) which are
 	  included in the metrics.json attachment for each profile upload.
+	* triggers.go: checks the triggers configured with WithTriggers, and
+	  captures an extra batch of profiles when one of them detects an
+	  anomaly, outside of the periodic collection.
+	* leaks.go: detects heap leaks by comparing the in-use heap by call
+	  stack of successive heap profiles, when enabled with
+	  WithLeakDetection.
+	* flightrecorder.go: keeps the recent execution trace in memory with
+	  the flight recorder of the Go runtime, and captures it when a span
+	  configured with WithFlightRecorder is slow.
 
 The code is tested in the "*_test.go" files. The profiler implementations
 themselves are in the Go standard library, and are tested for correctness there.
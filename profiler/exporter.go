@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An Exporter exports the batches of profiles collected by the profiler,
+// instead of uploading them to Datadog. See WithExporter.
+type Exporter interface {
+	// Export exports the batch. The context is canceled after the upload
+	// timeout, see WithUploadTimeout, or when the profiler is stopped.
+	// Export is called from a single goroutine.
+	Export(ctx context.Context, bat Batch) error
+}
+
+// A Batch is a set of profiles collected at roughly the same time, which the
+// Datadog UI calls a profile.
+type Batch struct {
+	// Seq is the sequence number of the batch since the profiler started.
+	Seq uint64
+	// Start and End delimit the period covered by the profiles.
+	Start, End time.Time
+	// Tags are the tags of the batch, including the tags of the profiler
+	// and the service, env, version and host tags.
+	Tags []string
+	// Attachments are the profiles of the batch.
+	Attachments []Attachment
+	// LeakSuspects are the call stacks suspected to leak heap memory at the
+	// end of the batch, see WithLeakDetection.
+	LeakSuspects []LeakSuspect
+	// Event is the JSON encoded event describing the batch, as uploaded to
+	// Datadog along with the attachments.
+	Event []byte
+}
+
+// An Attachment is a profile of a batch.
+type Attachment struct {
+	// Name is the file name of the profile, like "cpu.pprof".
+	Name string
+	// Data is the encoded profile.
+	Data []byte
+}
+
+// export exports the batch with the exporter of the configuration.
+func (p *profiler) export(bat batch) error {
+	event := newUploadEvent(bat, p.cfg)
+	b := Batch{
+		Seq:          bat.seq,
+		Start:        bat.start,
+		End:          bat.end,
+		Tags:         strings.Split(event.Tags, ","),
+		LeakSuspects: bat.leakSuspects,
+	}
+	for _, prof := range bat.profiles {
+		event.Attachments = append(event.Attachments, prof.name)
+		b.Attachments = append(b.Attachments, Attachment{Name: prof.name, Data: prof.data})
+	}
+	var err error
+	if b.Event, err = json.Marshal(event); err != nil {
+		return err
+	}
+	ctx, cancel := p.uploadContext()
+	defer cancel()
+	if err := p.cfg.exporter.Export(ctx, b); err != nil {
+		p.cfg.statsd.Count("datadog.profiling.go.upload_error", 1, nil, 1)
+		return err
+	}
+	p.cfg.statsd.Count("datadog.profiling.go.upload_success", 1, nil, 1)
+	return nil
+}
+
+// dirExporterLayout is the layout of the time in the names of the directories
+// written by the DirExporter, in the basic ISO 8601 format in UTC.
+const dirExporterLayout = "20060102T150405Z"
+
+// DirExporter is an Exporter writing each batch of profiles to a directory,
+// named after the end time and sequence number of the batch, holding the
+// profiles and the event describing the batch in "event.json". Only the
+// directories of the most recent batches are kept.
+type DirExporter struct {
+	dir        string
+	maxBatches int
+}
+
+// NewDirExporter returns a DirExporter writing the batches of profiles to
+// subdirectories of dir, keeping only the maxBatches most recent ones. All of
+// them are kept if maxBatches is 0.
+func NewDirExporter(dir string, maxBatches int) *DirExporter {
+	return &DirExporter{dir: dir, maxBatches: maxBatches}
+}
+
+// Export implements Exporter.
+func (e *DirExporter) Export(_ context.Context, bat Batch) error {
+	name := fmt.Sprintf("%s-%06d", bat.End.UTC().Format(dirExporterLayout), bat.Seq)
+	dir := filepath.Join(e.dir, name)
+	// 0755 is what mkdir does, should be reasonable for the use cases here.
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, a := range bat.Attachments {
+		// 0644 is what touch does, should be reasonable for the use cases here.
+		if err := os.WriteFile(filepath.Join(dir, a.Name), a.Data, 0644); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "event.json"), bat.Event, 0644); err != nil {
+		return err
+	}
+	return e.rotate()
+}
+
+// rotate removes the directories of the oldest batches, if there are more
+// than maxBatches.
+func (e *DirExporter) rotate() error {
+	if e.maxBatches <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return err
+	}
+	var batches []string
+	for _, entry := range entries {
+		// Only remove the directories written by the exporter.
+		ts, _, ok := strings.Cut(entry.Name(), "-")
+		if _, err := time.Parse(dirExporterLayout, ts); entry.IsDir() && ok && err == nil {
+			batches = append(batches, entry.Name())
+		}
+	}
+	if len(batches) <= e.maxBatches {
+		return nil
+	}
+	// The names sort in chronological order.
+	slices.Sort(batches)
+	for _, name := range batches[:len(batches)-e.maxBatches] {
+		if err := os.RemoveAll(filepath.Join(e.dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryExporter is an Exporter keeping the batches of profiles in memory, to
+// inspect them in tests. Its zero value is ready to use.
+type MemoryExporter struct {
+	mu      sync.Mutex
+	batches []Batch
+}
+
+// NewMemoryExporter returns a new MemoryExporter.
+func NewMemoryExporter() *MemoryExporter {
+	return new(MemoryExporter)
+}
+
+// Export implements Exporter.
+func (e *MemoryExporter) Export(_ context.Context, bat Batch) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, bat)
+	return nil
+}
+
+// Batches returns the batches exported so far, oldest first.
+func (e *MemoryExporter) Batches() []Batch {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.batches)
+}
+
+// Reset removes the batches exported so far.
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = nil
+}
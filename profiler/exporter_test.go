@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExporter(t *testing.T) {
+	t.Setenv("DD_PROFILING_EXECUTION_TRACE_ENABLED", "false")
+	exporter := NewMemoryExporter()
+	err := Start(
+		WithExporter(exporter),
+		WithProfileTypes(HeapProfile),
+		WithPeriod(10*time.Millisecond),
+		WithService("my-service"),
+		// uploading to the agent would fail
+		WithAgentAddr("invalid:1"),
+	)
+	require.NoError(t, err)
+	defer Stop()
+
+	require.Eventually(t, func() bool { return len(exporter.Batches()) > 1 }, 5*time.Second, 10*time.Millisecond)
+	for i, bat := range exporter.Batches()[:2] {
+		assert.Equal(t, uint64(i), bat.Seq)
+		assert.True(t, bat.End.After(bat.Start))
+		assert.Contains(t, bat.Tags, "service:my-service")
+		assert.Contains(t, bat.Tags, "runtime:go")
+		require.Len(t, bat.Attachments, 2)
+		assert.ElementsMatch(t, []string{"delta-heap.pprof", "metrics.json"}, []string{bat.Attachments[0].Name, bat.Attachments[1].Name})
+
+		var event uploadEvent
+		require.NoError(t, json.Unmarshal(bat.Event, &event))
+		assert.Equal(t, "go", event.Family)
+		assert.ElementsMatch(t, []string{"delta-heap.pprof", "metrics.json"}, event.Attachments)
+		assert.Contains(t, event.Tags, "service:my-service")
+		assert.Equal(t, true, event.Info.Profiler.Settings["custom_exporter"])
+	}
+
+	exporter.Reset()
+	assert.Empty(t, exporter.Batches())
+}
+
+type errExporter struct{}
+
+func (errExporter) Export(context.Context, Batch) error { return errors.New("export failed") }
+
+func TestExportError(t *testing.T) {
+	p, err := newProfiler(WithExporter(errExporter{}))
+	require.NoError(t, err)
+	assert.EqualError(t, p.uploadFunc(batch{}), "export failed")
+}
+
+func TestDirExporter(t *testing.T) {
+	dir := t.TempDir()
+	// not written by the exporter, so never removed
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "other"), 0755))
+
+	exporter := NewDirExporter(dir, 2)
+	end := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for seq := range 3 {
+		err := exporter.Export(context.Background(), Batch{
+			Seq:         uint64(seq),
+			End:         end.Add(time.Duration(seq) * time.Minute),
+			Attachments: []Attachment{{Name: "cpu.pprof", Data: []byte("cpu")}},
+			Event:       []byte(`{"family":"go"}`),
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"20250601T120100Z-000001", "20250601T120200Z-000002", "other"}, names)
+
+	data, err := os.ReadFile(filepath.Join(dir, "20250601T120200Z-000002", "cpu.pprof"))
+	require.NoError(t, err)
+	assert.Equal(t, "cpu", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "20250601T120200Z-000002", "event.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"family":"go"}`, string(data))
+}
@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+)
+
+const (
+	// DefaultFlightRecorderWindow specifies the default minimum length of the
+	// execution trace kept in memory by the flight recorder.
+	DefaultFlightRecorderWindow = 10 * time.Second
+
+	// DefaultFlightRecorderCooldown specifies the default minimum time between
+	// two execution traces captured by the flight recorder.
+	DefaultFlightRecorderCooldown = time.Minute
+)
+
+// FlightRecorderConfig configures the capture of execution traces of slow
+// spans, see WithFlightRecorder.
+type FlightRecorderConfig struct {
+	// Threshold is the duration above which a finished span is slow.
+	Threshold time.Duration
+	// Operations are patterns of the operation names of the spans to watch,
+	// where '*' matches any string and '?' any character, as in sampling
+	// rules. All the operations are watched if it is empty.
+	Operations []string
+	// Resources are patterns of the resource names of the spans to watch, as
+	// for Operations. All the resources are watched if it is empty.
+	Resources []string
+	// Window is the minimum length of the execution trace kept in memory. It
+	// defaults to DefaultFlightRecorderWindow.
+	Window time.Duration
+	// MaxBytes is an upper bound on the size of the execution trace kept in
+	// memory, which takes precedence over Window. It defaults to the size
+	// limit of the periodic execution traces.
+	MaxBytes uint64
+	// Cooldown is the minimum time between two captured execution traces. It
+	// defaults to DefaultFlightRecorderCooldown.
+	Cooldown time.Duration
+}
+
+// flightRecorder is implemented by runtime/trace.FlightRecorder, available as
+// of Go 1.25.
+type flightRecorder interface {
+	Start() error
+	Stop()
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// slowSpanMatcher reports whether the slow spans are watched by the flight
+// recorder.
+type slowSpanMatcher struct {
+	operations []*regexp.Regexp
+	resources  []*regexp.Regexp
+}
+
+func newSlowSpanMatcher(cfg *FlightRecorderConfig) slowSpanMatcher {
+	var m slowSpanMatcher
+	for _, pattern := range cfg.Operations {
+		m.operations = append(m.operations, globRegexp(pattern))
+	}
+	for _, pattern := range cfg.Resources {
+		m.resources = append(m.resources, globRegexp(pattern))
+	}
+	return m
+}
+
+func (m slowSpanMatcher) match(s traceprof.SlowSpan) bool {
+	return matchAny(m.operations, s.Operation) && matchAny(m.resources, s.Resource)
+}
+
+// matchAny reports whether s matches any of the patterns, or if there are none.
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp compiles the glob pattern into a regular expression matching an
+// entire string, case insensitively, in which only '?' and '*' are special.
+func globRegexp(pattern string) *regexp.Regexp {
+	pattern = regexp.QuoteMeta(pattern)
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + pattern + "$")
+}
+
+// runFlightRecorder keeps the recent execution trace of the program in memory
+// until the profiler is stopped, and captures it each time a watched span is
+// slow, at most once per cooldown.
+func (p *profiler) runFlightRecorder() {
+	cfg := p.cfg.flightRecorder
+	window := cmp.Or(cfg.Window, DefaultFlightRecorderWindow)
+	fr, err := newFlightRecorder(window, cmp.Or(cfg.MaxBytes, uint64(p.cfg.traceConfig.Limit)))
+	if err != nil {
+		log.Warn("profiler: flight recorder disabled: %s", err.Error())
+		return
+	}
+	if err := fr.Start(); err != nil {
+		log.Error("profiler: failed to start the flight recorder: %s", err.Error())
+		return
+	}
+	defer fr.Stop()
+
+	// The handler is called when the spans finish, so it can't block: the
+	// slow spans finishing while an execution trace is being captured are
+	// dropped, like the ones finishing during the cooldown.
+	slow := make(chan traceprof.SlowSpan, 1)
+	matcher := newSlowSpanMatcher(cfg)
+	traceprof.SetSlowSpanHandler(cfg.Threshold, func(s traceprof.SlowSpan) {
+		if !matcher.match(s) {
+			return
+		}
+		select {
+		case slow <- s:
+		default:
+		}
+	})
+	defer traceprof.SetSlowSpanHandler(0, nil)
+
+	cooldown := cmp.Or(cfg.Cooldown, DefaultFlightRecorderCooldown)
+	var lastCaptured time.Time
+	for {
+		var span traceprof.SlowSpan
+		select {
+		case <-p.exit:
+			return
+		case span = <-slow:
+		}
+		if !lastCaptured.IsZero() && time.Since(lastCaptured) < cooldown {
+			continue
+		}
+		lastCaptured = time.Now()
+		log.Debug("profiler: span %d lasted %s, capturing the execution trace", span.SpanID, span.Duration)
+		p.cfg.statsd.Count("datadog.profiling.go.flight_recorder", 1, p.cfg.tags.Slice(), 1)
+		if err := p.collectFlightRecorder(fr, window, span); err != nil {
+			log.Error("Error getting the execution trace of span %d: %v; skipping.", span.SpanID, err.Error())
+			p.cfg.statsd.Count("datadog.profiling.go.collect_error", 1, append(p.cfg.tags.Slice(), executionTrace.Tag(), "trigger:slow_span"), 1)
+		}
+	}
+}
+
+// collectFlightRecorder captures the execution trace kept by the flight
+// recorder fr and enqueues it for upload, tagged with the IDs of the slow span
+// which triggered the capture.
+func (p *profiler) collectFlightRecorder(fr flightRecorder, window time.Duration, span traceprof.SlowSpan) error {
+	var buf bytes.Buffer
+	compressor, err := p.newTriggerCompressor(executionTrace, &buf)
+	if err != nil {
+		return err
+	}
+	end := now()
+	_, err = fr.WriteTo(compressor)
+	if err = cmp.Or(err, compressor.Close()); err != nil {
+		return err
+	}
+	bat := batch{
+		seq:   p.seq.Add(1) - 1,
+		host:  p.cfg.hostname,
+		start: end.Add(-window),
+		end:   end,
+		extraTags: []string{
+			"trigger:slow_span",
+			"trace_id:" + span.TraceID,
+			"span_id:" + strconv.FormatUint(span.SpanID, 10),
+			"go_execution_traced:yes",
+			fmt.Sprintf("_dd.profiler.go_execution_trace_enabled:%v", p.cfg.traceConfig.Enabled),
+			pgoTag(),
+		},
+		customAttributes: p.cfg.customProfilerLabels,
+	}
+	bat.addProfile(&profile{name: executionTrace.Filename(), pt: executionTrace, data: buf.Bytes()})
+	p.enqueueUpload(bat)
+	return nil
+}
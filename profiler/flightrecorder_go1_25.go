@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build go1.25
+
+package profiler
+
+import (
+	"runtime/trace"
+	"time"
+)
+
+func newFlightRecorder(window time.Duration, maxBytes uint64) (flightRecorder, error) {
+	return trace.NewFlightRecorder(trace.FlightRecorderConfig{
+		MinAge:   window,
+		MaxBytes: maxBytes,
+	}), nil
+}
@@ -0,0 +1,17 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build !go1.25
+
+package profiler
+
+import (
+	"errors"
+	"time"
+)
+
+func newFlightRecorder(_ time.Duration, _ uint64) (flightRecorder, error) {
+	return nil, errors.New("the flight recorder requires Go 1.25 or later")
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+)
+
+func TestSlowSpanMatcher(t *testing.T) {
+	m := newSlowSpanMatcher(&FlightRecorderConfig{
+		Operations: []string{"http.*", "grpc.server"},
+		Resources:  []string{"GET /users/?"},
+	})
+	assert.True(t, m.match(traceprof.SlowSpan{Operation: "http.request", Resource: "GET /users/1"}))
+	assert.True(t, m.match(traceprof.SlowSpan{Operation: "GRPC.server", Resource: "get /users/2"}))
+	assert.False(t, m.match(traceprof.SlowSpan{Operation: "grpc.client", Resource: "GET /users/1"}))
+	assert.False(t, m.match(traceprof.SlowSpan{Operation: "http.request", Resource: "GET /users/10"}))
+	assert.False(t, m.match(traceprof.SlowSpan{Operation: "http.request", Resource: "GET /users.1"}), "only ? and * are special")
+
+	m = newSlowSpanMatcher(&FlightRecorderConfig{})
+	assert.True(t, m.match(traceprof.SlowSpan{Operation: "any", Resource: "any"}))
+}
+
+func TestWithFlightRecorder(t *testing.T) {
+	if _, err := newFlightRecorder(0, 0); err != nil {
+		t.Skip(err)
+	}
+	t.Run("invalid", func(t *testing.T) {
+		err := Start(WithFlightRecorder(FlightRecorderConfig{}))
+		assert.ErrorContains(t, err, "invalid flight recorder threshold")
+	})
+
+	profiles := startTestProfiler(t, 10,
+		WithProfileTypes(),
+		WithPeriod(time.Hour),
+		WithFlightRecorder(FlightRecorderConfig{
+			Threshold:  time.Second,
+			Operations: []string{"http.request"},
+			Cooldown:   time.Hour,
+		}),
+	)
+	var (
+		threshold time.Duration
+		report    func(traceprof.SlowSpan)
+	)
+	require.Eventually(t, func() bool {
+		threshold, report = traceprof.SlowSpanHandler()
+		return report != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, time.Second, threshold)
+
+	report(traceprof.SlowSpan{Operation: "db.query", TraceID: "1", SpanID: 1, Duration: 2 * time.Second})
+	report(traceprof.SlowSpan{Operation: "http.request", TraceID: "68e3a3a200000000000000000000abcd", SpanID: 2, Duration: 2 * time.Second})
+	prof := <-profiles
+	assert.Contains(t, prof.tags, "trigger:slow_span")
+	assert.Contains(t, prof.tags, "trace_id:68e3a3a200000000000000000000abcd")
+	assert.Contains(t, prof.tags, "span_id:2")
+	assert.Equal(t, []string{"go.trace"}, prof.event.Attachments)
+	assert.NotEmpty(t, prof.attachments["go.trace"])
+
+	// no other execution trace is captured during the cooldown
+	report(traceprof.SlowSpan{Operation: "http.request", TraceID: "3", SpanID: 3, Duration: 2 * time.Second})
+	select {
+	case prof := <-profiles:
+		t.Fatalf("unexpected batch during the cooldown: %v", prof.tags)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	Stop()
+	_, report = traceprof.SlowSpanHandler()
+	assert.Nil(t, report, "the handler is removed when the profiler stops")
+}
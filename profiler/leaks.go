@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	pprofile "github.com/google/pprof/profile"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+)
+
+const (
+	// DefaultLeakDetectionProfiles specifies the default number of successive
+	// heap profiles over which the in-use heap of a call stack must grow for
+	// it to be a leak suspect.
+	DefaultLeakDetectionProfiles = 5
+
+	// DefaultLeakMinGrowth specifies the default minimum growth, in bytes, of
+	// the in-use heap of a call stack for it to be a leak suspect.
+	DefaultLeakMinGrowth = 1 << 20
+
+	// DefaultLeakMaxSuspects specifies the default maximum number of leak
+	// suspects reported per profiling period.
+	DefaultLeakMaxSuspects = 10
+)
+
+// LeakDetectionConfig configures the detection of heap leaks, see
+// WithLeakDetection.
+type LeakDetectionConfig struct {
+	// Profiles is the number of successive heap profiles over which the
+	// in-use heap of a call stack must grow. It defaults to
+	// DefaultLeakDetectionProfiles, and can't be less than 2.
+	Profiles int
+	// MinGrowth is the minimum growth, in bytes, of the in-use heap of a call
+	// stack over the profiles. It defaults to DefaultLeakMinGrowth.
+	MinGrowth int64
+	// MaxSuspects is the maximum number of leak suspects reported per
+	// profiling period, the ones growing the most. It defaults to
+	// DefaultLeakMaxSuspects.
+	MaxSuspects int
+	// OnSuspects is called with the leak suspects found at the end of each
+	// profiling period, if any. It is called from the profiler's collection
+	// goroutine, so it should return quickly.
+	OnSuspects func([]LeakSuspect)
+}
+
+// A LeakSuspect is a call stack whose in-use heap kept growing over the last
+// heap profiles, which may indicate a memory leak.
+type LeakSuspect struct {
+	// Stack is the call stack of the allocations, from the root to the
+	// allocating function, with the function names separated by ';'.
+	Stack string `json:"stack"`
+	// InuseBytes is the size of the in-use heap allocated by the stack in the
+	// last heap profile.
+	InuseBytes int64 `json:"inuse_bytes"`
+	// InuseObjects is the number of in-use objects allocated by the stack in
+	// the last heap profile.
+	InuseObjects int64 `json:"inuse_objects"`
+	// Growth is the growth, in bytes, of the in-use heap allocated by the
+	// stack over the compared heap profiles.
+	Growth int64 `json:"growth_bytes"`
+}
+
+// heapUsage is the in-use heap allocated by a call stack.
+type heapUsage struct {
+	bytes, objects int64
+}
+
+// leakDetector keeps the in-use heap by call stack of the last heap profiles,
+// and reports the stacks whose in-use heap grows monotonically. It is only
+// used by the profiler's collection goroutine.
+type leakDetector struct {
+	cfg LeakDetectionConfig
+	// history holds the in-use heap by folded stack of the last heap
+	// profiles, oldest first.
+	history []map[string]heapUsage
+}
+
+func newLeakDetector(cfg LeakDetectionConfig) *leakDetector {
+	cfg.Profiles = max(cmp.Or(cfg.Profiles, DefaultLeakDetectionProfiles), 2)
+	cfg.MinGrowth = cmp.Or(cfg.MinGrowth, DefaultLeakMinGrowth)
+	cfg.MaxSuspects = cmp.Or(cfg.MaxSuspects, DefaultLeakMaxSuspects)
+	return &leakDetector{cfg: cfg}
+}
+
+// add adds the heap profile encoded in data to the history, and returns the
+// leak suspects if there are enough profiles to compare.
+func (d *leakDetector) add(data []byte) ([]LeakSuspect, error) {
+	stacks, err := heapStacks(data)
+	if err != nil {
+		return nil, err
+	}
+	d.history = append(d.history, stacks)
+	if n := len(d.history) - d.cfg.Profiles; n > 0 {
+		d.history = slices.Delete(d.history, 0, n)
+	}
+	if len(d.history) < d.cfg.Profiles {
+		return nil, nil
+	}
+
+	var suspects []LeakSuspect
+	first, last := d.history[0], d.history[len(d.history)-1]
+	for stack, usage := range last {
+		growth := usage.bytes - first[stack].bytes
+		if growth < d.cfg.MinGrowth || !d.monotonic(stack) {
+			continue
+		}
+		suspects = append(suspects, LeakSuspect{
+			Stack:        stack,
+			InuseBytes:   usage.bytes,
+			InuseObjects: usage.objects,
+			Growth:       growth,
+		})
+	}
+	slices.SortFunc(suspects, func(a, b LeakSuspect) int {
+		return cmp.Or(cmp.Compare(b.Growth, a.Growth), strings.Compare(a.Stack, b.Stack))
+	})
+	if len(suspects) > d.cfg.MaxSuspects {
+		suspects = suspects[:d.cfg.MaxSuspects]
+	}
+	return suspects, nil
+}
+
+// monotonic reports whether the in-use heap of the stack never decreases over
+// the profiles of the history.
+func (d *leakDetector) monotonic(stack string) bool {
+	for i := 1; i < len(d.history); i++ {
+		prev, ok := d.history[i-1][stack]
+		if !ok || d.history[i][stack].bytes < prev.bytes {
+			return false
+		}
+	}
+	return true
+}
+
+// heapStacks returns the in-use heap by call stack of the heap profile
+// encoded in data, the stacks being folded by function names with
+// pprofutils, so that they are stable across profiles.
+func heapStacks(data []byte) (map[string]heapUsage, error) {
+	prof, err := pprofile.ParseData(data)
+	if err != nil {
+		return nil, err
+	}
+	var folded bytes.Buffer
+	if err := (pprofutils.Protobuf{SampleTypes: true}).Convert(prof, &folded); err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(&folded)
+	scanner.Buffer(nil, 1<<20)
+	if !scanner.Scan() {
+		return nil, cmp.Or(scanner.Err(), fmt.Errorf("empty heap profile"))
+	}
+	sampleTypes := strings.Split(scanner.Text(), " ")
+	bytesIdx := slices.Index(sampleTypes, "inuse_space/bytes")
+	objectsIdx := slices.Index(sampleTypes, "inuse_objects/count")
+	if bytesIdx < 0 || objectsIdx < 0 {
+		return nil, fmt.Errorf("not a heap profile: %v", sampleTypes)
+	}
+	stacks := make(map[string]heapUsage)
+	for scanner.Scan() {
+		// The function names may contain spaces, but the values come last.
+		fields := strings.Split(scanner.Text(), " ")
+		if len(fields) <= len(sampleTypes) {
+			continue
+		}
+		stack := strings.Join(fields[:len(fields)-len(sampleTypes)], " ")
+		values := fields[len(fields)-len(sampleTypes):]
+		b, err := strconv.ParseInt(values[bytesIdx], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		o, err := strconv.ParseInt(values[objectsIdx], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		usage := stacks[stack]
+		usage.bytes += b
+		usage.objects += o
+		stacks[stack] = usage
+	}
+	return stacks, scanner.Err()
+}
+
+// detectLeaks collects a heap profile and returns the leak suspects found by
+// comparing it with the previous ones.
+func (p *profiler) detectLeaks() []LeakSuspect {
+	var buf bytes.Buffer
+	if err := p.lookupProfile("heap", &buf, 0); err != nil {
+		log.Error("Error getting the heap profile for leak detection: %v; skipping.", err.Error())
+		return nil
+	}
+	suspects, err := p.leaks.add(buf.Bytes())
+	if err != nil {
+		log.Error("Error detecting heap leaks: %v; skipping.", err.Error())
+		return nil
+	}
+	if len(suspects) == 0 {
+		return nil
+	}
+	log.Debug("profiler: found %d leak suspects", len(suspects))
+	p.cfg.statsd.Count("datadog.profiling.leak_suspect", int64(len(suspects)), p.cfg.tags.Slice(), 1)
+	if p.cfg.leakDetection.OnSuspects != nil {
+		p.cfg.leakDetection.OnSuspects(slices.Clone(suspects))
+	}
+	return suspects
+}
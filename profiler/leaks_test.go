@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+)
+
+// heapProfile returns the heap profile of the folded text, whose values are
+// the in-use objects and bytes of the stacks.
+func heapProfile(t *testing.T, text string) []byte {
+	t.Helper()
+	p, err := pprofutils.Text{}.Convert(strings.NewReader("inuse_objects/count inuse_space/bytes\n" + text))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestLeakDetector(t *testing.T) {
+	add := func(t *testing.T, d *leakDetector, text string) []LeakSuspect {
+		t.Helper()
+		suspects, err := d.add(heapProfile(t, text))
+		require.NoError(t, err)
+		return suspects
+	}
+
+	t.Run("monotonic", func(t *testing.T) {
+		d := newLeakDetector(LeakDetectionConfig{Profiles: 3, MinGrowth: 100})
+		assert.Empty(t, add(t, d, "main;leak 1 100\nmain;spike 1 100\nmain;stable 1 500\n"))
+		assert.Empty(t, add(t, d, "main;leak 2 200\nmain;spike 9 900\nmain;stable 1 500\n"))
+		// the spike decreased, the stable stack didn't grow
+		assert.Equal(t, []LeakSuspect{
+			{Stack: "main;leak", InuseBytes: 400, InuseObjects: 4, Growth: 300},
+		}, add(t, d, "main;leak 4 400\nmain;spike 8 800\nmain;stable 1 500\n"))
+		assert.Equal(t, []LeakSuspect{
+			{Stack: "main;leak", InuseBytes: 500, InuseObjects: 5, Growth: 300},
+		}, add(t, d, "main;leak 5 500\nmain;spike 20 2000\nmain;stable 1 500\n"))
+		// the profile where the spike decreased is dropped
+		assert.Equal(t, []LeakSuspect{
+			{Stack: "main;spike", InuseBytes: 3000, InuseObjects: 30, Growth: 2200},
+			{Stack: "main;leak", InuseBytes: 600, InuseObjects: 6, Growth: 200},
+		}, add(t, d, "main;leak 6 600\nmain;spike 30 3000\nmain;stable 1 500\n"))
+	})
+
+	t.Run("new-stack", func(t *testing.T) {
+		d := newLeakDetector(LeakDetectionConfig{Profiles: 2, MinGrowth: 100})
+		assert.Empty(t, add(t, d, "main;a 1 100\n"))
+		// main;b is missing from the first profile
+		assert.Empty(t, add(t, d, "main;a 1 100\nmain;b 10 1000\n"))
+	})
+
+	t.Run("min-growth", func(t *testing.T) {
+		d := newLeakDetector(LeakDetectionConfig{Profiles: 2})
+		assert.Empty(t, add(t, d, "main;a 1 100\n"))
+		assert.Empty(t, add(t, d, "main;a 2 200\n"))
+		assert.Len(t, add(t, d, "main;a 2 200000000\n"), 1)
+	})
+
+	t.Run("max-suspects", func(t *testing.T) {
+		d := newLeakDetector(LeakDetectionConfig{Profiles: 2, MinGrowth: 1, MaxSuspects: 2})
+		add(t, d, "main;a 1 1\nmain;b 1 1\nmain;c 1 1\n")
+		suspects := add(t, d, "main;a 1 20\nmain;b 1 30\nmain;c 1 10\n")
+		require.Len(t, suspects, 2)
+		assert.Equal(t, "main;b", suspects[0].Stack)
+		assert.Equal(t, "main;a", suspects[1].Stack)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		d := newLeakDetector(LeakDetectionConfig{})
+		_, err := d.add([]byte("not a profile"))
+		assert.Error(t, err)
+		cpu, err := pprofutils.Text{}.Convert(strings.NewReader("samples/count cpu/nanoseconds\nmain;a 1 10\n"))
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, cpu.Write(&buf))
+		_, err = d.add(buf.Bytes())
+		assert.ErrorContains(t, err, "not a heap profile")
+		assert.Empty(t, d.history)
+	})
+}
+
+func TestWithLeakDetection(t *testing.T) {
+	t.Setenv("DD_PROFILING_EXECUTION_TRACE_ENABLED", "false")
+	var statsd statsdtest.TestStatsdClient
+	suspects := make(chan []LeakSuspect, 100)
+	exporter := NewMemoryExporter()
+	p, err := newProfiler(
+		WithExporter(exporter),
+		WithProfileTypes(),
+		WithPeriod(10*time.Millisecond),
+		WithStatsd(&statsd),
+		WithLeakDetection(LeakDetectionConfig{
+			Profiles:   2,
+			MinGrowth:  100,
+			OnSuspects: func(s []LeakSuspect) { suspects <- s },
+		}),
+	)
+	require.NoError(t, err)
+	var inuse atomic.Int64
+	p.testHooks.lookupProfile = func(name string, w io.Writer, _ int) error {
+		assert.Equal(t, "heap", name)
+		// a single object growing by 100 bytes per profile
+		n := inuse.Add(100)
+		_, err := w.Write(heapProfile(t, "main;leak 1 "+strconv.FormatInt(n, 10)+"\n"))
+		return err
+	}
+	p.run()
+	defer p.stop()
+
+	got := <-suspects
+	require.Len(t, got, 1)
+	assert.Equal(t, "main;leak", got[0].Stack)
+	assert.Equal(t, int64(1), got[0].InuseObjects)
+	assert.Equal(t, int64(100), got[0].Growth)
+	calls := statsd.GetCallsByName("datadog.profiling.leak_suspect")
+	require.NotEmpty(t, calls)
+	assert.Equal(t, int64(1), calls[0].IntVal())
+
+	require.Eventually(t, func() bool { return len(exporter.Batches()) > 1 }, 5*time.Second, 10*time.Millisecond)
+	// there is no suspect until there are enough heap profiles to compare
+	batches := exporter.Batches()
+	assert.Empty(t, batches[0].LeakSuspects)
+	assert.Equal(t, got, batches[1].LeakSuspects)
+	var event uploadEvent
+	require.NoError(t, json.Unmarshal(batches[1].Event, &event))
+	assert.Equal(t, got, event.Info.Profiler.LeakSuspects)
+}
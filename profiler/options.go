@@ -115,6 +115,12 @@ type config struct {
 	enabled              bool
 	flushOnExit          bool
 	compressionConfig    string
+	triggers             []Trigger
+	triggerCooldown      time.Duration
+	triggerDuration      time.Duration
+	flightRecorder       *FlightRecorderConfig
+	exporter             Exporter
+	leakDetection        *LeakDetectionConfig
 }
 
 // logStartup records the configuration to the configured logger in JSON format
@@ -187,6 +193,8 @@ func defaultConfig() (*config, error) {
 		logStartup:           internal.BoolEnv("DD_TRACE_STARTUP_LOGS", true),
 		endpointCountEnabled: internal.BoolEnv(traceprof.EndpointCountEnvVar, false),
 		compressionConfig:    os.Getenv("DD_PROFILING_DEBUG_COMPRESSION_SETTINGS"),
+		triggerCooldown:      DefaultTriggerCooldown,
+		triggerDuration:      DefaultTriggerDuration,
 		traceConfig: executionTraceConfig{
 			Enabled: internal.BoolEnv("DD_PROFILING_EXECUTION_TRACE_ENABLED", executionTraceEnabledDefault),
 			Period:  internal.DurationEnv("DD_PROFILING_EXECUTION_TRACE_PERIOD", 15*time.Minute),
@@ -320,6 +328,57 @@ func CPUDuration(d time.Duration) Option {
 	}
 }
 
+// WithTriggers sets triggers detecting anomalies in the program, like a burst
+// of allocations or goroutines which would be averaged away or missed by the
+// periodic profiles. When one of them fires, the profiler immediately captures
+// an extra batch of profiles tagged "trigger:<name>": a heap profile, whose
+// allocations are reported since the previous trigger if delta profiles are
+// enabled, the stack traces of all the goroutines, and a CPU profile and an
+// execution trace covering the trigger duration, see WithTriggerDuration. The
+// Go runtime supports a single CPU profile and execution trace at a time: the
+// CPU profile of a trigger waits for the periodic one to finish, which is
+// shortened by the time it waits for that of a trigger in turn, and the
+// execution trace is skipped if the periodic one is being collected. A CPU
+// duration as long as the period is shortened by the trigger duration, to
+// leave room for the CPU profiles of the triggers, see CPUDuration. At most
+// one batch is captured per cooldown, see WithTriggerCooldown.
+func WithTriggers(triggers ...Trigger) Option {
+	return func(cfg *config) {
+		cfg.triggers = append(cfg.triggers, triggers...)
+	}
+}
+
+// WithTriggerCooldown specifies the minimum time between two batches of
+// profiles captured because a trigger fired. It defaults to
+// DefaultTriggerCooldown, and can't be shorter than a minute.
+func WithTriggerCooldown(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.triggerCooldown = d
+	}
+}
+
+// WithTriggerDuration specifies the length of the CPU profile and execution
+// trace captured when a trigger fires. It defaults to DefaultTriggerDuration.
+func WithTriggerDuration(d time.Duration) Option {
+	return func(cfg *config) {
+		cfg.triggerDuration = d
+	}
+}
+
+// WithFlightRecorder keeps the recent execution trace of the program in memory
+// using the flight recorder of the Go runtime, and captures it when a span
+// lasting longer than the threshold of cfg finishes, as long as its operation
+// and resource names match the patterns of cfg. The execution trace is
+// uploaded right away, tagged with the IDs of the slow span as
+// "trace_id:<id>" and "span_id:<id>", and covers the end of the span if it
+// lasted longer than the window of cfg. The flight recorder requires Go 1.25
+// or later, and is disabled with a warning on earlier versions.
+func WithFlightRecorder(cfg FlightRecorderConfig) Option {
+	return func(c *config) {
+		c.flightRecorder = &cfg
+	}
+}
+
 // CPUProfileRate sets the sampling frequency for CPU profiling. A sample will
 // be taken once for every (1 / hz) seconds of on-CPU time. If not given,
 // profiling will use the default rate from the runtime/pprof.StartCPUProfile
@@ -471,6 +530,28 @@ func withOutputDir(dir string) Option {
 	}
 }
 
+// WithLeakDetection enables the detection of heap leaks. At the end of each
+// profiling period, the profiler compares the in-use heap by call stack of the
+// last heap profiles, and reports the stacks whose in-use heap kept growing as
+// leak suspects. They are included in the metadata of the uploaded profiles,
+// counted by the "datadog.profiling.leak_suspect" statsd metric, and passed
+// to the OnSuspects callback of cfg. The detection collects an extra heap
+// profile per period.
+func WithLeakDetection(cfg LeakDetectionConfig) Option {
+	return func(c *config) {
+		c.leakDetection = &cfg
+	}
+}
+
+// WithExporter exports the batches of profiles with the exporter e, instead
+// of uploading them to Datadog. See DirExporter to write them to a directory,
+// and MemoryExporter to inspect them in tests.
+func WithExporter(e Exporter) Option {
+	return func(cfg *config) {
+		cfg.exporter = e
+	}
+}
+
 // WithLogStartup toggles logging the configuration of the profiler to standard
 // error when profiling is started. The configuration is logged in a JSON
 // format. This option is enabled by default.
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Command pgobuild builds a profile for profile-guided optimization out of the
+// CPU profiles of directories, like the ones written by profiler.DirExporter,
+// merging the most recent ones:
+//
+//	pgobuild -o default.pgo ./profiles
+//	go build -pgo=default.pgo
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+	"github.com/DataDog/dd-trace-go/v2/profiler/pgo"
+)
+
+var (
+	output      string
+	maxProfiles int
+	topSamples  int
+	maxBytes    int
+	text        bool
+)
+
+func init() {
+	flag.StringVar(&output, "o", "default.pgo", "Path of the profile to write")
+	flag.IntVar(&maxProfiles, "max-profiles", pgo.DefaultMaxProfiles, "Number of most recent CPU profiles to merge, 0 for all")
+	flag.IntVar(&topSamples, "top", pgo.DefaultTopSamples, "Number of hottest samples to keep, 0 for all")
+	flag.IntVar(&maxBytes, "max-bytes", pgo.DefaultMaxBytes, "Upper bound on the size of the profile, 0 for none")
+	flag.BoolVar(&text, "text", false, "Print the merged profile to stdout in the folded text format, instead of writing it")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pgobuild [flags] <dir>...\n")
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	b := pgo.NewBuilder(
+		pgo.WithMaxProfiles(maxProfiles),
+		pgo.WithTopSamples(topSamples),
+		pgo.WithMaxBytes(maxBytes),
+	)
+	for _, dir := range flag.Args() {
+		if err := b.AddDir(dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if text {
+		p, err := b.Build()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := (pprofutils.Protobuf{SampleTypes: true}).Convert(p, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := b.WriteFile(output); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s from %d CPU profiles", output, b.Len())
+}
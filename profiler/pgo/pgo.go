@@ -0,0 +1,331 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package pgo builds the profiles used for profile-guided optimization (PGO)
+// of Go programs, see https://go.dev/doc/pgo, out of the CPU profiles
+// collected by the profiler.
+//
+// A Builder keeps a rolling merge of the most recent CPU profiles, added from
+// the files written by a profiler.DirExporter, or directly from the profiler
+// when used as its exporter:
+//
+//	b := pgo.NewBuilder()
+//	profiler.Start(profiler.WithExporter(b))
+//	...
+//	b.WriteFile("default.pgo")
+//
+// The merged profile is normalized to keep it small and stable: the labels
+// are stripped, only the hottest samples are kept, and its size is bounded.
+// The pgobuild command in cmd/pgobuild builds a default.pgo file out of the
+// profiles of directories.
+package pgo
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/pprof/profile"
+
+	"github.com/DataDog/dd-trace-go/v2/profiler"
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+)
+
+const (
+	// DefaultMaxProfiles is the default number of most recent CPU profiles
+	// merged by a Builder, an hour of profiles at the default profiling
+	// period.
+	DefaultMaxProfiles = 60
+
+	// DefaultTopSamples is the default number of hottest samples kept in the
+	// merged profile.
+	DefaultTopSamples = 5000
+
+	// DefaultMaxBytes is the default upper bound on the size of the encoded
+	// merged profile.
+	DefaultMaxBytes = 1 << 20
+)
+
+const (
+	// cpuProfileName is the name of the CPU profiles uploaded by the profiler.
+	cpuProfileName = "cpu.pprof"
+
+	// eventName is the name of the file describing a batch written by a
+	// profiler.DirExporter next to its profiles.
+	eventName = "event.json"
+
+	// triggerTagPrefix prefixes the tag of the batches captured because a
+	// trigger fired, see profiler.WithTriggers.
+	triggerTagPrefix = "trigger:"
+)
+
+// cpuValueType is the type of the CPU time in the samples of CPU profiles.
+var cpuValueType = pprofutils.ValueType{Type: "cpu", Unit: "nanoseconds"}
+
+// errNoProfiles is returned when building a profile out of no CPU profiles.
+var errNoProfiles = errors.New("pgo: no CPU profiles")
+
+// A Builder builds a PGO profile out of a rolling merge of CPU profiles. It
+// implements profiler.Exporter to collect the CPU profiles of a profiler. It
+// is safe for concurrent use.
+type Builder struct {
+	maxProfiles int
+	topSamples  int
+	maxBytes    int
+
+	mu       sync.Mutex
+	profiles []*profile.Profile // normalized profiles, oldest first
+}
+
+var _ profiler.Exporter = (*Builder)(nil)
+
+// An Option configures a Builder.
+type Option func(*Builder)
+
+// WithMaxProfiles sets the number of most recent CPU profiles merged. It
+// defaults to DefaultMaxProfiles.
+func WithMaxProfiles(n int) Option {
+	return func(b *Builder) {
+		b.maxProfiles = n
+	}
+}
+
+// WithTopSamples sets the number of hottest samples of the merged profile
+// which are kept. It defaults to DefaultTopSamples.
+func WithTopSamples(n int) Option {
+	return func(b *Builder) {
+		b.topSamples = n
+	}
+}
+
+// WithMaxBytes sets the upper bound on the size of the encoded merged profile,
+// which is enforced by dropping its coldest samples. It defaults to
+// DefaultMaxBytes.
+func WithMaxBytes(n int) Option {
+	return func(b *Builder) {
+		b.maxBytes = n
+	}
+}
+
+// NewBuilder returns a new Builder with the given options.
+func NewBuilder(opts ...Option) *Builder {
+	b := &Builder{
+		maxProfiles: DefaultMaxProfiles,
+		topSamples:  DefaultTopSamples,
+		maxBytes:    DefaultMaxBytes,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Add adds the CPU profile encoded in data, in the pprof format, to the
+// merge. The oldest profile is dropped if there are more than the maximum
+// number of profiles.
+func (b *Builder) Add(data []byte) error {
+	p, err := profile.ParseData(data)
+	if err != nil {
+		return fmt.Errorf("pgo: parsing profile: %w", err)
+	}
+	if cpuValueIndex(p) < 0 {
+		return fmt.Errorf("pgo: not a CPU profile: %v", p.SampleType)
+	}
+	normalize(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.profiles = append(b.profiles, p)
+	if n := len(b.profiles) - b.maxProfiles; b.maxProfiles > 0 && n > 0 {
+		b.profiles = slices.Delete(b.profiles, 0, n)
+	}
+	return nil
+}
+
+// Export implements profiler.Exporter, adding the CPU profile of the batch to
+// the merge. Batches without a CPU profile are ignored, as well as the batches
+// captured because a trigger fired, whose CPU profiles cover an anomaly rather
+// than the usual workload of the program.
+func (b *Builder) Export(_ context.Context, bat profiler.Batch) error {
+	if slices.ContainsFunc(bat.Tags, isTriggerTag) {
+		return nil
+	}
+	for _, a := range bat.Attachments {
+		if a.Name == cpuProfileName {
+			return b.Add(a.Data)
+		}
+	}
+	return nil
+}
+
+// AddDir adds the CPU profiles found in dir and its subdirectories, in the
+// lexical order of their paths, which is chronological for the directories
+// written by a profiler.DirExporter. Like Export, it skips the batches
+// captured because a trigger fired.
+func (b *Builder) AddDir(dir string) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == cpuProfileName {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		triggered, err := isTriggered(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if triggered {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := b.Add(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of CPU profiles currently merged.
+func (b *Builder) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.profiles)
+}
+
+// Build returns the merged profile, keeping only its hottest samples.
+func (b *Builder) Build() (*profile.Profile, error) {
+	b.mu.Lock()
+	profiles := slices.Clone(b.profiles)
+	b.mu.Unlock()
+	if len(profiles) == 0 {
+		return nil, errNoProfiles
+	}
+	// Merge doesn't modify the profiles, but copies them.
+	merged, err := profile.Merge(profiles)
+	if err != nil {
+		return nil, fmt.Errorf("pgo: merging profiles: %w", err)
+	}
+	hottestFirst(merged)
+	if b.topSamples > 0 && len(merged.Sample) > b.topSamples {
+		merged.Sample = merged.Sample[:b.topSamples]
+	}
+	return merged.Compact(), nil
+}
+
+// WriteTo writes the merged profile to w in the pprof format, dropping its
+// coldest samples until it fits in the maximum size.
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	p, err := b.Build()
+	if err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	for {
+		buf.Reset()
+		if err := p.Write(&buf); err != nil {
+			return 0, err
+		}
+		if b.maxBytes <= 0 || buf.Len() <= b.maxBytes || len(p.Sample) <= 1 {
+			break
+		}
+		// The size is roughly proportional to the number of samples.
+		n := min(len(p.Sample)*b.maxBytes/buf.Len(), len(p.Sample)*9/10)
+		p.Sample = p.Sample[:max(n, 1)]
+		p = p.Compact()
+	}
+	return buf.WriteTo(w)
+}
+
+// WriteFile writes the merged profile to the file at path, like
+// "default.pgo", see WriteTo. The file is replaced atomically, so that a build
+// reading it concurrently doesn't see a partial profile.
+func (b *Builder) WriteFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = b.WriteTo(tmp)
+	// 0644 is what touch does, CreateTemp restricts the file to its owner.
+	err = cmp.Or(err, tmp.Chmod(0644))
+	if err = cmp.Or(err, tmp.Close()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// cpuValueIndex returns the index of the CPU time in the sample values of p,
+// or -1 if p is not a CPU profile.
+func cpuValueIndex(p *profile.Profile) int {
+	return slices.IndexFunc(p.SampleType, func(vt *profile.ValueType) bool {
+		return vt.Type == cpuValueType.Type && vt.Unit == cpuValueType.Unit
+	})
+}
+
+// isTriggerTag reports whether tag is the tag of a batch captured because a
+// trigger fired.
+func isTriggerTag(tag string) bool {
+	return strings.HasPrefix(tag, triggerTagPrefix)
+}
+
+// isTriggered reports whether the batch written by a profiler.DirExporter to
+// dir was captured because a trigger fired, according to its event. A
+// directory without an event is not a batch of the exporter.
+func isTriggered(dir string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, eventName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil || len(data) == 0 {
+		return false, err
+	}
+	var event struct {
+		Tags string `json:"tags_profiler"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return false, fmt.Errorf("%s: %w", filepath.Join(dir, eventName), err)
+	}
+	return slices.ContainsFunc(strings.Split(event.Tags, ","), isTriggerTag), nil
+}
+
+// normalize strips the labels of the samples of p, which are irrelevant for
+// PGO, so that the samples of the same stack are merged.
+func normalize(p *profile.Profile) {
+	for _, s := range p.Sample {
+		s.Label = nil
+		s.NumLabel = nil
+		s.NumUnit = nil
+	}
+	p.Comments = nil
+}
+
+// hottestFirst sorts the samples of the CPU profile p by decreasing CPU time.
+func hottestFirst(p *profile.Profile) {
+	i := cpuValueIndex(p)
+	slices.SortStableFunc(p.Sample, func(a, b *profile.Sample) int {
+		return cmp.Compare(b.Value[i], a.Value[i])
+	})
+}
@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package pgo
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/profiler"
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+)
+
+// cpuProfile returns the CPU profile of the folded text, with a label on each
+// sample.
+func cpuProfile(t *testing.T, text string) []byte {
+	t.Helper()
+	p, err := pprofutils.Text{}.Convert(strings.NewReader("samples/count cpu/nanoseconds\n" + text))
+	require.NoError(t, err)
+	for i, s := range p.Sample {
+		s.Label = map[string][]string{"span id": {strings.Repeat("1", i+1)}}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	return buf.Bytes()
+}
+
+// folded returns the folded text of the profile encoded in data.
+func folded(t *testing.T, data []byte) string {
+	t.Helper()
+	p, err := profile.ParseData(data)
+	require.NoError(t, err)
+	return foldedProfile(t, p)
+}
+
+func foldedProfile(t *testing.T, p *profile.Profile) string {
+	t.Helper()
+	for _, s := range p.Sample {
+		assert.Empty(t, s.Label)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, pprofutils.Protobuf{SampleTypes: true}.Convert(p, &buf))
+	// skip the header
+	_, text, _ := strings.Cut(buf.String(), "\n")
+	return text
+}
+
+func TestBuilder(t *testing.T) {
+	t.Run("merge", func(t *testing.T) {
+		b := NewBuilder()
+		require.NoError(t, b.Add(cpuProfile(t, "main;a 1 10\nmain;b 2 20\nmain;a 3 30\n")))
+		require.NoError(t, b.Add(cpuProfile(t, "main;a 1 10\nmain;b 4 40\n")))
+		p, err := b.Build()
+		require.NoError(t, err)
+		// hottest first, the labels being stripped
+		assert.Equal(t, "main;b 6 60\nmain;a 5 50\n", foldedProfile(t, p))
+	})
+
+	t.Run("top-samples", func(t *testing.T) {
+		b := NewBuilder(WithTopSamples(2))
+		require.NoError(t, b.Add(cpuProfile(t, "main;a 1 10\nmain;b 3 30\nmain;c 2 20\n")))
+		p, err := b.Build()
+		require.NoError(t, err)
+		assert.Equal(t, "main;b 3 30\nmain;c 2 20\n", foldedProfile(t, p))
+	})
+
+	t.Run("max-profiles", func(t *testing.T) {
+		b := NewBuilder(WithMaxProfiles(2))
+		require.NoError(t, b.Add(cpuProfile(t, "main;a 1 10\n")))
+		require.NoError(t, b.Add(cpuProfile(t, "main;b 2 20\n")))
+		require.NoError(t, b.Add(cpuProfile(t, "main;c 3 30\n")))
+		assert.Equal(t, 2, b.Len())
+		p, err := b.Build()
+		require.NoError(t, err)
+		assert.Equal(t, "main;c 3 30\nmain;b 2 20\n", foldedProfile(t, p))
+	})
+
+	t.Run("max-bytes", func(t *testing.T) {
+		var text strings.Builder
+		for i := range 1000 {
+			text.WriteString("main;f" + strings.Repeat("x", i%50) + ";g" + strings.Repeat("y", i) + " 1 " + strings.Repeat("1", 1+i%15) + "\n")
+		}
+		b := NewBuilder(WithMaxBytes(10000), WithTopSamples(0))
+		require.NoError(t, b.Add(cpuProfile(t, text.String())))
+		var buf bytes.Buffer
+		_, err := b.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, buf.Len(), 10000)
+		p, err := profile.ParseData(buf.Bytes())
+		require.NoError(t, err)
+		assert.NotEmpty(t, p.Sample)
+		assert.Less(t, len(p.Sample), 1000)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		b := NewBuilder()
+		_, err := b.Build()
+		assert.ErrorIs(t, err, errNoProfiles)
+		assert.Error(t, b.Add([]byte("not a profile")))
+		heap, err := pprofutils.Text{}.Convert(strings.NewReader("alloc_space/bytes\nmain;a 10\n"))
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, heap.Write(&buf))
+		assert.ErrorContains(t, b.Add(buf.Bytes()), "not a CPU profile")
+	})
+}
+
+func TestBuilderExport(t *testing.T) {
+	b := NewBuilder()
+	require.NoError(t, b.Export(context.Background(), profiler.Batch{
+		Attachments: []profiler.Attachment{
+			{Name: "delta-heap.pprof", Data: []byte("ignored")},
+			{Name: "cpu.pprof", Data: cpuProfile(t, "main;a 1 10\n")},
+		},
+	}))
+	require.NoError(t, b.Export(context.Background(), profiler.Batch{}))
+	// the batches captured because a trigger fired are ignored
+	require.NoError(t, b.Export(context.Background(), profiler.Batch{
+		Tags:        []string{"service:test", "trigger:goroutines"},
+		Attachments: []profiler.Attachment{{Name: "cpu.pprof", Data: cpuProfile(t, "main;b 1 10\n")}},
+	}))
+	assert.Equal(t, 1, b.Len())
+}
+
+func TestBuilderDir(t *testing.T) {
+	dir := t.TempDir()
+	exporter := profiler.NewDirExporter(dir, 0)
+	for i, text := range []string{"main;a 1 10\n", "main;b 2 20\n", "main;c 3 30\n"} {
+		require.NoError(t, exporter.Export(context.Background(), profiler.Batch{
+			Seq:         uint64(i),
+			Attachments: []profiler.Attachment{{Name: "cpu.pprof", Data: cpuProfile(t, text)}},
+		}))
+	}
+	// a batch captured because a trigger fired is skipped
+	require.NoError(t, exporter.Export(context.Background(), profiler.Batch{
+		Seq:         3,
+		Attachments: []profiler.Attachment{{Name: "cpu.pprof", Data: cpuProfile(t, "main;d 4 40\n")}},
+		Event:       []byte(`{"tags_profiler":"service:test,trigger:goroutines"}`),
+	}))
+
+	b := NewBuilder(WithMaxProfiles(2))
+	require.NoError(t, b.AddDir(dir))
+	out := filepath.Join(t.TempDir(), "default.pgo")
+	require.NoError(t, b.WriteFile(out))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	// the most recent profiles are merged
+	assert.Equal(t, "main;c 3 30\nmain;b 2 20\n", folded(t, data))
+}
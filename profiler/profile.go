@@ -103,10 +103,16 @@ var profileTypes = map[ProfileType]profileType{
 
 			compressor := p.compressors[CPUProfile]
 			compressor.Reset(&buf)
+			// A trigger may be collecting a CPU profile, see WithTriggers.
+			// The profile is shortened by the time spent waiting for it,
+			// so that the period isn't delayed.
+			waitStart := time.Now()
+			p.cpuMu.Lock()
+			defer p.cpuMu.Unlock()
 			if err := p.startCPUProfile(compressor); err != nil {
 				return nil, err
 			}
-			p.interruptibleSleep(p.cfg.cpuDuration)
+			p.interruptibleSleep(p.cfg.cpuDuration - time.Since(waitStart))
 
 			// We want the CPU profiler to finish last so that it can
 			// properly record all of our profile processing work for
@@ -207,13 +213,19 @@ var profileTypes = map[ProfileType]profileType{
 			compressor := p.compressors[executionTrace]
 			compressor.Reset(buf)
 			lt := newLimitedTraceCollector(compressor, int64(p.cfg.traceConfig.Limit))
+			// A trigger may be collecting an execution trace, see WithTriggers.
+			// The trace is shortened by the time spent waiting for it, so
+			// that the period isn't delayed.
+			waitStart := time.Now()
+			p.traceMu.Lock()
+			defer p.traceMu.Unlock()
 			if err := trace.Start(lt); err != nil {
 				return nil, err
 			}
 			traceLogCPUProfileRate(p.cfg.cpuProfileRate)
 			select {
 			case <-p.exit: // Profiling was stopped
-			case <-time.After(p.cfg.period): // The profiling cycle has ended
+			case <-time.After(p.cfg.period - time.Since(waitStart)): // The profiling cycle has ended
 			case <-lt.done: // The trace size limit was exceeded
 			}
 			trace.Stop()
@@ -360,6 +372,9 @@ type batch struct {
 	// customAttributes are pprof label keys which should be available as
 	// attributes for filtering profiles in our UI
 	customAttributes []string
+	// leakSuspects are the leak suspects found at the end of the period, see
+	// WithLeakDetection
+	leakSuspects []LeakSuspect
 }
 
 func (b *batch) addProfile(p *profile) {
@@ -18,6 +18,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/internal"
@@ -96,11 +97,22 @@ type profiler struct {
 	met             *metrics          // metric collector state
 	deltas          map[ProfileType]*fastDeltaProfiler
 	compressors     map[ProfileType]compressor
-	seq             uint64         // seq is the value of the profile_seq tag
+	seq             atomic.Uint64  // seq is the value of the profile_seq tag of the next batch
 	pendingProfiles sync.WaitGroup // signal that profile collection is done, for stopping CPU profiling
+	triggers        sync.WaitGroup // triggers waits for the triggers to stop, before closing out.
+	cpuMu           sync.Mutex     // cpuMu is held while collecting a CPU profile.
+	traceMu         sync.Mutex     // traceMu is held while collecting an execution trace.
+
+	// triggerHeapDelta computes the delta of the heap profiles captured when
+	// a trigger fires, see WithTriggers. It's only used by the goroutine
+	// checking the triggers.
+	triggerHeapDelta *fastDeltaProfiler
 
 	testHooks testHooks
 
+	// leaks detects heap leaks, if enabled with WithLeakDetection
+	leaks *leakDetector
+
 	// lastTrace is the last time an execution trace was collected
 	lastTrace time.Time
 }
@@ -216,9 +228,23 @@ func newProfiler(opts ...Option) (*profiler, error) {
 			return nil, fmt.Errorf("unknown profile type: %d", pt)
 		}
 	}
+	if cfg.flightRecorder != nil && cfg.flightRecorder.Threshold <= 0 {
+		return nil, fmt.Errorf("invalid flight recorder threshold, must be > 0: %s", cfg.flightRecorder.Threshold)
+	}
 	if cfg.cpuDuration > cfg.period {
 		cfg.cpuDuration = cfg.period
 	}
+	if len(cfg.triggers) > 0 {
+		if cfg.triggerCooldown < minTriggerCooldown {
+			log.Warn("profiler: trigger cooldown %s is too short, using %s", cfg.triggerCooldown, minTriggerCooldown)
+			cfg.triggerCooldown = minTriggerCooldown
+		}
+		// Leave room for the CPU profiles of the triggers, which can't run
+		// while the periodic one is collected.
+		if cfg.cpuDuration == cfg.period && cfg.period > cfg.triggerDuration {
+			cfg.cpuDuration = cfg.period - cfg.triggerDuration
+		}
+	}
 	if cfg.logStartup {
 		logStartup(cfg)
 	}
@@ -271,7 +297,13 @@ func newProfiler(opts ...Option) (*profiler, error) {
 			p.deltas[pt] = newFastDeltaProfiler(compressor, profileTypes[pt].DeltaValues...)
 		}
 	}
+	if cfg.leakDetection != nil {
+		p.leaks = newLeakDetector(*cfg.leakDetection)
+	}
 	p.uploadFunc = p.upload
+	if cfg.exporter != nil {
+		p.uploadFunc = p.export
+	}
 	return &p, nil
 }
 
@@ -296,6 +328,21 @@ func (p *profiler) run() {
 		p.met.reset(now()) // collect baseline metrics at profiler start
 		p.collect(tick.C)
 	}()
+	if len(p.cfg.triggers) > 0 {
+		p.triggers.Add(1)
+		go func() {
+			defer p.triggers.Done()
+			p.watchTriggers()
+		}()
+	}
+	if p.cfg.flightRecorder != nil {
+		// The flight recorder enqueues batches like the triggers.
+		p.triggers.Add(1)
+		go func() {
+			defer p.triggers.Done()
+			p.runFlightRecorder()
+		}()
+	}
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
@@ -306,7 +353,11 @@ func (p *profiler) run() {
 // collect runs the profile types found in the configuration whenever the ticker receives
 // an item.
 func (p *profiler) collect(ticker <-chan time.Time) {
-	defer close(p.out)
+	defer func() {
+		// The triggers may still be enqueuing a batch.
+		p.triggers.Wait()
+		close(p.out)
+	}()
 	var (
 		// mu guards completed
 		mu        sync.Mutex
@@ -328,7 +379,7 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 	exit := false
 	for !exit {
 		bat := batch{
-			seq:   p.seq,
+			seq:   p.seq.Add(1) - 1,
 			host:  p.cfg.hostname,
 			start: now(),
 			extraTags: []string{
@@ -341,8 +392,6 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 			},
 			customAttributes: p.cfg.customProfilerLabels,
 		}
-		p.seq++
-
 		clear(completed)
 		completed = completed[:0]
 		// We need to increment pendingProfiles for every non-CPU
@@ -397,6 +446,9 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 			}(t)
 		}
 		wg.Wait()
+		if p.leaks != nil {
+			bat.leakSuspects = p.detectLeaks()
+		}
 		for _, prof := range completed {
 			if prof.pt == executionTrace {
 				// If the profile batch includes a runtime execution trace, add a tag so
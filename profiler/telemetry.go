@@ -65,5 +65,11 @@ func telemetryConfiguration(c *config) []telemetry.Configuration {
 		{Name: "num_custom_profiler_label_keys", Value: len(c.customProfilerLabels)},
 		{Name: "flush_on_exit", Value: c.flushOnExit},
 		{Name: "debug_compression_settings", Value: c.compressionConfig},
+		{Name: "num_triggers", Value: len(c.triggers)},
+		{Name: "trigger_cooldown", Value: c.triggerCooldown.String()},
+		{Name: "trigger_duration", Value: c.triggerDuration.String()},
+		{Name: "flight_recorder_enabled", Value: c.flightRecorder != nil},
+		{Name: "custom_exporter", Value: c.exporter != nil},
+		{Name: "leak_detection_enabled", Value: c.leakDetection != nil},
 	}
 }
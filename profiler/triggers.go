@@ -0,0 +1,352 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"runtime"
+	rtmetrics "runtime/metrics"
+	"runtime/trace"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+const (
+	// DefaultTriggerCooldown specifies the default minimum time between two
+	// batches of profiles captured because a trigger fired, see WithTriggers.
+	DefaultTriggerCooldown = 5 * time.Minute
+
+	// DefaultTriggerDuration specifies the default length of the CPU profile
+	// and execution trace captured when a trigger fires, see WithTriggers.
+	DefaultTriggerDuration = 10 * time.Second
+
+	// minTriggerCooldown is the minimum time between two batches of profiles
+	// captured because a trigger fired, see WithTriggerCooldown.
+	minTriggerCooldown = time.Minute
+)
+
+// triggerCheckInterval is the interval at which the triggers are checked. It
+// is a variable so that it can be shortened in tests.
+var triggerCheckInterval = time.Second
+
+// A Trigger detects an anomaly in the program, like a burst of allocations or
+// goroutines, upon which the profiler immediately captures an extra batch of
+// profiles instead of waiting for the next profiling period. See WithTriggers.
+type Trigger struct {
+	name string
+	// newCheck returns the function reporting whether the trigger fires,
+	// called every triggerCheckInterval. It is called once per profiler, so
+	// that the returned function can keep the state of the previous checks.
+	newCheck func() func() bool
+}
+
+// Name returns the name of the trigger, tagged on the profiles captured when
+// it fires as "trigger:<name>".
+func (t Trigger) Name() string {
+	return t.name
+}
+
+// HeapGrowthTrigger returns a Trigger named "heap_growth" which fires when the
+// heap grows faster than bytesPerSecond.
+func HeapGrowthTrigger(bytesPerSecond uint64) Trigger {
+	return Trigger{
+		name: "heap_growth",
+		newCheck: func() func() bool {
+			const metric = "/memory/classes/heap/objects:bytes"
+			sample := []rtmetrics.Sample{{Name: metric}}
+			rtmetrics.Read(sample)
+			prev, prevTime := sample[0].Value.Uint64(), time.Now()
+			return func() bool {
+				rtmetrics.Read(sample)
+				cur, curTime := sample[0].Value.Uint64(), time.Now()
+				elapsed := curTime.Sub(prevTime).Seconds()
+				grown := cur > prev && elapsed > 0 && float64(cur-prev)/elapsed > float64(bytesPerSecond)
+				prev, prevTime = cur, curTime
+				return grown
+			}
+		},
+	}
+}
+
+// GoroutineTrigger returns a Trigger named "goroutines" which fires when the
+// program has more than max goroutines.
+func GoroutineTrigger(max int) Trigger {
+	return Trigger{
+		name: "goroutines",
+		newCheck: func() func() bool {
+			return func() bool {
+				return runtime.NumGoroutine() > max
+			}
+		},
+	}
+}
+
+// GCCPUTrigger returns a Trigger named "gc_cpu" which fires when the garbage
+// collector uses more than the given fraction, between 0 and 1, of the CPU
+// time available to the program since the previous check.
+func GCCPUTrigger(fraction float64) Trigger {
+	return Trigger{
+		name: "gc_cpu",
+		newCheck: func() func() bool {
+			sample := []rtmetrics.Sample{
+				{Name: "/cpu/classes/gc/total:cpu-seconds"},
+				{Name: "/cpu/classes/total:cpu-seconds"},
+			}
+			rtmetrics.Read(sample)
+			prevGC, prevTotal := sample[0].Value.Float64(), sample[1].Value.Float64()
+			return func() bool {
+				rtmetrics.Read(sample)
+				gc, total := sample[0].Value.Float64(), sample[1].Value.Float64()
+				fired := total > prevTotal && (gc-prevGC)/(total-prevTotal) > fraction
+				prevGC, prevTotal = gc, total
+				return fired
+			}
+		},
+	}
+}
+
+// FuncTrigger returns a Trigger with the given name which fires when fn
+// returns true. fn is called every second from a single goroutine.
+func FuncTrigger(name string, fn func() bool) Trigger {
+	return Trigger{
+		name: name,
+		newCheck: func() func() bool {
+			return fn
+		},
+	}
+}
+
+// watchTriggers checks the triggers of the configuration until the profiler
+// is stopped, and captures a batch of profiles each time one fires, at most
+// once per cooldown.
+func (p *profiler) watchTriggers() {
+	checks := make([]func() bool, len(p.cfg.triggers))
+	for i, t := range p.cfg.triggers {
+		checks[i] = t.newCheck()
+	}
+	if p.cfg.deltaProfiles {
+		if err := p.startTriggerHeapDelta(); err != nil {
+			log.Error("profiler: failed to start the delta heap profile of the triggers: %v", err.Error())
+		}
+	}
+	tick := time.NewTicker(triggerCheckInterval)
+	defer tick.Stop()
+	var lastFired time.Time
+	for {
+		select {
+		case <-p.exit:
+			return
+		case <-tick.C:
+		}
+		// All the checks run on every tick, so that the ones comparing with
+		// their previous state compare with the latest one.
+		fired := -1
+		for i, check := range checks {
+			if check() && fired < 0 {
+				fired = i
+			}
+		}
+		if fired < 0 || (!lastFired.IsZero() && time.Since(lastFired) < p.cfg.triggerCooldown) {
+			continue
+		}
+		lastFired = time.Now()
+		name := p.cfg.triggers[fired].name
+		log.Debug("profiler: trigger %s fired, capturing profiles", name)
+		p.cfg.statsd.Count("datadog.profiling.go.trigger", 1, append(p.cfg.tags.Slice(), "trigger:"+name), 1)
+		p.collectTriggered(name)
+	}
+}
+
+// triggeredProfile collects a profile of the batch captured when a trigger
+// fires. It returns a nil profile if the profile can't be captured at the
+// moment.
+type triggeredProfile func(p *profiler) (*profile, error)
+
+// triggeredProfiles are the profiles captured when a trigger fires. They don't
+// share the compressors and delta profilers of the periodic profiles, as they
+// run concurrently with them.
+var triggeredProfiles = map[ProfileType]triggeredProfile{
+	HeapProfile: func(p *profiler) (*profile, error) {
+		var buf bytes.Buffer
+		if p.triggerHeapDelta == nil {
+			compressor, err := p.newTriggerCompressor(HeapProfile, &buf)
+			if err != nil {
+				return nil, err
+			}
+			err = p.lookupProfile("heap", compressor, 0)
+			err = cmp.Or(err, compressor.Close())
+			return &profile{name: HeapProfile.Filename(), pt: HeapProfile, data: buf.Bytes()}, err
+		}
+		// The allocations are reported since the previous trigger, like the
+		// periodic heap profiles report them since the previous period.
+		if err := p.lookupProfile("heap", &buf, 0); err != nil {
+			return nil, err
+		}
+		delta, err := p.triggerHeapDelta.Delta(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("delta profile error: %s", err.Error())
+		}
+		return &profile{name: "delta-" + HeapProfile.Filename(), pt: HeapProfile, data: delta}, nil
+	},
+	expGoroutineWaitProfile: func(p *profiler) (*profile, error) {
+		if n := runtime.NumGoroutine(); n > p.cfg.maxGoroutinesWait {
+			return nil, fmt.Errorf("skipping goroutines wait profile: %d goroutines exceeds DD_PROFILING_WAIT_PROFILE_MAX_GOROUTINES limit of %d", n, p.cfg.maxGoroutinesWait)
+		}
+		var (
+			now  = now()
+			text = &bytes.Buffer{}
+			buf  bytes.Buffer
+		)
+		if err := p.lookupProfile("goroutine", text, 2); err != nil {
+			return nil, err
+		}
+		compressor, err := p.newTriggerCompressor(expGoroutineWaitProfile, &buf)
+		if err != nil {
+			return nil, err
+		}
+		err = goroutineDebug2ToPprof(text, compressor, now)
+		err = cmp.Or(err, compressor.Close())
+		return &profile{name: expGoroutineWaitProfile.Filename(), pt: expGoroutineWaitProfile, data: buf.Bytes()}, err
+	},
+	CPUProfile: func(p *profiler) (*profile, error) {
+		// The Go runtime supports a single CPU profile at a time, so the
+		// periodic one has to finish first. It leaves room for this one at
+		// the start of each period, see newProfiler.
+		waitStart := time.Now()
+		p.cpuMu.Lock()
+		defer p.cpuMu.Unlock()
+		if wait := time.Since(waitStart); wait > time.Second {
+			log.Debug("profiler: the CPU profile of the trigger waited %s for the periodic one", wait)
+		}
+		select {
+		case <-p.exit:
+			return nil, nil
+		default:
+		}
+		var buf bytes.Buffer
+		compressor, err := p.newTriggerCompressor(CPUProfile, &buf)
+		if err != nil {
+			return nil, err
+		}
+		if p.cfg.cpuProfileRate != 0 {
+			runtime.SetCPUProfileRate(p.cfg.cpuProfileRate)
+		}
+		if err := p.startCPUProfile(compressor); err != nil {
+			return nil, err
+		}
+		p.interruptibleSleep(p.cfg.triggerDuration)
+		p.stopCPUProfile()
+		err = compressor.Close()
+		return &profile{name: CPUProfile.Filename(), pt: CPUProfile, data: buf.Bytes()}, err
+	},
+	executionTrace: func(p *profiler) (*profile, error) {
+		// The Go runtime supports a single execution trace at a time.
+		if !p.cfg.traceConfig.Enabled || !p.traceMu.TryLock() {
+			return nil, nil
+		}
+		defer p.traceMu.Unlock()
+		var buf bytes.Buffer
+		compressor, err := p.newTriggerCompressor(executionTrace, &buf)
+		if err != nil {
+			return nil, err
+		}
+		lt := newLimitedTraceCollector(compressor, int64(p.cfg.traceConfig.Limit))
+		if err := trace.Start(lt); err != nil {
+			return nil, err
+		}
+		traceLogCPUProfileRate(p.cfg.cpuProfileRate)
+		select {
+		case <-p.exit:
+		case <-time.After(p.cfg.triggerDuration):
+		case <-lt.done:
+		}
+		trace.Stop()
+		err = compressor.Close()
+		return &profile{name: executionTrace.Filename(), pt: executionTrace, data: buf.Bytes()}, err
+	},
+}
+
+// startTriggerHeapDelta sets up the delta profiler of the heap profiles
+// captured when a trigger fires, from a first heap profile. It doesn't share
+// the delta profiler of the periodic heap profiles, whose allocations would be
+// missing from the next periodic profile otherwise.
+func (p *profiler) startTriggerHeapDelta() error {
+	in, out := compressionStrategy(HeapProfile, true, p.cfg.compressionConfig)
+	compressor, err := newCompressionPipeline(in, out)
+	if err != nil {
+		return err
+	}
+	dp := newFastDeltaProfiler(compressor, profileTypes[HeapProfile].DeltaValues...)
+	var buf bytes.Buffer
+	if err := p.lookupProfile("heap", &buf, 0); err != nil {
+		return err
+	}
+	if _, err := dp.Delta(buf.Bytes()); err != nil {
+		return err
+	}
+	p.triggerHeapDelta = dp
+	return nil
+}
+
+// newTriggerCompressor returns a new compressor of the profiles of type pt
+// captured when a trigger fires, writing to w.
+func (p *profiler) newTriggerCompressor(pt ProfileType, w *bytes.Buffer) (compressor, error) {
+	in, out := compressionStrategy(pt, false, p.cfg.compressionConfig)
+	c, err := newCompressionPipeline(in, out)
+	if err != nil {
+		return nil, err
+	}
+	c.Reset(w)
+	return c, nil
+}
+
+// collectTriggered captures the batch of profiles of the trigger with the
+// given name and enqueues it for upload.
+func (p *profiler) collectTriggered(name string) {
+	bat := batch{
+		seq:   p.seq.Add(1) - 1,
+		host:  p.cfg.hostname,
+		start: now(),
+		extraTags: []string{
+			"trigger:" + name,
+			fmt.Sprintf("_dd.profiler.go_execution_trace_enabled:%v", p.cfg.traceConfig.Enabled),
+			pgoTag(),
+		},
+		customAttributes: p.cfg.customProfilerLabels,
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for pt, collect := range triggeredProfiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prof, err := collect(p)
+			if err != nil {
+				log.Error("Error getting %s profile of trigger %s: %v; skipping.", pt, name, err.Error())
+				p.cfg.statsd.Count("datadog.profiling.go.collect_error", 1, append(p.cfg.tags.Slice(), pt.Tag(), "trigger:"+name), 1)
+				return
+			}
+			if prof == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if pt == executionTrace {
+				bat.extraTags = append(bat.extraTags, "go_execution_traced:yes")
+			}
+			bat.addProfile(prof)
+		}()
+	}
+	wg.Wait()
+	bat.end = now()
+	p.enqueueUpload(bat)
+}
@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggers(t *testing.T) {
+	t.Run("goroutines", func(t *testing.T) {
+		check := GoroutineTrigger(runtime.NumGoroutine() + 10).newCheck()
+		assert.False(t, check())
+		stop := make(chan struct{})
+		defer close(stop)
+		for range 20 {
+			go func() { <-stop }()
+		}
+		assert.True(t, check())
+	})
+
+	t.Run("heap_growth", func(t *testing.T) {
+		check := HeapGrowthTrigger(1).newCheck()
+		var keep [][]byte
+		for range 100 {
+			keep = append(keep, make([]byte, 1<<20))
+		}
+		assert.True(t, check())
+		runtime.KeepAlive(keep)
+
+		check = HeapGrowthTrigger(1 << 50).newCheck()
+		keep = append(keep, make([]byte, 1<<20))
+		assert.False(t, check())
+		runtime.KeepAlive(keep)
+	})
+
+	t.Run("gc_cpu", func(t *testing.T) {
+		check := GCCPUTrigger(1).newCheck()
+		runtime.GC()
+		assert.False(t, check(), "the GC can't use more than all the CPU")
+	})
+
+	t.Run("func", func(t *testing.T) {
+		trig := FuncTrigger("custom", func() bool { return true })
+		assert.Equal(t, "custom", trig.Name())
+		assert.True(t, trig.newCheck()())
+	})
+}
+
+func TestWithTriggers(t *testing.T) {
+	defer func(d time.Duration) { triggerCheckInterval = d }(triggerCheckInterval)
+	triggerCheckInterval = 10 * time.Millisecond
+
+	var fired atomic.Int32
+	trig := FuncTrigger("custom", func() bool {
+		fired.Add(1)
+		return true
+	})
+	profiles := startTestProfiler(t, 10,
+		WithProfileTypes(HeapProfile),
+		WithPeriod(time.Hour),
+		WithTriggers(trig),
+		WithTriggerDuration(10*time.Millisecond),
+	)
+
+	// the periodic batch is only uploaded at the end of the period
+	prof := <-profiles
+	require.Contains(t, prof.tags, "trigger:custom")
+	// the allocations are reported since the profiler started
+	assert.Contains(t, prof.event.Attachments, "delta-heap.pprof")
+	assert.Contains(t, prof.event.Attachments, "goroutineswait.pprof")
+	// the periodic CPU profile is disabled, so the trigger captures one
+	assert.Contains(t, prof.event.Attachments, "cpu.pprof")
+	assert.NotEmpty(t, prof.attachments["delta-heap.pprof"])
+
+	// the trigger keeps being checked, but no other batch is captured
+	// during the cooldown
+	require.Eventually(t, func() bool { return fired.Load() > 5 }, 5*time.Second, 10*time.Millisecond)
+	select {
+	case prof := <-profiles:
+		t.Fatalf("unexpected batch during the cooldown: %v", prof.tags)
+	default:
+	}
+}
+
+func TestWithTriggersNoDeltaProfiles(t *testing.T) {
+	defer func(d time.Duration) { triggerCheckInterval = d }(triggerCheckInterval)
+	triggerCheckInterval = 10 * time.Millisecond
+
+	profiles := startTestProfiler(t, 10,
+		WithProfileTypes(HeapProfile),
+		WithPeriod(time.Hour),
+		WithDeltaProfiles(false),
+		WithTriggers(FuncTrigger("custom", func() bool { return true })),
+		WithTriggerDuration(10*time.Millisecond),
+	)
+
+	prof := <-profiles
+	require.Contains(t, prof.tags, "trigger:custom")
+	assert.Contains(t, prof.event.Attachments, "heap.pprof")
+	assert.NotContains(t, prof.event.Attachments, "delta-heap.pprof")
+}
+
+func TestTriggersConfig(t *testing.T) {
+	trig := FuncTrigger("custom", func() bool { return false })
+
+	p, err := unstartedProfiler(WithTriggers(trig), WithTriggerCooldown(0))
+	require.NoError(t, err)
+	assert.Equal(t, minTriggerCooldown, p.cfg.triggerCooldown)
+	// room is left for the CPU profiles of the triggers
+	assert.Equal(t, DefaultPeriod-DefaultTriggerDuration, p.cfg.cpuDuration)
+
+	p, err = unstartedProfiler(WithTriggers(trig), CPUDuration(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTriggerCooldown, p.cfg.triggerCooldown)
+	assert.Equal(t, 30*time.Second, p.cfg.cpuDuration)
+}
+
+func TestTriggerCPUProfileWaits(t *testing.T) {
+	p, err := unstartedProfiler(WithTriggerDuration(10 * time.Millisecond))
+	require.NoError(t, err)
+
+	// the periodic CPU profile is being collected
+	p.cpuMu.Lock()
+	done := make(chan *profile)
+	go func() {
+		prof, err := triggeredProfiles[CPUProfile](p)
+		assert.NoError(t, err)
+		done <- prof
+	}()
+	select {
+	case <-done:
+		t.Fatal("the CPU profile of the trigger didn't wait for the periodic one")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.cpuMu.Unlock()
+	prof := <-done
+	require.NotNil(t, prof)
+	assert.NotEmpty(t, prof.data)
+}
@@ -73,15 +73,10 @@ type retriableError struct{ err error }
 // Error implements error.
 func (e retriableError) Error() string { return e.err.Error() }
 
-// doRequest makes an HTTP POST request to the Datadog Profiling API with the
-// given profile.
-func (p *profiler) doRequest(bat batch) error {
-	contentType, body, err := encode(bat, p.cfg)
-	if err != nil {
-		return err
-	}
+// uploadContext returns the context of an upload, which is canceled after the
+// upload timeout, or when the profiler is stopped unless it flushes on exit.
+func (p *profiler) uploadContext() (context.Context, context.CancelFunc) {
 	funcExit := make(chan struct{})
-	defer close(funcExit)
 	// uploadTimeout is guaranteed to be >= 0, see newProfiler.
 	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.uploadTimeout)
 	go func() {
@@ -94,6 +89,21 @@ func (p *profiler) doRequest(bat batch) error {
 		}
 		cancel()
 	}()
+	return ctx, func() {
+		close(funcExit)
+		cancel()
+	}
+}
+
+// doRequest makes an HTTP POST request to the Datadog Profiling API with the
+// given profile.
+func (p *profiler) doRequest(bat batch) error {
+	contentType, body, err := encode(bat, p.cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := p.uploadContext()
+	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, "POST", p.cfg.targetURL, body)
 	if err != nil {
 		return err
@@ -154,10 +164,14 @@ type profilerInfo struct {
 	// (env var set via admission controller) or "manual"
 	Activation string         `json:"activation"`
 	Settings   map[string]any `json:"settings"`
+	// LeakSuspects are the call stacks suspected to leak heap memory, see
+	// WithLeakDetection.
+	LeakSuspects []LeakSuspect `json:"leak_suspects,omitempty"`
 }
 
-// encode encodes the profile as a multipart mime request.
-func encode(bat batch, cfg *config) (contentType string, body io.Reader, err error) {
+// newUploadEvent returns the event describing the batch, without its
+// attachments.
+func newUploadEvent(bat batch, cfg *config) *uploadEvent {
 	tags := append(cfg.tags.Slice(),
 		fmt.Sprintf("service:%s", cfg.service),
 		// The profile_seq tag can be used to identify the first profile
@@ -177,10 +191,6 @@ func encode(bat batch, cfg *config) (contentType string, body io.Reader, err err
 		tags = append(tags, fmt.Sprintf("host:%s", bat.host))
 	}
 
-	var buf bytes.Buffer
-
-	mw := multipart.NewWriter(&buf)
-
 	event := &uploadEvent{
 		Version:          "4",
 		Family:           "go",
@@ -207,11 +217,21 @@ func encode(bat batch, cfg *config) (contentType string, body io.Reader, err err
 	} else {
 		event.Info.Profiler.SSI.Mechanism = "none"
 	}
+	event.Info.Profiler.LeakSuspects = bat.leakSuspects
 	event.Info.Profiler.Settings = map[string]any{}
 	for _, tc := range telemetryConfiguration(cfg) {
 		event.Info.Profiler.Settings[tc.Name] = tc.Value
 	}
+	return event
+}
+
+// encode encodes the profile as a multipart mime request.
+func encode(bat batch, cfg *config) (contentType string, body io.Reader, err error) {
+	var buf bytes.Buffer
+
+	mw := multipart.NewWriter(&buf)
 
+	event := newUploadEvent(bat, cfg)
 	for _, p := range bat.profiles {
 		event.Attachments = append(event.Attachments, p.name)
 		f, err := mw.CreateFormFile(p.name, p.name)